@@ -0,0 +1,270 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/auth"
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/client"
+	"github.com/jaredallard/binhost/internal/dpi"
+	"github.com/urfave/cli/v3"
+)
+
+// backend implements the admin commands, either directly against the
+// database (localBackend) or against a binhost server (remoteBackend).
+type backend interface {
+	ListTargets(ctx context.Context) ([]api.Target, error)
+	CreateTarget(ctx context.Context, name string) error
+	DeleteTarget(ctx context.Context, name string, force bool) error
+
+	// ListPackages returns a page of the packages matching q in the
+	// target, or in all targets if target is empty. q.TargetIDs is
+	// ignored.
+	ListPackages(ctx context.Context, target string, q *catalog.SearchQuery) (*api.ListPackagesResponse, error)
+	DeletePackages(ctx context.Context, target, pattern string) ([]api.DeletedPackage, error)
+
+	ListTokens(ctx context.Context) ([]api.Token, error)
+	CreateToken(ctx context.Context, req *api.CreateTokenRequest) (*api.Token, error)
+	RevokeToken(ctx context.Context, id uuid.UUID) error
+
+	// Close releases the resources of the backend.
+	Close() error
+}
+
+// newBackend returns a remoteBackend if --server is set, otherwise a
+// localBackend configured from the environment, like the server.
+func newBackend(ctx context.Context, cmd *cli.Command) (backend, error) {
+	if server := cmd.String("server"); server != "" {
+		c, err := client.New(server, cmd.String("token"))
+		if err != nil {
+			return nil, err
+		}
+		return &remoteBackend{c}, nil
+	}
+
+	return newLocalBackend(ctx)
+}
+
+// newLocalBackend returns a localBackend configured from the
+// environment. Like the server, it requires the database to be at the
+// latest version.
+func newLocalBackend(ctx context.Context) (*localBackend, error) {
+	deps, err := dpi.New(ctx, newLogger())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dependencies: %w", err)
+	}
+
+	m, err := deps.Migrator()
+	if err == nil {
+		err = m.Check(ctx)
+	}
+	if err != nil {
+		deps.DB.Close() //nolint:errcheck // Why: Best effort close, already failed.
+		return nil, err
+	}
+	return &localBackend{deps}, nil
+}
+
+// remoteBackend is a backend that uses the HTTP API of a binhost
+// server.
+type remoteBackend struct {
+	c *client.Client
+}
+
+func (b *remoteBackend) ListTargets(ctx context.Context) ([]api.Target, error) {
+	return b.c.ListTargets(ctx)
+}
+
+func (b *remoteBackend) CreateTarget(ctx context.Context, name string) error {
+	return b.c.CreateTarget(ctx, name)
+}
+
+func (b *remoteBackend) DeleteTarget(ctx context.Context, name string, force bool) error {
+	return b.c.DeleteTarget(ctx, name, force)
+}
+
+func (b *remoteBackend) ListPackages(ctx context.Context, target string, q *catalog.SearchQuery) (*api.ListPackagesResponse, error) {
+	v := url.Values{}
+	for param, value := range map[string]string{
+		"category":   q.Category,
+		"name":       q.Name,
+		"repository": q.Repository,
+		"slot":       q.Slot,
+		"keyword":    q.Keyword,
+		"use":        q.Use,
+		"cursor":     q.Cursor,
+	} {
+		if value != "" {
+			v.Set(param, value)
+		}
+	}
+	if !q.BuiltAfter.IsZero() {
+		v.Set("built_after", q.BuiltAfter.Format(time.RFC3339))
+	}
+	if !q.BuiltBefore.IsZero() {
+		v.Set("built_before", q.BuiltBefore.Format(time.RFC3339))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	return b.c.ListPackages(ctx, target, v)
+}
+
+func (b *remoteBackend) DeletePackages(ctx context.Context, target, pattern string) ([]api.DeletedPackage, error) {
+	return b.c.DeletePackages(ctx, target, pattern)
+}
+
+func (b *remoteBackend) ListTokens(ctx context.Context) ([]api.Token, error) {
+	return b.c.ListTokens(ctx)
+}
+
+func (b *remoteBackend) CreateToken(ctx context.Context, req *api.CreateTokenRequest) (*api.Token, error) {
+	return b.c.CreateToken(ctx, req)
+}
+
+func (b *remoteBackend) RevokeToken(ctx context.Context, id uuid.UUID) error {
+	return b.c.RevokeToken(ctx, id)
+}
+
+func (b *remoteBackend) Close() error {
+	return nil
+}
+
+// localBackend is a backend that uses the database and object storage
+// directly.
+type localBackend struct {
+	deps *dpi.Dependencies
+}
+
+func (b *localBackend) ListTargets(ctx context.Context) ([]api.Target, error) {
+	targets, stats, err := catalog.ListTargets(ctx, b.deps.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]api.Target, 0, len(targets))
+	for _, t := range targets {
+		resp = append(resp, api.NewTarget(t, stats[t.ID]))
+	}
+	return resp, nil
+}
+
+func (b *localBackend) CreateTarget(ctx context.Context, name string) error {
+	_, err := catalog.CreateTarget(ctx, b.deps.DB, name)
+	return err
+}
+
+func (b *localBackend) DeleteTarget(ctx context.Context, name string, force bool) error {
+	t, err := catalog.LookupTarget(ctx, b.deps.DB, name)
+	if err != nil {
+		return err
+	}
+
+	if err := catalog.DeleteTarget(ctx, b.deps.DB, b.deps.Storage, b.deps.Log, t, force); err != nil {
+		if errors.Is(err, catalog.ErrTargetNotEmpty) {
+			return fmt.Errorf("%w, use --force to delete it and its packages", err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (b *localBackend) ListPackages(ctx context.Context, name string, q *catalog.SearchQuery) (*api.ListPackagesResponse, error) {
+	q.TargetIDs = nil
+	if name != "" {
+		t, err := catalog.LookupTarget(ctx, b.deps.DB, name)
+		if err != nil {
+			return nil, err
+		}
+		q.TargetIDs = []uuid.UUID{t.ID}
+	}
+
+	res, err := catalog.Search(ctx, b.deps.DB, q)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := api.NewListPackagesResponse(res)
+	return &resp, nil
+}
+
+func (b *localBackend) DeletePackages(ctx context.Context, name, pattern string) ([]api.DeletedPackage, error) {
+	t, err := catalog.LookupTarget(ctx, b.deps.DB, name)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := catalog.DeleteMatching(ctx, b.deps.DB, b.deps.Storage, b.deps.Log, t, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.NewDeletedPackages(deleted), nil
+}
+
+func (b *localBackend) ListTokens(ctx context.Context) ([]api.Token, error) {
+	tokens, err := auth.ListTokens(ctx, b.deps.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]api.Token, 0, len(tokens))
+	for _, t := range tokens {
+		resp = append(resp, api.NewToken(t))
+	}
+	return resp, nil
+}
+
+func (b *localBackend) CreateToken(ctx context.Context, req *api.CreateTokenRequest) (*api.Token, error) {
+	scopes, err := auth.ParseScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := catalog.LookupTargets(ctx, b.deps.DB, req.Targets)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, t, err := auth.CreateToken(ctx, b.deps.DB, req.Name, scopes, targets, req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := api.NewToken(t)
+	resp.Token = tok
+	return &resp, nil
+}
+
+func (b *localBackend) RevokeToken(ctx context.Context, id uuid.UUID) error {
+	return auth.RevokeToken(ctx, b.deps.DB, id)
+}
+
+func (b *localBackend) Close() error {
+	return b.deps.DB.Close()
+}
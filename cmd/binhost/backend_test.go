@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/auth"
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/config"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/dpi"
+	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/packages/packagestest"
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+// newTestBackend returns a localBackend backed by an empty database and
+// filesystem storage.
+func newTestBackend(t *testing.T) *localBackend {
+	return &localBackend{&dpi.Dependencies{
+		DB:      dbtest.Open(t),
+		Storage: storage.NewFS(t.TempDir()),
+		Conf:    &config.Config{},
+		Log:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}}
+}
+
+// addTestPackage adds an XPAK package for the provided CPV to the
+// target with the provided name.
+func addTestPackage(t *testing.T, b *localBackend, targetName, category, pf string) {
+	ctx := context.Background()
+	tgt, err := catalog.LookupTarget(ctx, b.deps.DB, targetName)
+	assert.NilError(t, err)
+
+	binpkg, err := packages.New(bytes.NewReader(packagestest.BuildXpak([]byte(pf), map[string]string{
+		"CATEGORY": category + "\n", "PF": pf + "\n",
+	})))
+	assert.NilError(t, err)
+	defer binpkg.Delete() //nolint:errcheck // Why: Best effort.
+
+	_, err = catalog.AddPackage(ctx, b.deps.DB, b.deps.Storage, tgt, binpkg)
+	assert.NilError(t, err)
+}
+
+func TestLocalBackendTargets(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBackend(t)
+
+	assert.NilError(t, b.CreateTarget(ctx, "arm64"))
+	assert.NilError(t, b.CreateTarget(ctx, "amd64"))
+	assert.ErrorIs(t, b.CreateTarget(ctx, "amd64"), catalog.ErrTargetExists)
+	addTestPackage(t, b, "amd64", "app-misc", "foo-1.0")
+
+	targets, err := b.ListTargets(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(targets))
+	assert.Equal(t, "amd64", targets[0].Name)
+	assert.Equal(t, 1, targets[0].Packages)
+	assert.Equal(t, "arm64", targets[1].Name)
+	assert.Equal(t, 0, targets[1].Packages)
+
+	assert.ErrorIs(t, b.DeleteTarget(ctx, "missing", false), catalog.ErrTargetNotFound)
+	err = b.DeleteTarget(ctx, "amd64", false)
+	assert.ErrorIs(t, err, catalog.ErrTargetNotEmpty)
+	assert.ErrorContains(t, err, "use --force")
+	assert.NilError(t, b.DeleteTarget(ctx, "amd64", true))
+
+	targets, err = b.ListTargets(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(targets))
+}
+
+func TestLocalBackendPackages(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBackend(t)
+	assert.NilError(t, b.CreateTarget(ctx, "amd64"))
+	assert.NilError(t, b.CreateTarget(ctx, "arm64"))
+	addTestPackage(t, b, "amd64", "app-misc", "foo-1.0")
+	addTestPackage(t, b, "amd64", "app-misc", "bar-1.0")
+	addTestPackage(t, b, "arm64", "app-misc", "foo-1.0")
+
+	resp, err := b.ListPackages(ctx, "amd64", &catalog.SearchQuery{})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(resp.Packages))
+
+	resp, err = b.ListPackages(ctx, "", &catalog.SearchQuery{Name: "foo"})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(resp.Packages))
+
+	_, err = b.ListPackages(ctx, "missing", &catalog.SearchQuery{})
+	assert.ErrorIs(t, err, catalog.ErrTargetNotFound)
+
+	deleted, err := b.DeletePackages(ctx, "amd64", "app-misc/foo-*")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []api.DeletedPackage{
+		{CPV: "app-misc/foo-1.0", BuildID: 1, Path: "app-misc/foo/foo-1.0-1.xpak"},
+	}, deleted)
+
+	resp, err = b.ListPackages(ctx, "", &catalog.SearchQuery{Name: "foo"})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(resp.Packages))
+	assert.Equal(t, "arm64", resp.Packages[0].Target)
+}
+
+func TestLocalBackendTokens(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBackend(t)
+	assert.NilError(t, b.CreateTarget(ctx, "amd64"))
+
+	_, err := b.CreateToken(ctx, &api.CreateTokenRequest{Name: "ci", Scopes: []string{"root"}})
+	assert.ErrorContains(t, err, "unknown scope")
+	_, err = b.CreateToken(ctx, &api.CreateTokenRequest{
+		Name: "ci", Scopes: []string{"target:write"}, Targets: []string{"amd64", "missing"},
+	})
+	assert.ErrorIs(t, err, catalog.ErrTargetNotFound)
+
+	// Targets listed more than once are only added once.
+	created, err := b.CreateToken(ctx, &api.CreateTokenRequest{
+		Name: "ci", Scopes: []string{"target:write"}, Targets: []string{"amd64", "amd64"},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"amd64"}, created.Targets)
+
+	p, err := auth.Authenticate(ctx, b.deps.DB, "", created.Token)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []auth.Scope{auth.ScopeTargetWrite}, p.Scopes)
+
+	tokens, err := b.ListTokens(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(tokens))
+	assert.Equal(t, created.ID, tokens[0].ID)
+	assert.DeepEqual(t, []string{"amd64"}, tokens[0].Targets)
+	assert.Equal(t, "", tokens[0].Token)
+
+	assert.NilError(t, b.RevokeToken(ctx, created.ID))
+	assert.ErrorIs(t, b.RevokeToken(ctx, created.ID), auth.ErrTokenNotFound)
+
+	_, err = auth.Authenticate(ctx, b.deps.DB, "", created.Token)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
@@ -13,45 +13,61 @@
 // You should have received a copy of the GNU Affero General Public License
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
-// Package main contains a CLI for starting the binhost server. See the
-// README in the root of the repository for more information.
+// Package main contains the binhost CLI, which runs the binhost server
+// and administers it. See the README in the root of the repository for
+// more information.
 package main
 
 import (
 	"context"
-	"os/signal"
-
+	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 
 	charmlog "github.com/charmbracelet/log"
-
-	_ "github.com/jackc/pgx/v5/stdlib" // Used by ent.
-
-	"github.com/jaredallard/binhost/internal/dpi"
-	"github.com/jaredallard/binhost/internal/server"
+	"github.com/urfave/cli/v3"
 )
 
-// main runs the binhost server.
+// main runs the binhost CLI.
 func main() {
-	handler := charmlog.New(os.Stderr)
-	log := slog.New(handler)
-
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	defer log.Info("shutting down")
-
-	deps, err := dpi.New(ctx, log)
-	if err != nil {
-		log.With("error", err).Error("failed to create dependencies")
-		os.Exit(1)
+	cmd := &cli.Command{
+		Name:  "binhost",
+		Usage: "Serve and administer a Gentoo binhost",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "server",
+				Usage:   "URL of the binhost server to administer, e.g., https://binhost.example.com. If unset, the database is used directly",
+				Sources: cli.EnvVars("BINHOST_SERVER"),
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Usage:   "API token used to authenticate to --server",
+				Sources: cli.EnvVars("BINHOST_TOKEN"),
+			},
+		},
+		Commands: []*cli.Command{
+			serveCommand(),
+			migrateCommand(),
+			targetCommand(),
+			tokenCommand(),
+			packageCommand(),
+			pushCommand(),
+			importCommand(),
+			exportCommand(),
+		},
 	}
-	defer deps.DB.Close()
 
-	log.Info("starting server")
-	if err := server.New(deps).Run(ctx); err != nil {
-		log.With("error", err).Error("failed to run server")
+	if err := cmd.Run(ctx, os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
 		os.Exit(1)
 	}
 }
+
+// newLogger returns the logger used by all commands.
+func newLogger() *slog.Logger {
+	return slog.New(charmlog.New(os.Stderr))
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/urfave/cli/v3"
+)
+
+// exportCommand returns the export command, which writes a target as a
+// static PKGDIR.
+func exportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export a target as a static PKGDIR or a tarball of one",
+		Description: "Writes the Packages index of the target and its packages using the Portage layout, " +
+			"so that any static web server or a file:// binhost can serve it. Packages already present " +
+			"in the output directory are not written again. Uses the database and object storage directly.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "target",
+				Usage:    "Target to export",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "out",
+				Usage:    "Directory to export to, or the file to write the tarball to with --tar (- for stdout)",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "tar",
+				Usage: "Write a tar archive instead of a directory",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.String("server") != "" {
+				return errors.New("export uses the database directly, --server is not supported")
+			}
+
+			b, err := newLocalBackend(ctx)
+			if err != nil {
+				return err
+			}
+			defer b.Close() //nolint:errcheck // Why: Best effort close.
+
+			t, err := catalog.LookupTarget(ctx, b.deps.DB, cmd.String("target"))
+			if err != nil {
+				return err
+			}
+
+			w, finish, err := newExportWriter(cmd.String("out"), cmd.Bool("tar"))
+			if err != nil {
+				return err
+			}
+
+			res, err := catalog.Export(ctx, b.deps.DB, b.deps.Storage, t, w)
+			if ferr := finish(); err == nil {
+				err = ferr
+			}
+			if err != nil {
+				return err
+			}
+
+			// Don't mix the summary into a tarball written to stdout.
+			out := cmd.Root().Writer
+			if cmd.Bool("tar") && cmd.String("out") == "-" {
+				out = os.Stderr
+			}
+			fmt.Fprintf(out, "target %s: exported %d packages, %d written (%s), %d unchanged\n",
+				t.Name, res.Packages, res.Written, humanize.IBytes(uint64(res.Size)), res.Unchanged)
+			return nil
+		},
+	}
+}
+
+// newExportWriter returns the ExportWriter for --out and a function
+// that must be called once the export is done.
+func newExportWriter(out string, tarball bool) (catalog.ExportWriter, func() error, error) {
+	if !tarball {
+		return catalog.NewDirExport(out), func() error { return nil }, nil
+	}
+
+	var f io.WriteCloser = os.Stdout
+	if out != "-" {
+		var err error
+		f, err = os.Create(out)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create tarball: %w", err)
+		}
+	}
+
+	w, finish := catalog.NewTarExport(f)
+	return w, func() error {
+		if err := finish(); err != nil {
+			f.Close() //nolint:errcheck // Why: Best effort close, already failed.
+			return fmt.Errorf("failed to write tarball: %w", err)
+		}
+		return f.Close()
+	}, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/urfave/cli/v3"
+)
+
+// importCommand returns the import command, which imports an existing
+// PKGDIR into a target.
+func importCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import the packages in an existing PKGDIR into a target",
+		ArgsUsage: "PKGDIR",
+		Description: "Walks PKGDIR and adds every package to the target, skipping packages the target " +
+			"already contains. Packages that can't be read fall back to their entry in the Packages " +
+			"file of PKGDIR. Uses the database and object storage directly.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "target",
+				Usage:    "Target to import the packages into",
+				Required: true,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				return fmt.Errorf("expected 1 argument, usage: %s %s", cmd.FullName(), cmd.ArgsUsage)
+			}
+			if cmd.String("server") != "" {
+				return errors.New("import uses the database directly, use push to upload packages to a server")
+			}
+
+			b, err := newLocalBackend(ctx)
+			if err != nil {
+				return err
+			}
+			defer b.Close() //nolint:errcheck // Why: Best effort close.
+
+			t, err := catalog.LookupTarget(ctx, b.deps.DB, cmd.String("target"))
+			if err != nil {
+				return fmt.Errorf("%w, create it using: binhost target create", err)
+			}
+
+			res, err := catalog.Import(ctx, b.deps.DB, b.deps.Storage, t, cmd.Args().First(), b.deps.Log)
+			if err != nil && res == nil {
+				return err
+			}
+
+			for _, path := range res.Conflicts {
+				fmt.Fprintf(os.Stderr, "conflict: %s: target has a different package with the same BUILD_ID\n", path)
+			}
+			printFailures(res.Failed)
+			fmt.Fprintf(cmd.Root().Writer, "target %s: %d imported, %d skipped, %d conflicting, %d failed\n",
+				t.Name, len(res.Imported), len(res.Skipped), len(res.Conflicts), len(res.Failed))
+			if err != nil {
+				return err
+			}
+			if len(res.Failed) != 0 || len(res.Conflicts) != 0 {
+				return fmt.Errorf("failed to import %d package(s)", len(res.Failed)+len(res.Conflicts))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+)
+
+// withBackend returns an action that calls fn with the backend
+// configured by the global flags, after checking that exactly nargs
+// arguments were provided.
+func withBackend(nargs int, fn func(ctx context.Context, cmd *cli.Command, b backend) error) cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != nargs {
+			return fmt.Errorf("expected %d argument(s), got %d, usage: %s %s", nargs, cmd.Args().Len(), cmd.FullName(), cmd.ArgsUsage)
+		}
+
+		b, err := newBackend(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		defer b.Close() //nolint:errcheck // Why: Best effort close.
+
+		return fn(ctx, cmd, b)
+	}
+}
+
+// newTable returns a tabwriter for printing tables to w. It must be
+// flushed once all rows are written.
+func newTable(w io.Writer, header ...any) *tabwriter.Writer {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row(tw, header...)
+	return tw
+}
+
+// row writes a row to the provided table.
+func row(tw *tabwriter.Writer, columns ...any) {
+	for i, c := range columns {
+		if i != 0 {
+			fmt.Fprint(tw, "\t")
+		}
+		fmt.Fprint(tw, c)
+	}
+	fmt.Fprintln(tw)
+}
+
+// printFailures prints the provided errors, keyed by the path of the
+// package that failed, to stderr.
+func printFailures(failed map[string]error) {
+	paths := make([]string, 0, len(failed))
+	for path := range failed {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fmt.Fprintf(os.Stderr, "failed: %s: %v\n", path, failed[path])
+	}
+}
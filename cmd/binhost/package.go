@@ -0,0 +1,114 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/urfave/cli/v3"
+)
+
+// packageCommand returns the package command, which manages the
+// packages in targets.
+func packageCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "package",
+		Usage: "Manage packages",
+		Commands: []*cli.Command{
+			{
+				Name:      "ls",
+				Usage:     "List the packages in a target, or in all targets if no target is provided",
+				ArgsUsage: "[TARGET]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "category", Usage: "Exact category, e.g., app-editors"},
+					&cli.StringFlag{Name: "name", Usage: "Glob pattern (using * and ?) matched against the package name"},
+					&cli.StringFlag{Name: "repository", Usage: "Repository the package was built from, e.g., gentoo"},
+					&cli.StringFlag{Name: "slot", Usage: "Exact SLOT"},
+					&cli.StringFlag{Name: "keyword", Usage: "Keyword the package has, e.g., ~amd64"},
+					&cli.StringFlag{Name: "use", Usage: "USE flag the package was built with"},
+					&cli.TimestampFlag{
+						Name: "built-after", Usage: "Only packages built at or after this RFC 3339 time",
+						Config: cli.TimestampConfig{Layouts: []string{time.RFC3339}},
+					},
+					&cli.TimestampFlag{
+						Name: "built-before", Usage: "Only packages built at or before this RFC 3339 time",
+						Config: cli.TimestampConfig{Layouts: []string{time.RFC3339}},
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					// TARGET is optional.
+					nargs := min(cmd.Args().Len(), 1)
+					return withBackend(nargs, listPackages)(ctx, cmd)
+				},
+			},
+			{
+				Name:      "rm",
+				Usage:     "Delete all packages in a target whose CPV matches a glob pattern, e.g., dev-lang/rust-*",
+				ArgsUsage: "TARGET PATTERN",
+				Action: withBackend(2, func(ctx context.Context, cmd *cli.Command, b backend) error {
+					deleted, err := b.DeletePackages(ctx, cmd.Args().Get(0), cmd.Args().Get(1))
+					if err != nil {
+						return err
+					}
+
+					tw := newTable(cmd.Root().Writer, "CPV", "BUILD_ID", "PATH")
+					for _, p := range deleted {
+						row(tw, p.CPV, p.BuildID, p.Path)
+					}
+					return tw.Flush()
+				}),
+			},
+		},
+	}
+}
+
+// listPackages prints all packages matching the flags of the package
+// ls command, fetching them a page at a time.
+func listPackages(ctx context.Context, cmd *cli.Command, b backend) error {
+	q := &catalog.SearchQuery{
+		Category:    cmd.String("category"),
+		Name:        cmd.String("name"),
+		Repository:  cmd.String("repository"),
+		Slot:        cmd.String("slot"),
+		Keyword:     cmd.String("keyword"),
+		Use:         cmd.String("use"),
+		BuiltAfter:  cmd.Timestamp("built-after"),
+		BuiltBefore: cmd.Timestamp("built-before"),
+		Limit:       catalog.MaxSearchLimit,
+	}
+
+	tw := newTable(cmd.Root().Writer, "TARGET", "CPV", "BUILD_ID", "REPOSITORY", "SIZE", "MODIFIED")
+	for {
+		resp, err := b.ListPackages(ctx, cmd.Args().First(), q)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range resp.Packages {
+			row(tw, p.Target, p.CPV, p.BuildID, p.Repository, humanize.IBytes(uint64(p.Size)), p.ModifiedAt.Format(time.RFC3339))
+		}
+
+		if resp.NextCursor == "" {
+			break
+		}
+		q.Cursor = resp.NextCursor
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jaredallard/binhost/internal/client"
+	"github.com/jaredallard/binhost/internal/push"
+	"github.com/urfave/cli/v3"
+)
+
+// pushCommand returns the push command, which uploads the packages in
+// a local PKGDIR that the server doesn't have yet.
+func pushCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "push",
+		Usage:     "Upload the packages in a PKGDIR that the server doesn't have yet",
+		ArgsUsage: "PKGDIR",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "target",
+				Usage: "Target to push to. Defaults to the CHOST of the Packages file, created by the server if allowed",
+			},
+			&cli.IntFlag{
+				Name:  "jobs",
+				Usage: "Number of packages to upload concurrently",
+				Value: 4,
+			},
+			&cli.IntFlag{
+				Name:  "retries",
+				Usage: "Number of times to retry a failed upload",
+				Value: 3,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only print the packages that would be uploaded",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				return fmt.Errorf("expected 1 argument, usage: %s %s", cmd.FullName(), cmd.ArgsUsage)
+			}
+			if cmd.String("server") == "" {
+				return errors.New("--server is required")
+			}
+
+			c, err := client.New(cmd.String("server"), cmd.String("token"))
+			if err != nil {
+				return err
+			}
+
+			res, err := push.Push(ctx, c, cmd.Args().First(), push.Options{
+				Target:     cmd.String("target"),
+				Jobs:       cmd.Int("jobs"),
+				Retries:    cmd.Int("retries"),
+				RetryDelay: time.Second,
+				DryRun:     cmd.Bool("dry-run"),
+				Log:        newLogger(),
+			})
+			if err != nil {
+				return err
+			}
+
+			return printPushResult(cmd, res)
+		},
+	}
+}
+
+// printPushResult prints the packages that were (or would be, in dry
+// run mode) uploaded and a summary of the push. An error is returned if
+// any package failed.
+func printPushResult(cmd *cli.Command, res *push.Result) error {
+	w := cmd.Root().Writer
+
+	var present, missing int
+	for _, item := range res.Items {
+		switch item.Action {
+		case push.ActionSkip:
+			present++
+		case push.ActionUpload:
+			missing++
+			if cmd.Bool("dry-run") {
+				fmt.Fprintln(w, "would upload", item.FilePath())
+			}
+		}
+	}
+
+	printFailures(res.Failed)
+
+	if cmd.Bool("dry-run") {
+		fmt.Fprintf(w, "target %s: %d to upload, %d already present, %d conflicting\n", res.Target, missing, present, len(res.Failed))
+		return nil
+	}
+
+	fmt.Fprintf(w, "target %s: %d uploaded, %d already present, %d failed\n", res.Target, len(res.Uploaded), present, len(res.Failed))
+	if len(res.Failed) != 0 {
+		return fmt.Errorf("failed to push %d package(s)", len(res.Failed))
+	}
+	return nil
+}
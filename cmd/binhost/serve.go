@@ -0,0 +1,222 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jaredallard/binhost/internal/dpi"
+	"github.com/jaredallard/binhost/internal/migrations"
+	"github.com/jaredallard/binhost/internal/server"
+	"github.com/urfave/cli/v3"
+)
+
+// serveCommand returns the serve command, which runs the binhost
+// server.
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Run the binhost server",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "migrate",
+				Usage:   "Apply pending database migrations before starting",
+				Sources: cli.EnvVars("MIGRATE"),
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			log := newLogger()
+			defer log.Info("shutting down")
+
+			deps, err := dpi.New(ctx, log)
+			if err != nil {
+				return err
+			}
+			defer deps.DB.Close()
+
+			m, err := deps.Migrator()
+			if err != nil {
+				return err
+			}
+			if cmd.Bool("migrate") {
+				if err := migrateUp(ctx, cmd, m, 0); err != nil {
+					return err
+				}
+			}
+
+			// Refuse to run against a schema we don't know.
+			if err := m.Check(ctx); err != nil {
+				if errors.Is(err, migrations.ErrVersionMismatch) {
+					return fmt.Errorf("%w, run: binhost migrate up", err)
+				}
+				return err
+			}
+
+			log.Info("starting server")
+			return server.New(deps).Run(ctx)
+		},
+	}
+}
+
+// migrateCommand returns the migrate command, which manages the
+// version of the database schema.
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Manage the database schema",
+		Commands: []*cli.Command{
+			{
+				Name:      "up",
+				Usage:     "Apply the next N pending migrations, or all of them",
+				ArgsUsage: "[N]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "confirm-data-loss",
+						Usage: "Allow migrations to delete data they can't migrate",
+					},
+				},
+				Action: withMigrator(func(ctx context.Context, cmd *cli.Command, m *migrations.Migrator) error {
+					n, err := countArg(cmd, 0)
+					if err != nil {
+						return err
+					}
+					if cmd.Bool("confirm-data-loss") {
+						m.ConfirmDataLoss()
+					}
+					return migrateUp(ctx, cmd, m, n)
+				}),
+			},
+			{
+				Name:      "down",
+				Usage:     "Revert the last N applied migrations, 1 by default",
+				ArgsUsage: "[N]",
+				Action: withMigrator(func(ctx context.Context, cmd *cli.Command, m *migrations.Migrator) error {
+					n, err := countArg(cmd, 1)
+					if err != nil {
+						return err
+					}
+
+					reverted, err := m.Down(ctx, n)
+					for _, mig := range reverted {
+						fmt.Fprintf(cmd.Root().Writer, "reverted %d_%s\n", mig.Version, mig.Name)
+					}
+					return err
+				}),
+			},
+			{
+				Name:  "status",
+				Usage: "Print the version of the database and the pending migrations",
+				Action: withMigrator(func(ctx context.Context, cmd *cli.Command, m *migrations.Migrator) error {
+					v, err := m.Version(ctx)
+					if err != nil {
+						return err
+					}
+
+					tw := newTable(cmd.Root().Writer, "VERSION", "NAME", "STATUS")
+					for _, mig := range m.Migrations() {
+						status := "pending"
+						if mig.Version <= v {
+							status = "applied"
+						}
+						row(tw, mig.Version, mig.Name, status)
+					}
+					if err := tw.Flush(); err != nil {
+						return err
+					}
+
+					fmt.Fprintf(cmd.Root().Writer, "\ndatabase is at version %d, latest is %d\n", v, m.Latest())
+					return nil
+				}),
+			},
+			{
+				Name:      "force",
+				Usage:     "Set the version of the database without running migrations",
+				ArgsUsage: "VERSION",
+				Action: withMigrator(func(ctx context.Context, cmd *cli.Command, m *migrations.Migrator) error {
+					if cmd.Args().Len() != 1 {
+						return fmt.Errorf("expected 1 argument, usage: %s %s", cmd.FullName(), cmd.ArgsUsage)
+					}
+					v, err := strconv.Atoi(cmd.Args().First())
+					if err != nil {
+						return fmt.Errorf("invalid version: %w", err)
+					}
+
+					if err := m.Force(ctx, v); err != nil {
+						return err
+					}
+
+					fmt.Fprintln(cmd.Root().Writer, "database is at version", v)
+					return nil
+				}),
+			},
+		},
+	}
+}
+
+// withMigrator returns an action that calls fn with a migrator for the
+// database configured in the environment.
+func withMigrator(fn func(ctx context.Context, cmd *cli.Command, m *migrations.Migrator) error) cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		deps, err := dpi.New(ctx, newLogger())
+		if err != nil {
+			return err
+		}
+		defer deps.DB.Close()
+
+		m, err := deps.Migrator()
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, cmd, m)
+	}
+}
+
+// migrateUp applies the next n pending migrations, or all of them if n
+// is 0, and prints them.
+func migrateUp(ctx context.Context, cmd *cli.Command, m *migrations.Migrator, n int) error {
+	applied, err := m.Up(ctx, n)
+	for _, mig := range applied {
+		fmt.Fprintf(cmd.Root().Writer, "applied %d_%s\n", mig.Version, mig.Name)
+	}
+	if err == nil && len(applied) == 0 {
+		fmt.Fprintln(cmd.Root().Writer, "no pending migrations")
+	}
+	if errors.Is(err, migrations.ErrDataLoss) {
+		return fmt.Errorf("%w, confirm using: binhost migrate up --confirm-data-loss", err)
+	}
+	return err
+}
+
+// countArg returns the optional N argument of the up and down
+// commands, or def if it isn't provided.
+func countArg(cmd *cli.Command, def int) (int, error) {
+	switch cmd.Args().Len() {
+	case 0:
+		return def, nil
+	case 1:
+		n, err := strconv.Atoi(cmd.Args().First())
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid number of migrations %q", cmd.Args().First())
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("expected at most 1 argument, usage: %s %s", cmd.FullName(), cmd.ArgsUsage)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/urfave/cli/v3"
+)
+
+// targetCommand returns the target command, which manages targets.
+func targetCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "target",
+		Usage: "Manage targets",
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List targets",
+				Action: withBackend(0, func(ctx context.Context, cmd *cli.Command, b backend) error {
+					targets, err := b.ListTargets(ctx)
+					if err != nil {
+						return err
+					}
+
+					tw := newTable(cmd.Root().Writer, "NAME", "PACKAGES", "SIZE", "PRIVATE", "OWNER", "DESCRIPTION")
+					for _, t := range targets {
+						row(tw, t.Name, t.Packages, humanize.IBytes(uint64(t.Size)), t.Private, t.Owner, t.Description)
+					}
+					return tw.Flush()
+				}),
+			},
+			{
+				Name:      "create",
+				Usage:     "Create a target",
+				ArgsUsage: "NAME",
+				Action: withBackend(1, func(ctx context.Context, cmd *cli.Command, b backend) error {
+					if err := b.CreateTarget(ctx, cmd.Args().First()); err != nil {
+						return err
+					}
+
+					fmt.Fprintln(cmd.Root().Writer, "created target", cmd.Args().First())
+					return nil
+				}),
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete a target",
+				ArgsUsage: "NAME",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Delete the target even if it contains packages, along with its packages",
+					},
+				},
+				Action: withBackend(1, func(ctx context.Context, cmd *cli.Command, b backend) error {
+					if err := b.DeleteTarget(ctx, cmd.Args().First(), cmd.Bool("force")); err != nil {
+						return err
+					}
+
+					fmt.Fprintln(cmd.Root().Writer, "deleted target", cmd.Args().First())
+					return nil
+				}),
+			},
+		},
+	}
+}
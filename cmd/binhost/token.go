@@ -0,0 +1,120 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/urfave/cli/v3"
+)
+
+// tokenCommand returns the token command, which manages API tokens.
+func tokenCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "token",
+		Usage: "Manage API tokens",
+		Commands: []*cli.Command{
+			{
+				Name:      "create",
+				Usage:     "Create a token and print it",
+				ArgsUsage: "NAME",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:     "scope",
+						Usage:    "Scope to grant the token: admin, target:write or target:read",
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:  "target",
+						Usage: "Limit the target scopes of the token to this target. All targets if unset",
+					},
+					&cli.DurationFlag{
+						Name:  "expires-in",
+						Usage: "Duration after which the token expires. Never if unset",
+					},
+				},
+				Action: withBackend(1, func(ctx context.Context, cmd *cli.Command, b backend) error {
+					req := &api.CreateTokenRequest{
+						Name:    cmd.Args().First(),
+						Scopes:  cmd.StringSlice("scope"),
+						Targets: cmd.StringSlice("target"),
+					}
+					if d := cmd.Duration("expires-in"); d > 0 {
+						expiresAt := time.Now().Add(d)
+						req.ExpiresAt = &expiresAt
+					}
+
+					tok, err := b.CreateToken(ctx, req)
+					if err != nil {
+						return err
+					}
+
+					// Only the token is written to stdout, so it can be captured.
+					fmt.Fprintln(os.Stderr, "created token", tok.ID, "(it will not be shown again)")
+					fmt.Fprintln(cmd.Root().Writer, tok.Token)
+					return nil
+				}),
+			},
+			{
+				Name:  "list",
+				Usage: "List tokens",
+				Action: withBackend(0, func(ctx context.Context, cmd *cli.Command, b backend) error {
+					tokens, err := b.ListTokens(ctx)
+					if err != nil {
+						return err
+					}
+
+					tw := newTable(cmd.Root().Writer, "ID", "NAME", "SCOPES", "TARGETS", "EXPIRES")
+					for _, t := range tokens {
+						targets, expires := "*", "never"
+						if len(t.Targets) != 0 {
+							targets = strings.Join(t.Targets, ",")
+						}
+						if t.ExpiresAt != nil {
+							expires = t.ExpiresAt.Format(time.RFC3339)
+						}
+						row(tw, t.ID, t.Name, strings.Join(t.Scopes, ","), targets, expires)
+					}
+					return tw.Flush()
+				}),
+			},
+			{
+				Name:      "revoke",
+				Usage:     "Revoke a token",
+				ArgsUsage: "ID",
+				Action: withBackend(1, func(ctx context.Context, cmd *cli.Command, b backend) error {
+					id, err := uuid.Parse(cmd.Args().First())
+					if err != nil {
+						return fmt.Errorf("invalid token id: %w", err)
+					}
+
+					if err := b.RevokeToken(ctx, id); err != nil {
+						return err
+					}
+
+					fmt.Fprintln(cmd.Root().Writer, "revoked token", id)
+					return nil
+				}),
+			},
+		},
+	}
+}
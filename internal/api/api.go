@@ -0,0 +1,278 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package api contains the types of the requests and responses of the
+// binhost HTTP API, shared by the server and its clients.
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/parser"
+)
+
+// Target is a target returned by the targets endpoints.
+type Target struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Owner       string    `json:"owner"`
+	Private     bool      `json:"private"`
+
+	// Profile is the header of the Packages index of the target.
+	Profile *parser.Profile `json:"profile"`
+	catalog.TargetStats
+}
+
+// NewTarget creates a Target from the provided target and its stats.
+func NewTarget(t *ent.Target, stats catalog.TargetStats) Target {
+	return Target{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Owner:       t.Owner,
+		Private:     t.Private,
+		Profile:     t.Profile,
+		TargetStats: stats,
+	}
+}
+
+// UpdateTargetRequest is the request body for updating a target.
+// Fields that are nil are left unchanged.
+type UpdateTargetRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Owner       *string `json:"owner,omitempty"`
+	Private     *bool   `json:"private,omitempty"`
+
+	// Profile replaces the profile of the target as a whole.
+	Profile *parser.Profile `json:"profile,omitempty"`
+}
+
+// Package is a package returned by the package listing endpoints.
+type Package struct {
+	Target     string    `json:"target"`
+	CPV        string    `json:"cpv"`
+	Category   string    `json:"category"`
+	Name       string    `json:"name"`
+	Version    string    `json:"version"`
+	Revision   string    `json:"revision"`
+	BuildID    int       `json:"build_id"`
+	Repository string    `json:"repository"`
+	Format     string    `json:"format"`
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	SHA1       string    `json:"sha1"`
+	MD5        string    `json:"md5"`
+	BLAKE2B    string    `json:"blake2b"`
+	SHA512     string    `json:"sha512"`
+	ModifiedAt time.Time `json:"modified_at"`
+
+	// Metadata contains the metadata of the package as it appears in
+	// the Packages index.
+	Metadata *PackageMetadata `json:"metadata"`
+}
+
+// PackageMetadata is the metadata of a package. The BUILD_ID and
+// repository are part of [Package] instead.
+type PackageMetadata struct {
+	BuildTime     string   `json:"build_time"`
+	DefinedPhases []string `json:"defined_phases"`
+	EAPI          int      `json:"eapi"`
+	ELibc         string   `json:"elibc"`
+	IUse          string   `json:"iuse"`
+	Use           string   `json:"use"`
+	Keywords      []string `json:"keywords"`
+	Licenses      []string `json:"licenses"`
+	Slot          string   `json:"slot"`
+	BDepend       string   `json:"bdepend"`
+	Depend        string   `json:"depend"`
+	IDepend       string   `json:"idepend"`
+	PDepend       string   `json:"pdepend"`
+	RDepend       string   `json:"rdepend"`
+	Requires      []string `json:"requires"`
+	Restrict      string   `json:"restrict"`
+	Provides      []string `json:"provides"`
+
+	// InstalledSize is the size of the installed package in bytes.
+	InstalledSize int `json:"installed_size"`
+}
+
+// NewPackageMetadata creates a PackageMetadata from the provided
+// metadata. Returns nil if md is nil.
+func NewPackageMetadata(md *parser.PackageCommon) *PackageMetadata {
+	if md == nil {
+		return nil
+	}
+
+	return &PackageMetadata{
+		BuildTime:     md.BuildTime,
+		DefinedPhases: md.DefinedPhases,
+		EAPI:          md.EAPI,
+		ELibc:         md.ELibc,
+		IUse:          md.IUse,
+		Use:           md.Use,
+		Keywords:      md.Keywords,
+		Licenses:      md.Licenses,
+		Slot:          md.Slot,
+		BDepend:       md.BDepends,
+		Depend:        md.Depends,
+		IDepend:       md.IDepend,
+		PDepend:       md.PDepends,
+		RDepend:       md.RDepends,
+		Requires:      md.Requires,
+		Restrict:      md.Restrict,
+		Provides:      md.Provides,
+		InstalledSize: md.Size,
+	}
+}
+
+// NewPackage creates a Package from the provided package. The target
+// of the package must be loaded.
+func NewPackage(p *ent.Pkg) Package {
+	return Package{
+		Target:     p.Edges.Target.Name,
+		CPV:        catalog.CPV(p),
+		Category:   p.Category,
+		Name:       p.Name,
+		Version:    p.Version,
+		Revision:   p.Revision,
+		BuildID:    p.BuildID,
+		Repository: p.Repository,
+		Format:     p.Format.String(),
+		Path:       p.Path,
+		Size:       p.Size,
+		SHA1:       p.Sha1,
+		MD5:        p.Md5,
+		BLAKE2B:    p.Blake2b,
+		SHA512:     p.Sha512,
+		ModifiedAt: p.Mtime,
+		Metadata:   NewPackageMetadata(p.PackageFields),
+	}
+}
+
+// ListPackagesResponse is the response of the package listing
+// endpoints.
+type ListPackagesResponse struct {
+	Packages []Package `json:"packages"`
+
+	// NextCursor is passed as ?cursor= to fetch the next page. Omitted
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewListPackagesResponse creates a ListPackagesResponse from the
+// provided search result.
+func NewListPackagesResponse(res *catalog.SearchResult) ListPackagesResponse {
+	resp := ListPackagesResponse{
+		Packages:   make([]Package, 0, len(res.Packages)),
+		NextCursor: res.NextCursor,
+	}
+	for _, p := range res.Packages {
+		resp.Packages = append(resp.Packages, NewPackage(p))
+	}
+	return resp
+}
+
+// DeletedPackage is a package returned by the package deletion
+// endpoints.
+type DeletedPackage struct {
+	CPV     string `json:"cpv"`
+	BuildID int    `json:"build_id"`
+	Path    string `json:"path"`
+}
+
+// NewDeletedPackages converts the provided packages into
+// DeletedPackages.
+func NewDeletedPackages(pkgs []*ent.Pkg) []DeletedPackage {
+	resp := make([]DeletedPackage, 0, len(pkgs))
+	for _, p := range pkgs {
+		resp = append(resp, DeletedPackage{CPV: catalog.CPV(p), BuildID: p.BuildID, Path: p.Path})
+	}
+	return resp
+}
+
+// UploadResponse is the response of the upload endpoints.
+type UploadResponse struct {
+	// Target is the name of the target the package was uploaded to.
+	Target  string `json:"target"`
+	CPV     string `json:"cpv"`
+	BuildID int    `json:"build_id"`
+	Path    string `json:"path"`
+}
+
+// Token is a token returned by the token endpoints.
+type Token struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	Targets   []string   `json:"targets"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Token is the token itself. Only returned when the token is
+	// created.
+	Token string `json:"token,omitempty"`
+}
+
+// NewToken creates a Token from the provided token. The targets of the
+// token must be loaded.
+func NewToken(t *ent.Token) Token {
+	targets := make([]string, 0, len(t.Edges.Targets))
+	for _, target := range t.Edges.Targets {
+		targets = append(targets, target.Name)
+	}
+
+	return Token{
+		ID:        t.ID,
+		Name:      t.Name,
+		Scopes:    t.Scopes,
+		Targets:   targets,
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+	}
+}
+
+// CreateTokenRequest is the request body for creating a token.
+type CreateTokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+
+	// Targets are the names of the targets that the target scopes of the
+	// token are limited to. All targets if empty.
+	Targets   []string   `json:"targets,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ErrorResponse is the body of all error responses.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes an error in an ErrorResponse.
+type ErrorBody struct {
+	// Code is a stable, machine readable identifier of the error, e.g.,
+	// target_not_found.
+	Code string `json:"code"`
+
+	// Message is a human readable description of the error.
+	Message string `json:"message"`
+
+	// Details contains additional information about the error.
+	Details map[string]any `json:"details,omitempty"`
+}
@@ -18,6 +18,7 @@
 package archive
 
 import (
+	"archive/tar"
 	"fmt"
 	"io"
 	"os"
@@ -60,6 +61,22 @@ type ExtractOptions struct {
 
 // Extract extracts an archive to the provided destination.
 func Extract(opts ExtractOptions, dest string) error {
+	return withExtractor(opts, func(e Extractor, r io.Reader, ext string) error {
+		return e.Extract(r, ext, dest)
+	})
+}
+
+// Walk calls fn for every entry in an archive without extracting it to
+// disk. Options are the same as [Extract].
+func Walk(opts ExtractOptions, fn WalkFunc) error {
+	return withExtractor(opts, func(e Extractor, r io.Reader, ext string) error {
+		return e.Walk(r, ext, fn)
+	})
+}
+
+// withExtractor opens the archive described by opts and calls fn with
+// the extractor that supports it.
+func withExtractor(opts ExtractOptions, fn func(e Extractor, r io.Reader, ext string) error) error {
 	if opts.Reader == nil && opts.Path == "" {
 		return fmt.Errorf("either reader or path must be provided")
 	}
@@ -92,19 +109,27 @@ func Extract(opts ExtractOptions, dest string) error {
 
 	for eext, extractor := range extensions {
 		if ext == eext {
-			return extractor.Extract(opts.Reader, ext, dest)
+			return fn(extractor, opts.Reader, ext)
 		}
 	}
 
 	return fmt.Errorf("unsupported archive extension: %s", ext)
 }
 
+// WalkFunc is called by [Walk] for every entry in an archive. r reads
+// the contents of the entry and is only valid until fn returns.
+type WalkFunc func(h *tar.Header, r io.Reader) error
+
 // Extractor is an interface for extracting archives.
 type Extractor interface {
 	// Extract extracts all files from the provided reader to the
 	// destination.
 	Extract(r io.Reader, ext, dest string) error
 
+	// Walk calls fn for every entry in the archive read from the
+	// provided reader.
+	Walk(r io.Reader, ext string, fn WalkFunc) error
+
 	// Extensions should return a list of supported extensions for this
 	// extractor.
 	Extensions() []string
@@ -20,8 +20,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // _ ensures that tarExtractor implements the Extractor interface.
@@ -31,44 +33,43 @@ var _ Extractor = (&tarExtractor{})
 type tarExtractor struct{}
 
 func (t *tarExtractor) Extensions() []string {
-	return []string{"tar", "tgz", "gz", "xz", "tbz2", "bz2"}
+	return []string{"tar", "tgz", "gz", "xz", "tbz2", "bz2", "zst", "lz4", "lz", "lzma"}
 }
 
+// Extract extracts the archive into dest. Symlinks and hardlinks are
+// supported, but entries are never allowed to be written outside of
+// dest, either through their name or through a previously extracted
+// symlink.
 func (t *tarExtractor) Extract(r io.Reader, ext, dest string) error {
-	var container io.ReadCloser
-	switch ext {
-	case "tar":
-		container = io.NopCloser(r)
-	case "tgz", "gz":
-		var err error
-		container, err = newGzipReader(r)
-		if err != nil {
-			return fmt.Errorf("failed to create gzip reader: %w", err)
-		}
-	case "tbz2", "bz2":
-		container = newBzip2Reader(r)
-	case "xz":
-		container = newXZReader(r)
-	default:
-		// This only happens if we're missing a case in the switch statement.
-		return fmt.Errorf("unsupported tar extension: %s", ext)
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return fmt.Errorf("failed to create destination: %w", err)
 	}
-	defer container.Close()
 
-	tr := tar.NewReader(container)
-	for {
-		h, err := tr.Next()
+	// Resolve dest so that containment checks work when dest itself is,
+	// or is inside of, a symlink.
+	root, err := filepath.EvalSymlinks(dest)
+	if err != nil {
+		return fmt.Errorf("failed to resolve destination: %w", err)
+	}
+
+	return t.Walk(r, ext, func(h *tar.Header, r io.Reader) error {
+		path, err := securePath(root, h.Name)
 		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
+			return err
+		}
 
-			return fmt.Errorf("failed to read tar header: %w", err)
+		// Ensure that nothing we're about to write through (e.g., a
+		// symlink extracted earlier) points outside of dest.
+		if err := ensureContained(root, filepath.Dir(path)); err != nil {
+			return fmt.Errorf("refusing to extract %s: %w", h.Name, err)
 		}
 
-		path := filepath.Join(dest, h.Name)
 		switch h.Typeflag {
 		case tar.TypeDir:
+			if err := ensureContained(root, path); err != nil {
+				return fmt.Errorf("refusing to extract %s: %w", h.Name, err)
+			}
+
 			if err := os.MkdirAll(path, h.FileInfo().Mode()); err != nil {
 				return fmt.Errorf("failed to create directory: %w", err)
 			}
@@ -78,12 +79,16 @@ func (t *tarExtractor) Extract(r io.Reader, ext, dest string) error {
 				return fmt.Errorf("failed to create directory: %w", err)
 			}
 
-			f, err := os.Create(path)
+			if err := removeExisting(path); err != nil {
+				return err
+			}
+
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
 			if err != nil {
 				return fmt.Errorf("failed to create file: %w", err)
 			}
 
-			if _, err := io.Copy(f, tr); err != nil {
+			if _, err := io.Copy(f, r); err != nil {
 				_ = f.Close() //nolint:errcheck // Why: Best effort to close the file.
 				return fmt.Errorf("failed to copy file contents: %w", err)
 			}
@@ -91,6 +96,50 @@ func (t *tarExtractor) Extract(r io.Reader, ext, dest string) error {
 			if err := f.Close(); err != nil {
 				return fmt.Errorf("failed to close file: %w", err)
 			}
+		case tar.TypeSymlink:
+			if err := checkSymlink(root, path, h.Linkname); err != nil {
+				return fmt.Errorf("refusing to extract %s: %w", h.Name, err)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+
+			if err := removeExisting(path); err != nil {
+				return err
+			}
+
+			if err := os.Symlink(h.Linkname, path); err != nil {
+				return fmt.Errorf("failed to create symlink: %w", err)
+			}
+
+			// Permissions and times of symlinks aren't meaningful, and
+			// setting them would follow the link.
+			return nil
+		case tar.TypeLink:
+			target, err := securePath(root, h.Linkname)
+			if err != nil {
+				return fmt.Errorf("refusing to extract %s: %w", h.Name, err)
+			}
+
+			if err := ensureContained(root, filepath.Dir(target)); err != nil {
+				return fmt.Errorf("refusing to extract %s: %w", h.Name, err)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+
+			if err := removeExisting(path); err != nil {
+				return err
+			}
+
+			if err := os.Link(target, path); err != nil {
+				return fmt.Errorf("failed to create hardlink: %w", err)
+			}
+
+			// The hardlink shares its permissions and times with the target.
+			return nil
 		default:
 			return fmt.Errorf("unsupported file type in package (%s: %v)", h.Name, h.Typeflag)
 		}
@@ -103,8 +152,174 @@ func (t *tarExtractor) Extract(r io.Reader, ext, dest string) error {
 			return fmt.Errorf("failed to set file times: %w", err)
 		}
 
-		// TODO(jaredallard): Symlinks, ownership information, etc...
+		// TODO(jaredallard): Ownership information, etc...
+		return nil
+	})
+}
+
+func (t *tarExtractor) Walk(r io.Reader, ext string, fn WalkFunc) error {
+	container, err := t.decompress(r, ext)
+	if err != nil {
+		return err
+	}
+	defer container.Close()
+
+	tr := tar.NewReader(container)
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		if err := fn(h, tr); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+// decompress returns a reader that decompresses the tar archive read
+// from r based on the provided extension.
+func (t *tarExtractor) decompress(r io.Reader, ext string) (io.ReadCloser, error) {
+	switch ext {
+	case "tar":
+		return io.NopCloser(r), nil
+	case "tgz", "gz":
+		container, err := newGzipReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return container, nil
+	case "tbz2", "bz2":
+		return newBzip2Reader(r), nil
+	case "xz":
+		return newXZReader(r), nil
+	case "zst":
+		container, err := newZstdReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return container, nil
+	case "lz4":
+		return newLz4Reader(r), nil
+	case "lz":
+		container, err := newLzipReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create lzip reader: %w", err)
+		}
+		return container, nil
+	case "lzma":
+		container, err := newLzmaReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create lzma reader: %w", err)
+		}
+		return container, nil
+	default:
+		// This only happens if we're missing a case in the switch statement.
+		return nil, fmt.Errorf("unsupported tar extension: %s", ext)
+	}
+}
+
+// securePath returns the path that the archive entry name should be
+// extracted to inside of root. An error is returned if name is absolute
+// or would escape root.
+func securePath(root, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("refusing to extract absolute path: %s", name)
+	}
+
+	path := filepath.Join(root, name)
+	if !isWithin(root, path) {
+		return "", fmt.Errorf("refusing to extract path outside of destination: %s", name)
+	}
+
+	return path, nil
+}
+
+// checkSymlink ensures that a symlink at path pointing to target does
+// not point outside of root. Absolute targets are treated as being
+// relative to root, since that is where they will point once the
+// extracted tree is used as a root filesystem.
+func checkSymlink(root, path, target string) error {
+	var resolved string
+	if filepath.IsAbs(target) {
+		resolved = filepath.Join(root, target)
+	} else {
+		resolved = filepath.Join(filepath.Dir(path), target)
+	}
+
+	if !isWithin(root, resolved) {
+		return fmt.Errorf("symlink target %s is outside of destination", target)
+	}
+
+	return nil
+}
+
+// ensureContained ensures that the closest existing ancestor of path,
+// including path itself, resolves to a location inside of root after
+// following symlinks.
+func ensureContained(root, path string) error {
+	for {
+		if _, err := os.Lstat(path); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		// Can happen for dangling symlinks, which we can't safely write
+		// through either.
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	if !isWithin(root, resolved) {
+		return fmt.Errorf("%s resolves outside of destination", path)
+	}
+
+	return nil
+}
+
+// removeExisting removes path if it exists and is not a directory, so
+// that it can be replaced without following a symlink in its place.
+func removeExisting(path string) error {
+	inf, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	if inf.IsDir() {
+		return fmt.Errorf("refusing to replace directory %s", path)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove existing file: %w", err)
+	}
+
+	return nil
+}
+
+// isWithin returns true if path is root or is inside of root. Both
+// paths must be clean.
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -21,6 +21,10 @@ import (
 	"io"
 
 	"github.com/jamespfennell/xz"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/sorairolake/lzip-go"
+	"github.com/ulikunitz/xz/lzma"
 )
 
 // newGzipReader creates a new gzip reader from the provided reader.
@@ -37,3 +41,35 @@ func newXZReader(r io.Reader) io.ReadCloser {
 func newBzip2Reader(r io.Reader) io.ReadCloser {
 	return io.NopCloser(bzip2.NewReader(r))
 }
+
+// newZstdReader creates a new zstd reader from the provided reader.
+func newZstdReader(r io.Reader) (io.ReadCloser, error) {
+	d, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
+
+// newLz4Reader creates a new lz4 reader from the provided reader.
+func newLz4Reader(r io.Reader) io.ReadCloser {
+	return io.NopCloser(lz4.NewReader(r))
+}
+
+// newLzipReader creates a new lzip reader from the provided reader.
+func newLzipReader(r io.Reader) (io.ReadCloser, error) {
+	lr, err := lzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(lr), nil
+}
+
+// newLzmaReader creates a new lzma reader from the provided reader.
+func newLzmaReader(r io.Reader) (io.ReadCloser, error) {
+	lr, err := lzma.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(lr), nil
+}
@@ -0,0 +1,207 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/archive"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/sorairolake/lzip-go"
+	"github.com/ulikunitz/xz/lzma"
+	"gotest.tools/v3/assert"
+)
+
+// entry is a tar entry used to build test archives.
+type entry struct {
+	name     string
+	typeflag byte
+	linkname string
+	body     string
+}
+
+// buildTar creates a tar archive containing the provided entries.
+func buildTar(t *testing.T, entries ...entry) *bytes.Buffer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		mode := int64(0o644)
+		if e.typeflag == tar.TypeDir {
+			mode = 0o755
+		}
+
+		assert.NilError(t, tw.WriteHeader(&tar.Header{
+			Name:     e.name,
+			Typeflag: e.typeflag,
+			Linkname: e.linkname,
+			Mode:     mode,
+			Size:     int64(len(e.body)),
+		}))
+		_, err := tw.Write([]byte(e.body))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return &buf
+}
+
+// extract extracts the provided entries into a new directory inside of
+// a parent directory, which is used to detect files written outside of
+// the destination.
+func extract(t *testing.T, entries ...entry) (parent, dest string, err error) {
+	parent = t.TempDir()
+	dest = filepath.Join(parent, "dest")
+	err = archive.Extract(archive.ExtractOptions{
+		Reader:    buildTar(t, entries...),
+		Extension: "tar",
+	}, dest)
+	return parent, dest, err
+}
+
+func TestExtractsLinks(t *testing.T) {
+	_, dest, err := extract(t,
+		entry{name: "usr/lib/libfoo.so.1", typeflag: tar.TypeReg, body: "foo"},
+		entry{name: "usr/lib/libfoo.so", typeflag: tar.TypeSymlink, linkname: "libfoo.so.1"},
+		entry{name: "usr/lib64", typeflag: tar.TypeSymlink, linkname: "/usr/lib"},
+		entry{name: "usr/bin/foo", typeflag: tar.TypeLink, linkname: "usr/lib/libfoo.so.1"},
+	)
+	assert.NilError(t, err)
+
+	target, err := os.Readlink(filepath.Join(dest, "usr/lib/libfoo.so"))
+	assert.NilError(t, err)
+	assert.Equal(t, "libfoo.so.1", target)
+
+	b, err := os.ReadFile(filepath.Join(dest, "usr/lib/libfoo.so"))
+	assert.NilError(t, err)
+	assert.Equal(t, "foo", string(b))
+
+	target, err = os.Readlink(filepath.Join(dest, "usr/lib64"))
+	assert.NilError(t, err)
+	assert.Equal(t, "/usr/lib", target)
+
+	b, err = os.ReadFile(filepath.Join(dest, "usr/bin/foo"))
+	assert.NilError(t, err)
+	assert.Equal(t, "foo", string(b))
+}
+
+func TestRejectsMaliciousArchives(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []entry
+	}{
+		{
+			name:    "parent traversal",
+			entries: []entry{{name: "../evil", typeflag: tar.TypeReg, body: "evil"}},
+		},
+		{
+			name:    "nested parent traversal",
+			entries: []entry{{name: "usr/../../evil", typeflag: tar.TypeReg, body: "evil"}},
+		},
+		{
+			name:    "absolute path",
+			entries: []entry{{name: "/evil", typeflag: tar.TypeReg, body: "evil"}},
+		},
+		{
+			name:    "symlink escape",
+			entries: []entry{{name: "link", typeflag: tar.TypeSymlink, linkname: "../"}},
+		},
+		{
+			name: "write through absolute symlink",
+			entries: []entry{
+				{name: "link", typeflag: tar.TypeSymlink, linkname: "/tmp"},
+				{name: "link/evil", typeflag: tar.TypeReg, body: "evil"},
+			},
+		},
+		{
+			name: "write through symlinked directory",
+			entries: []entry{
+				{name: "dir", typeflag: tar.TypeDir},
+				{name: "dir/link", typeflag: tar.TypeSymlink, linkname: "/"},
+				{name: "dir/link/evil", typeflag: tar.TypeDir},
+			},
+		},
+		{
+			name:    "hardlink escape",
+			entries: []entry{{name: "link", typeflag: tar.TypeLink, linkname: "../outside"}},
+		},
+		{
+			name: "hardlink through symlink",
+			entries: []entry{
+				{name: "link", typeflag: tar.TypeSymlink, linkname: "/etc"},
+				{name: "passwd", typeflag: tar.TypeLink, linkname: "link/passwd"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, _, err := extract(t, tt.entries...)
+			assert.Assert(t, err != nil, "expected extraction to fail")
+
+			// Nothing should have been written next to the destination.
+			_, err = os.Stat(filepath.Join(parent, "evil"))
+			assert.Assert(t, os.IsNotExist(err), "file was written outside of destination")
+		})
+	}
+}
+
+func TestReplacingSymlinkDoesNotFollowIt(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "outside")
+	assert.NilError(t, os.WriteFile(outside, []byte("original"), 0o600))
+
+	_, dest, err := extract(t,
+		// Relative to the destination, so allowed, but it would point to
+		// outside if it was followed from the host.
+		entry{name: "file", typeflag: tar.TypeSymlink, linkname: outside},
+		entry{name: "file", typeflag: tar.TypeReg, body: "replaced"},
+	)
+	assert.NilError(t, err)
+
+	b, err := os.ReadFile(outside)
+	assert.NilError(t, err)
+	assert.Equal(t, "original", string(b))
+
+	b, err = os.ReadFile(filepath.Join(dest, "file"))
+	assert.NilError(t, err)
+	assert.Equal(t, "replaced", string(b))
+}
+
+func TestWalkCompressedArchives(t *testing.T) {
+	tests := []struct {
+		ext      string
+		compress func(w io.Writer) (io.WriteCloser, error)
+	}{
+		{"gz", func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }},
+		{"zst", func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) }},
+		{"lz4", func(w io.Writer) (io.WriteCloser, error) { return lz4.NewWriter(w), nil }},
+		{"lz", func(w io.Writer) (io.WriteCloser, error) { return lzip.NewWriter(w), nil }},
+		{"lzma", func(w io.Writer) (io.WriteCloser, error) { return lzma.NewWriter(w) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			var buf bytes.Buffer
+			cw, err := tt.compress(&buf)
+			assert.NilError(t, err)
+			_, err = io.Copy(cw, buildTar(t, entry{name: "metadata/PF", typeflag: tar.TypeReg, body: "foo-1.0"}))
+			assert.NilError(t, err)
+			assert.NilError(t, cw.Close())
+
+			var names []string
+			assert.NilError(t, archive.Walk(archive.ExtractOptions{
+				Reader:    &buf,
+				Extension: tt.ext,
+			}, func(h *tar.Header, r io.Reader) error {
+				b, err := io.ReadAll(r)
+				assert.NilError(t, err)
+				assert.Equal(t, "foo-1.0", string(b))
+
+				names = append(names, h.Name)
+				return nil
+			}))
+			assert.DeepEqual(t, []string{"metadata/PF"}, names)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package atom implements parsing and comparison of Gentoo package
+// names and versions as described by the Package Manager
+// Specification (PMS).
+//
+// See: https://projects.gentoo.org/pms/latest/pms.html#names-and-versions
+package atom
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Regular expressions for the components of a package as defined by
+// the PMS.
+var (
+	// nameRe matches a package name (PN).
+	nameRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9+_-]*$`)
+
+	// categoryRe matches a category.
+	categoryRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9+_.-]*$`)
+
+	// versionRe matches a version, optionally with a revision.
+	versionRe = regexp.MustCompile(
+		`^([0-9]+(?:\.[0-9]+)*)([a-z]?)((?:_(?:alpha|beta|pre|rc|p)[0-9]*)*)(?:-r([0-9]+))?$`,
+	)
+
+	// suffixRe matches a single version suffix.
+	suffixRe = regexp.MustCompile(`_(alpha|beta|pre|rc|p)([0-9]*)`)
+)
+
+// suffixOrder is the order of version suffixes. A version without a
+// suffix sorts between "rc" and "p".
+var suffixOrder = map[string]int{
+	"alpha": -4,
+	"beta":  -3,
+	"pre":   -2,
+	"rc":    -1,
+	"p":     1,
+}
+
+// Suffix is a version suffix (e.g., _rc1).
+type Suffix struct {
+	// Kind is the kind of suffix without the leading underscore (e.g.,
+	// "rc").
+	Kind string
+
+	// Number is the number following the suffix, if any.
+	Number string
+}
+
+// Version is a package version, including the revision.
+type Version struct {
+	// Numbers are the dot separated numeric components of the version.
+	Numbers []string
+
+	// Letter is the optional letter following the numeric components.
+	Letter string
+
+	// Suffixes are the version suffixes in the order they appeared.
+	Suffixes []Suffix
+
+	// Revision is the revision number without the leading "-r". Empty
+	// if the version has no revision.
+	Revision string
+}
+
+// ParseVersion parses a version (PV or PVR).
+func ParseVersion(s string) (*Version, error) {
+	m := versionRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid version: %q", s)
+	}
+
+	v := &Version{
+		Numbers:  strings.Split(m[1], "."),
+		Letter:   m[2],
+		Revision: m[4],
+	}
+	for _, sm := range suffixRe.FindAllStringSubmatch(m[3], -1) {
+		v.Suffixes = append(v.Suffixes, Suffix{Kind: sm[1], Number: sm[2]})
+	}
+
+	return v, nil
+}
+
+// PV returns the version without the revision.
+func (v *Version) PV() string {
+	var sb strings.Builder
+	sb.WriteString(strings.Join(v.Numbers, "."))
+	sb.WriteString(v.Letter)
+	for _, s := range v.Suffixes {
+		sb.WriteString("_" + s.Kind + s.Number)
+	}
+	return sb.String()
+}
+
+// PR returns the revision of the version (e.g., r1), or an empty
+// string if the version has no revision.
+func (v *Version) PR() string {
+	if v.Revision == "" {
+		return ""
+	}
+	return "r" + v.Revision
+}
+
+// String returns the version including the revision (PVR).
+func (v *Version) String() string {
+	return PVR(v.PV(), v.PR())
+}
+
+// PVR joins a version and revision (e.g., r1) into a PVR. The revision
+// may be empty.
+func PVR(version, revision string) string {
+	if revision == "" {
+		return version
+	}
+	return version + "-" + revision
+}
+
+// ParsePF splits a PF (e.g., foo-bar-1.2.3-r1) into the package name
+// and version.
+func ParsePF(pf string) (string, *Version, error) {
+	// A package name may not end in a hyphen followed by something that
+	// would be a valid version, so the first hyphen that is followed by
+	// a valid version is the separator.
+	for i := 0; i < len(pf); i++ {
+		if pf[i] != '-' {
+			continue
+		}
+
+		name := pf[:i]
+		if !nameRe.MatchString(name) {
+			continue
+		}
+
+		v, err := ParseVersion(pf[i+1:])
+		if err != nil {
+			continue
+		}
+
+		return name, v, nil
+	}
+
+	return "", nil, fmt.Errorf("invalid package name and version: %q", pf)
+}
+
+// ValidCategory returns true if category is a valid category name.
+func ValidCategory(category string) bool {
+	return categoryRe.MatchString(category)
+}
+
+// ParseCPV splits a CPV (e.g., dev-lang/go-1.22.0-r1) into the
+// category, package name and version.
+func ParseCPV(cpv string) (string, string, *Version, error) {
+	category, pf, ok := strings.Cut(cpv, "/")
+	if !ok || !ValidCategory(category) {
+		return "", "", nil, fmt.Errorf("invalid category in %q", cpv)
+	}
+
+	name, v, err := ParsePF(pf)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return category, name, v, nil
+}
+
+// Compare compares two versions using the algorithm described by the
+// PMS. Returns -1 if a < b, 0 if a == b and 1 if a > b.
+func Compare(a, b *Version) int {
+	// The first component is always compared numerically.
+	if c := compareInts(a.Numbers[0], b.Numbers[0]); c != 0 {
+		return c
+	}
+
+	for i := 1; i < len(a.Numbers) && i < len(b.Numbers); i++ {
+		an, bn := a.Numbers[i], b.Numbers[i]
+
+		var c int
+		if strings.HasPrefix(an, "0") || strings.HasPrefix(bn, "0") {
+			// Components with a leading zero are compared as strings with
+			// trailing zeros removed.
+			c = strings.Compare(strings.TrimRight(an, "0"), strings.TrimRight(bn, "0"))
+		} else {
+			c = compareInts(an, bn)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	if c := cmp.Compare(len(a.Numbers), len(b.Numbers)); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(a.Letter, b.Letter); c != 0 {
+		return c
+	}
+
+	for i := 0; i < len(a.Suffixes) || i < len(b.Suffixes); i++ {
+		switch {
+		case i >= len(a.Suffixes):
+			// a has no suffix here, which is greater than anything but _p.
+			if b.Suffixes[i].Kind == "p" {
+				return -1
+			}
+			return 1
+		case i >= len(b.Suffixes):
+			if a.Suffixes[i].Kind == "p" {
+				return 1
+			}
+			return -1
+		}
+
+		as, bs := a.Suffixes[i], b.Suffixes[i]
+		if c := cmp.Compare(suffixOrder[as.Kind], suffixOrder[bs.Kind]); c != 0 {
+			return c
+		}
+		if c := compareInts(as.Number, bs.Number); c != 0 {
+			return c
+		}
+	}
+
+	return compareInts(a.Revision, b.Revision)
+}
+
+// compareInts compares two non-negative decimal integers of arbitrary
+// length. Empty strings are treated as zero.
+func compareInts(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,105 @@
+package atom_test
+
+import (
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/atom"
+	"gotest.tools/v3/assert"
+)
+
+func TestParsePF(t *testing.T) {
+	tests := []struct {
+		pf       string
+		name     string
+		pv       string
+		revision string
+	}{
+		{"foo-1.2.3", "foo", "1.2.3", ""},
+		{"foo-1.2.3-r1", "foo", "1.2.3", "r1"},
+		{"onepassword-cli-0", "onepassword-cli", "0", ""},
+		{"foo-bar-2b_rc1_p3-r12", "foo-bar", "2b_rc1_p3", "r12"},
+		{"gtk+-3.24.41", "gtk+", "3.24.41", ""},
+		{"font-adobe-100dpi-1.0.4", "font-adobe-100dpi", "1.0.4", ""},
+		{"python-3.12.1_p1", "python", "3.12.1_p1", ""},
+		{"foo-1.0_alpha_beta2_pre", "foo", "1.0_alpha_beta2_pre", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pf, func(t *testing.T) {
+			name, v, err := atom.ParsePF(tt.pf)
+			assert.NilError(t, err)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.pv, v.PV())
+			assert.Equal(t, tt.revision, v.PR())
+			assert.Equal(t, tt.pf, name+"-"+v.String())
+		})
+	}
+}
+
+func TestParsePFInvalid(t *testing.T) {
+	for _, pf := range []string{"foo", "foo-", "foo-bar", "foo-1.2.3-r", "-1.0", "foo-1.0_omega"} {
+		_, _, err := atom.ParsePF(pf)
+		assert.Assert(t, err != nil, "expected %q to be invalid", pf)
+	}
+}
+
+func TestParseCPV(t *testing.T) {
+	category, name, v, err := atom.ParseCPV("dev-lang/go-1.22.0-r1")
+	assert.NilError(t, err)
+	assert.Equal(t, "dev-lang", category)
+	assert.Equal(t, "go", name)
+	assert.Equal(t, "1.22.0-r1", v.String())
+}
+
+func TestValidCategory(t *testing.T) {
+	for _, category := range []string{"dev-lang", "app-misc", "virtual", "sys-apps.extra", "_x"} {
+		assert.Assert(t, atom.ValidCategory(category), "expected %q to be valid", category)
+	}
+	for _, category := range []string{"", ".", "..", "../dev-lang", "dev-lang/go", "-foo", ".foo"} {
+		assert.Assert(t, !atom.ValidCategory(category), "expected %q to be invalid", category)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Each version is strictly less than the one after it.
+	ordered := []string{
+		"0.9",
+		"1.0_alpha",
+		"1.0_alpha1",
+		"1.0_beta",
+		"1.0_pre2",
+		"1.0_rc1",
+		"1.0",
+		"1.0-r1",
+		"1.0-r2",
+		"1.0_p1",
+		"1.0a",
+		"1.01",
+		"1.1",
+		"1.2",
+		"1.10",
+		"1.10.0",
+		"2",
+		"10",
+		"99999999999999999999",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, err := atom.ParseVersion(ordered[i])
+		assert.NilError(t, err)
+		b, err := atom.ParseVersion(ordered[i+1])
+		assert.NilError(t, err)
+
+		assert.Equal(t, -1, atom.Compare(a, b), "%s < %s", ordered[i], ordered[i+1])
+		assert.Equal(t, 1, atom.Compare(b, a), "%s > %s", ordered[i+1], ordered[i])
+		assert.Equal(t, 0, atom.Compare(a, a), "%s == %s", ordered[i], ordered[i])
+	}
+}
+
+func TestCompareEqual(t *testing.T) {
+	for _, pair := range [][2]string{{"1.0", "1.0-r0"}, {"1.01", "1.010"}, {"01", "1"}} {
+		a, err := atom.ParseVersion(pair[0])
+		assert.NilError(t, err)
+		b, err := atom.ParseVersion(pair[1])
+		assert.NilError(t, err)
+		assert.Equal(t, 0, atom.Compare(a, b), "%s == %s", pair[0], pair[1])
+	}
+}
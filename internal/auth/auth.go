@@ -0,0 +1,244 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package auth implements authentication and authorization of API
+// tokens for the binhost server.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/token"
+)
+
+// Scope is a permission granted to a token.
+type Scope string
+
+// Contains the supported scopes.
+const (
+	// ScopeAdmin allows everything, including managing targets and
+	// tokens.
+	ScopeAdmin Scope = "admin"
+
+	// ScopeTargetWrite allows uploading and deleting packages in a
+	// target. Implies ScopeTargetRead.
+	ScopeTargetWrite Scope = "target:write"
+
+	// ScopeTargetRead allows reading the packages in a target.
+	ScopeTargetRead Scope = "target:read"
+)
+
+// Scopes contains all of the supported scopes.
+var Scopes = []Scope{ScopeAdmin, ScopeTargetWrite, ScopeTargetRead}
+
+var (
+	// ErrInvalidToken is returned when a token doesn't exist or has
+	// expired.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrTokenNotFound is returned by RevokeToken when the token doesn't
+	// exist.
+	ErrTokenNotFound = errors.New("token not found")
+)
+
+// tokenPrefix is prepended to all generated tokens to make them easy
+// to identify, e.g., by secret scanners.
+const tokenPrefix = "bh_"
+
+// ParseScope parses the provided string into a Scope.
+func ParseScope(s string) (Scope, error) {
+	if !slices.Contains(Scopes, Scope(s)) {
+		return "", fmt.Errorf("unknown scope %q", s)
+	}
+
+	return Scope(s), nil
+}
+
+// ParseScopes parses the provided strings into Scopes.
+func ParseScopes(strs []string) ([]Scope, error) {
+	scopes := make([]Scope, 0, len(strs))
+	for _, s := range strs {
+		scope, err := ParseScope(s)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
+
+// Generate returns a new random token.
+func Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash returns the hash of the provided token that is stored in the
+// database.
+func Hash(tok string) string {
+	sum := sha256.Sum256([]byte(tok))
+	return hex.EncodeToString(sum[:])
+}
+
+// Principal is the caller of an API authenticated by a token.
+type Principal struct {
+	// Name is the name of the token.
+	Name string
+
+	// Scopes are the scopes granted to the token.
+	Scopes []Scope
+
+	// TargetIDs are the targets that the target scopes of the token are
+	// limited to. Nil if they apply to all targets.
+	TargetIDs []uuid.UUID
+}
+
+// HasScope returns true if the principal was granted the provided
+// scope for at least one target.
+func (p *Principal) HasScope(scope Scope) bool {
+	if slices.Contains(p.Scopes, ScopeAdmin) || slices.Contains(p.Scopes, scope) {
+		return true
+	}
+
+	return scope == ScopeTargetRead && slices.Contains(p.Scopes, ScopeTargetWrite)
+}
+
+// Can returns true if the principal was granted the provided scope for
+// the provided target. uuid.Nil is only allowed for principals whose
+// scopes aren't limited to specific targets.
+func (p *Principal) Can(scope Scope, targetID uuid.UUID) bool {
+	if slices.Contains(p.Scopes, ScopeAdmin) {
+		return true
+	}
+	if scope == ScopeAdmin || !p.HasScope(scope) {
+		return false
+	}
+
+	return p.TargetIDs == nil || (targetID != uuid.Nil && slices.Contains(p.TargetIDs, targetID))
+}
+
+// Authenticate returns the Principal for the provided token.
+// ErrInvalidToken is returned if the token doesn't exist or has
+// expired. adminToken, if set, is a static token that is always granted
+// ScopeAdmin. It's used to create the first tokens.
+func Authenticate(ctx context.Context, db *ent.Client, adminToken, tok string) (*Principal, error) {
+	if !strings.HasPrefix(tok, tokenPrefix) {
+		if adminToken != "" && subtle.ConstantTimeCompare([]byte(tok), []byte(adminToken)) == 1 {
+			return &Principal{Name: "admin", Scopes: []Scope{ScopeAdmin}}, nil
+		}
+
+		return nil, ErrInvalidToken
+	}
+
+	t, err := db.Token.Query().Where(token.HashEQ(Hash(tok))).WithTargets().Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("failed to query token: %w", err)
+	}
+	if t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	p := &Principal{Name: t.Name}
+	for _, s := range t.Scopes {
+		p.Scopes = append(p.Scopes, Scope(s))
+	}
+	for _, target := range t.Edges.Targets {
+		p.TargetIDs = append(p.TargetIDs, target.ID)
+	}
+
+	return p, nil
+}
+
+// CreateToken creates a new token with the provided scopes. The target
+// scopes of the token are limited to the provided targets, if any.
+// expiresAt may be nil for tokens that don't expire. The token is
+// returned along with its entity, it can't be retrieved again later.
+func CreateToken(
+	ctx context.Context, db *ent.Client, name string, scopes []Scope, targets []*ent.Target, expiresAt *time.Time,
+) (string, *ent.Token, error) {
+	if len(scopes) == 0 {
+		return "", nil, fmt.Errorf("at least one scope is required")
+	}
+
+	tok, err := Generate()
+	if err != nil {
+		return "", nil, err
+	}
+
+	strScopes := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		strScopes = append(strScopes, string(s))
+	}
+
+	t, err := db.Token.Create().
+		SetName(name).
+		SetHash(Hash(tok)).
+		SetScopes(strScopes).
+		AddTargets(targets...).
+		SetNillableExpiresAt(expiresAt).
+		Save(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create token: %w", err)
+	}
+	t.Edges.Targets = targets
+
+	return tok, t, nil
+}
+
+// ListTokens returns all tokens, oldest first, with their targets
+// loaded.
+func ListTokens(ctx context.Context, db *ent.Client) ([]*ent.Token, error) {
+	tokens, err := db.Token.Query().WithTargets().Order(token.ByCreatedAt()).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed querying tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// RevokeToken revokes (deletes) the token with the provided ID.
+// ErrTokenNotFound is returned if it doesn't exist.
+func RevokeToken(ctx context.Context, db *ent.Client, id uuid.UUID) error {
+	if err := db.Token.DeleteOneID(id).Exec(ctx); err != nil {
+		if ent.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrTokenNotFound, id)
+		}
+
+		return fmt.Errorf("failed deleting token: %w", err)
+	}
+
+	return nil
+}
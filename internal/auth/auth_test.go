@@ -0,0 +1,60 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/auth"
+	"gotest.tools/v3/assert"
+)
+
+func TestGeneratedTokensAreUnique(t *testing.T) {
+	a, err := auth.Generate()
+	assert.NilError(t, err)
+	b, err := auth.Generate()
+	assert.NilError(t, err)
+
+	assert.Assert(t, strings.HasPrefix(a, "bh_"))
+	assert.Assert(t, a != b)
+	assert.Assert(t, auth.Hash(a) != auth.Hash(b))
+	assert.Equal(t, auth.Hash(a), auth.Hash(a))
+}
+
+func TestPrincipalCan(t *testing.T) {
+	allowed, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		scope     auth.Scope
+		target    uuid.UUID
+		want      bool
+	}{
+		{"admin can do anything", auth.Principal{Scopes: []auth.Scope{auth.ScopeAdmin}}, auth.ScopeAdmin, uuid.Nil, true},
+		{"admin can write any target", auth.Principal{Scopes: []auth.Scope{auth.ScopeAdmin}}, auth.ScopeTargetWrite, other, true},
+		{"write can't admin", auth.Principal{Scopes: []auth.Scope{auth.ScopeTargetWrite}}, auth.ScopeAdmin, uuid.Nil, false},
+		{"write implies read", auth.Principal{Scopes: []auth.Scope{auth.ScopeTargetWrite}}, auth.ScopeTargetRead, other, true},
+		{"read can't write", auth.Principal{Scopes: []auth.Scope{auth.ScopeTargetRead}}, auth.ScopeTargetWrite, other, false},
+		{
+			"limited to allowed target",
+			auth.Principal{Scopes: []auth.Scope{auth.ScopeTargetWrite}, TargetIDs: []uuid.UUID{allowed}},
+			auth.ScopeTargetWrite, allowed, true,
+		},
+		{
+			"limited to other target",
+			auth.Principal{Scopes: []auth.Scope{auth.ScopeTargetWrite}, TargetIDs: []uuid.UUID{allowed}},
+			auth.ScopeTargetWrite, other, false,
+		},
+		{
+			"limited to unknown target",
+			auth.Principal{Scopes: []auth.Scope{auth.ScopeTargetWrite}, TargetIDs: []uuid.UUID{allowed}},
+			auth.ScopeTargetWrite, uuid.Nil, false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.principal.Can(tt.scope, tt.target))
+		})
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jaredallard/binhost/internal/atom"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/storage"
+)
+
+var (
+	// ErrPackageExists is returned by AddPackage when the target already
+	// contains the same build of the package.
+	ErrPackageExists = errors.New("package already exists")
+
+	// ErrInvalidBuildID is returned by AddPackage when the BUILD_ID of
+	// the package isn't a positive integer.
+	ErrInvalidBuildID = errors.New("invalid BUILD_ID")
+)
+
+// ContentTypes contains the content type that package archives are
+// stored and served with for each package format.
+var ContentTypes = map[pkg.Format]string{
+	pkg.FormatGpkg: "application/x-tar",
+	pkg.FormatXpak: "application/octet-stream",
+}
+
+// maxBuildIDAttempts is the number of times AddPackage tries to add a
+// package that it assigns a BUILD_ID to.
+const maxBuildIDAttempts = 5
+
+// AddPackage adds the provided package to the target and stores its
+// archive. The package only becomes visible once its archive has been
+// stored. The BUILD_ID from the package is used if it has one,
+// otherwise the next unused one for its version is assigned.
+//
+// The archive is stored before the package is added, so that the
+// transaction adding it doesn't lock the target while a (potentially
+// large) archive is being stored. If adding the package fails, the
+// archive is deleted again.
+func AddPackage(ctx context.Context, db *ent.Client, store storage.Storage, t *ent.Target, binpkg *packages.Package) (*ent.Pkg, error) {
+	var buildID int
+	if binpkg.BuildID != "" {
+		var err error
+		buildID, err = strconv.Atoi(binpkg.BuildID)
+		if err != nil || buildID < 1 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidBuildID, binpkg.BuildID)
+		}
+	}
+
+	format := pkg.Format(binpkg.Format)
+	key := NewObjectKey(t, binpkg.Category, binpkg.Name, format)
+	if err := storeArchive(ctx, store, key, binpkg, format); err != nil {
+		return nil, err
+	}
+
+	p, err := insertPackage(ctx, db, t, binpkg, buildID, key)
+	if err != nil {
+		// Nothing references the archive since the package wasn't added.
+		// Delete it even if the request was cancelled.
+		store.Delete(context.WithoutCancel(ctx), key) //nolint:errcheck // Why: Best effort, err is what matters.
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// storeArchive stores the archive of the provided package under key.
+func storeArchive(ctx context.Context, store storage.Storage, key string, binpkg *packages.Package, format pkg.Format) error {
+	f, err := binpkg.Archive()
+	if err != nil {
+		return fmt.Errorf("failed to open package archive: %w", err)
+	}
+	defer f.Close()
+
+	if err := store.Put(ctx, key, f, binpkg.Checksums.Size, ContentTypes[format]); err != nil {
+		return fmt.Errorf("failed to store package archive: %w", err)
+	}
+
+	return nil
+}
+
+// insertPackage adds the provided package, whose archive is stored
+// under key, to the target. If buildID is zero, the next unused
+// BUILD_ID is assigned.
+func insertPackage(ctx context.Context, db *ent.Client, t *ent.Target, binpkg *packages.Package, buildID int, key string) (*ent.Pkg, error) {
+	if buildID != 0 {
+		return insertPackageOnce(ctx, db, t, binpkg, buildID, key)
+	}
+
+	// Concurrent uploads of the same version can be assigned the same
+	// BUILD_ID, in which case all but one of them violate the unique
+	// index. Those are retried with the next unused BUILD_ID.
+	for attempt := 1; ; attempt++ {
+		p, err := insertPackageOnce(ctx, db, t, binpkg, 0, key)
+		if err == nil {
+			return p, nil
+		}
+
+		binpkg.BuildID = ""
+		if !errors.Is(err, ErrPackageExists) || attempt == maxBuildIDAttempts {
+			return nil, err
+		}
+	}
+}
+
+// insertPackageOnce implements insertPackage for a single attempt.
+func insertPackageOnce(ctx context.Context, db *ent.Client, t *ent.Target, binpkg *packages.Package, buildID int, key string) (*ent.Pkg, error) {
+	tx, err := db.Tx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // Why: No-op after commit.
+
+	if buildID == 0 {
+		buildID, err = nextBuildID(ctx, tx, t, &binpkg.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		binpkg.BuildID = strconv.Itoa(buildID)
+	}
+
+	format := pkg.Format(binpkg.Format)
+	p, err := tx.Pkg.Create().
+		SetName(binpkg.Name).
+		SetCategory(binpkg.Category).
+		SetRepository(binpkg.Repo).
+		SetTarget(t).
+		SetVersion(binpkg.Version).
+		SetRevision(binpkg.Revision).
+		SetBuildID(buildID).
+		SetFormat(format).
+		SetPackageFields(&binpkg.PackageCommon).
+		SetPath(PackagePath(binpkg.Category, binpkg.Name, atom.PVR(binpkg.Version, binpkg.Revision), buildID, format)).
+		SetObjectKey(key).
+		SetSize(binpkg.Checksums.Size).
+		SetSha1(binpkg.Checksums.SHA1).
+		SetMd5(binpkg.Checksums.MD5).
+		SetBlake2b(binpkg.Checksums.BLAKE2B).
+		SetSha512(binpkg.Checksums.SHA512).
+		SetMtime(time.Now()).
+		Save(ctx)
+	if err != nil {
+		if ent.IsConstraintError(err) {
+			return nil, ErrPackageExists
+		}
+
+		return nil, fmt.Errorf("failed creating package: %w", err)
+	}
+
+	if err := TouchIndex(ctx, tx.Client(), t); err != nil {
+		return nil, err
+	}
+
+	if err := InitProfile(ctx, tx.Client(), t, InferProfile(&binpkg.Metadata)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit package: %w", err)
+	}
+
+	return p, nil
+}
+
+// nextBuildID returns the next unused BUILD_ID for the version of the
+// provided package in the target.
+func nextBuildID(ctx context.Context, tx *ent.Tx, t *ent.Target, md *packages.Metadata) (int, error) {
+	latest, err := tx.Pkg.Query().
+		Where(
+			pkg.TargetIDEQ(t.ID),
+			pkg.CategoryEQ(md.Category),
+			pkg.NameEQ(md.Name),
+			pkg.VersionEQ(md.Version),
+			pkg.RevisionEQ(md.Revision),
+		).
+		Order(ent.Desc(pkg.FieldBuildID)).
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return 1, nil
+		}
+
+		return 0, fmt.Errorf("failed to query latest build id: %w", err)
+	}
+
+	return latest.BuildID + 1, nil
+}
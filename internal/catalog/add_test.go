@@ -0,0 +1,169 @@
+package catalog_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/hook"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/packages/packagestest"
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+// newTarget creates a target with the provided name.
+func newTarget(t *testing.T, db *ent.Client, name string) *ent.Target {
+	tgt, err := db.Target.Create().SetName(name).Save(context.Background())
+	assert.NilError(t, err)
+	return tgt
+}
+
+// newXpak creates an XPAK package for the provided CPV. If buildID is
+// empty, the package has no BUILD_ID.
+func newXpak(t *testing.T, category, pf, buildID string) *packages.Package {
+	md := map[string]string{
+		"CATEGORY":   category + "\n",
+		"PF":         pf + "\n",
+		"repository": "gentoo\n",
+	}
+	if buildID != "" {
+		md["BUILD_ID"] = buildID + "\n"
+	}
+
+	binpkg, err := packages.New(bytes.NewReader(packagestest.BuildXpak([]byte(category+"/"+pf), md)))
+	assert.NilError(t, err)
+	t.Cleanup(func() { binpkg.Delete() }) //nolint:errcheck // Why: Best effort.
+	return binpkg
+}
+
+// addXpak adds an XPAK package for the provided CPV to the target.
+func addXpak(t *testing.T, db *ent.Client, store storage.Storage, tgt *ent.Target, category, pf, buildID string) *ent.Pkg {
+	p, err := catalog.AddPackage(context.Background(), db, store, tgt, newXpak(t, category, pf, buildID))
+	assert.NilError(t, err)
+	return p
+}
+
+func TestPackagePath(t *testing.T) {
+	assert.Equal(t, "app-misc/foo/foo-1.0-r1-3.gpkg.tar", catalog.PackagePath("app-misc", "foo", "1.0-r1", 3, pkg.FormatGpkg))
+	assert.Equal(t, "dev-lang/go/go-1.22.0-1.xpak", catalog.PackagePath("dev-lang", "go", "1.22.0", 1, pkg.FormatXpak))
+}
+
+func TestAddPackageAssignsBuildIDs(t *testing.T) {
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "test")
+
+	first := addXpak(t, db, store, tgt, "app-misc", "foo-1.0-r1", "")
+	second := addXpak(t, db, store, tgt, "app-misc", "foo-1.0-r1", "")
+	explicit := addXpak(t, db, store, tgt, "app-misc", "foo-1.0-r1", "7")
+	other := addXpak(t, db, store, tgt, "app-misc", "foo-2.0", "")
+
+	assert.Equal(t, 1, first.BuildID)
+	assert.Equal(t, "app-misc/foo/foo-1.0-r1-1.xpak", first.Path)
+	assert.Equal(t, 2, second.BuildID)
+	assert.Equal(t, 7, explicit.BuildID)
+	assert.Equal(t, 1, other.BuildID)
+
+	obj, err := store.Get(context.Background(), second.ObjectKey)
+	assert.NilError(t, err)
+	defer obj.Close()
+	assert.Equal(t, second.Size, obj.Size)
+
+	_, err = catalog.AddPackage(context.Background(), db, store, tgt, newXpak(t, "app-misc", "foo-1.0-r1", "7"))
+	assert.ErrorIs(t, err, catalog.ErrPackageExists)
+}
+
+func TestAddPackageRetriesTakenBuildID(t *testing.T) {
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "test")
+	addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "1")
+
+	// Make the first attempt use a BUILD_ID that is already taken, as
+	// if a concurrent upload added it after it was assigned.
+	attempts := 0
+	db.Pkg.Use(func(next ent.Mutator) ent.Mutator {
+		return hook.PkgFunc(func(ctx context.Context, m *ent.PkgMutation) (ent.Value, error) {
+			attempts++
+			if attempts == 1 {
+				m.SetBuildID(1)
+			}
+			return next.Mutate(ctx, m)
+		})
+	})
+
+	binpkg := newXpak(t, "app-misc", "foo-1.0", "")
+	p, err := catalog.AddPackage(context.Background(), db, store, tgt, binpkg)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 2, p.BuildID)
+	assert.Equal(t, "2", binpkg.BuildID)
+}
+
+// storedFiles returns the number of files in the provided directory
+// and its subdirectories.
+func storedFiles(t *testing.T, dir string) int {
+	var n int
+	assert.NilError(t, filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			n++
+		}
+		return err
+	}))
+	return n
+}
+
+func TestAddPackageDeletesArchiveWhenAddFails(t *testing.T) {
+	db := dbtest.Open(t)
+	dir := t.TempDir()
+	store := storage.NewFS(dir)
+	tgt := newTarget(t, db, "test")
+
+	first := addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "1")
+	_, err := catalog.AddPackage(context.Background(), db, store, tgt, newXpak(t, "app-misc", "foo-1.0", "1"))
+	assert.ErrorIs(t, err, catalog.ErrPackageExists)
+
+	// Only the archive of the first package is left, unchanged.
+	assert.Equal(t, 1, storedFiles(t, dir))
+	obj, err := store.Get(context.Background(), first.ObjectKey)
+	assert.NilError(t, err)
+	defer obj.Close()
+	assert.Equal(t, first.Size, obj.Size)
+}
+
+// writingStorage is a storage.Storage that updates the provided target
+// whenever an object is stored.
+type writingStorage struct {
+	storage.Storage
+	db  *ent.Client
+	tgt *ent.Target
+	err error
+}
+
+// Put implements the storage.Storage interface.
+func (s *writingStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	s.err = s.db.Target.UpdateOne(s.tgt).SetDescription("updated").Exec(ctx)
+
+	return s.Storage.Put(ctx, key, r, size, contentType)
+}
+
+func TestAddPackageDoesNotLockTargetWhileStoring(t *testing.T) {
+	db := dbtest.Open(t)
+	tgt := newTarget(t, db, "test")
+	store := &writingStorage{Storage: storage.NewFS(t.TempDir()), db: db, tgt: tgt}
+
+	_, err := catalog.AddPackage(context.Background(), db, store, tgt, newXpak(t, "app-misc", "foo-1.0", ""))
+	assert.NilError(t, err)
+	assert.NilError(t, store.err)
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package catalog implements operations on the packages stored in a
+// binhost target, such as generating the Portage Packages index for
+// it.
+package catalog
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/atom"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/parser"
+)
+
+// Index generates a Packages index for the provided target from the
+// packages stored in the database.
+func Index(ctx context.Context, db *ent.Client, t *ent.Target) (*parser.Index, error) {
+	pkgs, err := indexPkgs(ctx, db, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return newIndex(t, pkgs), nil
+}
+
+// indexPkgs returns the packages in the provided target in the order of
+// the Packages index.
+func indexPkgs(ctx context.Context, db *ent.Client, t *ent.Target) ([]*ent.Pkg, error) {
+	pkgs, err := db.Pkg.Query().
+		Where(pkg.HasTargetWith(target.IDEQ(t.ID))).
+		Order(pkg.ByCategory(), pkg.ByName()).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed querying packages: %w", err)
+	}
+
+	// Versions can't be sorted by the database, so sort them here.
+	slices.SortStableFunc(pkgs, comparePkgs)
+	return pkgs, nil
+}
+
+// newIndex creates the Packages index of the provided target out of
+// its packages, in the order returned by indexPkgs.
+func newIndex(t *ent.Target, pkgs []*ent.Pkg) *parser.Index {
+	index := &parser.Index{
+		Packages:       len(pkgs),
+		Timestamp:      int(t.IndexUpdatedAt.Unix()),
+		PackageEntries: make([]parser.Package, 0, len(pkgs)),
+	}
+	if t.Profile != nil {
+		index.Profile = *t.Profile
+	}
+	for _, p := range pkgs {
+		index.PackageEntries = append(index.PackageEntries, IndexEntry(p))
+	}
+
+	return index
+}
+
+// IndexEntry converts the provided package into an entry for a
+// Packages index.
+func IndexEntry(p *ent.Pkg) parser.Package {
+	var entry parser.Package
+	if p.PackageFields != nil {
+		entry.PackageCommon = *p.PackageFields
+	}
+	entry.BuildID = strconv.Itoa(p.BuildID)
+
+	entry.CPV = CPV(p)
+	entry.Format = p.Format.String()
+	entry.Path = p.Path
+
+	// SIZE in the index is the size of the archive, not the installed
+	// size from the metadata.
+	entry.Size = int(p.Size)
+	entry.SHA1 = p.Sha1
+	entry.MD5 = p.Md5
+	entry.BLAKE2B = p.Blake2b
+	entry.SHA512 = p.Sha512
+	entry.ModifiedTime = int(p.Mtime.Unix())
+	return entry
+}
+
+// PackagePath returns the path, relative to the root of a target, that
+// a package is served from. version should include the revision, if
+// any (PVR).
+//
+// Paths follow the layout Portage uses for binpkg-multi-instance
+// (category/PN/PF-BUILD_ID.gpkg.tar, or .xpak for XPAK packages) so
+// that multiple builds of the same version can exist in a target.
+func PackagePath(category, name, version string, buildID int, format pkg.Format) string {
+	return category + "/" + name + "/" + name + "-" + version + "-" + strconv.Itoa(buildID) + extension(format)
+}
+
+// NewObjectKey returns a new key to store the archive of a package in
+// the provided target under. Keys are unique for every upload, so that
+// storing an archive never replaces the archive of another package,
+// e.g., one that was assigned the same BUILD_ID concurrently.
+func NewObjectKey(t *ent.Target, category, name string, format pkg.Format) string {
+	return t.ID.String() + "/" + category + "/" + name + "/" + uuid.NewString() + extension(format)
+}
+
+// extension returns the file extension of package archives in the
+// provided format.
+func extension(format pkg.Format) string {
+	if format == pkg.FormatXpak {
+		return ".xpak"
+	}
+	return ".gpkg.tar"
+}
+
+// comparePkgs orders packages by category, name, version and then
+// BUILD_ID.
+func comparePkgs(a, b *ent.Pkg) int {
+	if c := cmp.Compare(a.Category, b.Category); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+
+	av, aerr := atom.ParseVersion(atom.PVR(a.Version, a.Revision))
+	bv, berr := atom.ParseVersion(atom.PVR(b.Version, b.Revision))
+	if aerr != nil || berr != nil {
+		// Shouldn't happen since versions are validated on upload.
+		return cmp.Compare(atom.PVR(a.Version, a.Revision), atom.PVR(b.Version, b.Revision))
+	}
+	if c := atom.Compare(av, bv); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.BuildID, b.BuildID)
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/atom"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/ent/predicate"
+	"github.com/jaredallard/binhost/internal/storage"
+)
+
+// DeletePackages deletes the packages in the target matching all of the
+// provided predicates, along with their archives. The deleted packages
+// are returned. See deletePackages for how failures are handled.
+func DeletePackages(
+	ctx context.Context, db *ent.Client, store storage.Storage, log *slog.Logger, t *ent.Target, preds ...predicate.Pkg,
+) ([]*ent.Pkg, error) {
+	return deletePackages(ctx, db, store, log, t, func(tx *ent.Tx) ([]*ent.Pkg, error) {
+		return tx.Pkg.Query().Where(append(preds, pkg.TargetIDEQ(t.ID))...).All(ctx)
+	})
+}
+
+// ErrInvalidPattern is returned by DeleteMatching when the provided
+// pattern is invalid.
+var ErrInvalidPattern = errors.New("invalid pattern")
+
+// DeleteMatching deletes the packages in the target whose CPV (e.g.,
+// dev-lang/rust-1.75.0) matches the provided glob pattern (e.g.,
+// dev-lang/rust-*), along with their archives. The deleted packages are
+// returned. See deletePackages for how failures are handled.
+func DeleteMatching(
+	ctx context.Context, db *ent.Client, store storage.Storage, log *slog.Logger, t *ent.Target, pattern string,
+) ([]*ent.Pkg, error) {
+	category, _, ok := strings.Cut(pattern, "/")
+	if !ok {
+		return nil, fmt.Errorf("%w: must be in the form category/PF: %s", ErrInvalidPattern, pattern)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidPattern, pattern, err)
+	}
+
+	return deletePackages(ctx, db, store, log, t, func(tx *ent.Tx) ([]*ent.Pkg, error) {
+		query := tx.Pkg.Query().Where(pkg.TargetIDEQ(t.ID))
+
+		// Narrow down the query if the category isn't a pattern.
+		if !strings.ContainsAny(category, `*?[\`) {
+			query = query.Where(pkg.CategoryEQ(category))
+		}
+
+		pkgs, err := query.All(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		matched := make([]*ent.Pkg, 0, len(pkgs))
+		for _, p := range pkgs {
+			if ok, _ := path.Match(pattern, CPV(p)); ok { //nolint:errcheck // Why: Validated above.
+				matched = append(matched, p)
+			}
+		}
+		return matched, nil
+	})
+}
+
+// deletePackages deletes the packages returned by find in a single
+// transaction. Their archives are deleted once the transaction has been
+// committed, so that a package is never listed without its archive.
+// Failing to delete an archive only leaves an unreferenced object behind,
+// which is logged rather than returned.
+func deletePackages(
+	ctx context.Context, db *ent.Client, store storage.Storage, log *slog.Logger, t *ent.Target,
+	find func(tx *ent.Tx) ([]*ent.Pkg, error),
+) ([]*ent.Pkg, error) {
+	tx, err := db.Tx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // Why: No-op after commit.
+
+	pkgs, err := find(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query packages: %w", err)
+	}
+	if len(pkgs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(pkgs))
+	for _, p := range pkgs {
+		ids = append(ids, p.ID)
+	}
+
+	if _, err := tx.Pkg.Delete().Where(pkg.IDIn(ids...)).Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to delete packages: %w", err)
+	}
+
+	if err := TouchIndex(ctx, tx.Client(), t); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit deletion: %w", err)
+	}
+
+	deleteArchives(ctx, store, log, pkgs)
+	return pkgs, nil
+}
+
+// deleteArchives deletes the archives of the provided packages, which
+// must no longer exist in the database. Failures are logged. Deleting an
+// archive that doesn't exist is not an error.
+func deleteArchives(ctx context.Context, store storage.Storage, log *slog.Logger, pkgs []*ent.Pkg) {
+	for _, p := range pkgs {
+		if err := store.Delete(ctx, p.ObjectKey); err != nil {
+			log.Warn("failed to delete package archive", "cpv", CPV(p), "key", p.ObjectKey, "error", err)
+		}
+	}
+}
+
+// TouchIndex marks the Packages index of the target as updated. This
+// should be called whenever packages in the target change.
+func TouchIndex(ctx context.Context, db *ent.Client, t *ent.Target) error {
+	if err := db.Target.UpdateOneID(t.ID).SetIndexUpdatedAt(time.Now()).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to update target index time: %w", err)
+	}
+
+	return nil
+}
+
+// CPV returns the CPV of the provided package (e.g.,
+// dev-lang/go-1.22.0-r1).
+func CPV(p *ent.Pkg) string {
+	return p.Category + "/" + p.Name + "-" + atom.PVR(p.Version, p.Revision)
+}
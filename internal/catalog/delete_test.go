@@ -0,0 +1,116 @@
+package catalog_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+// discardLogger is a logger that discards everything logged to it.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// cpvs returns the CPVs of the provided packages, sorted.
+func cpvs(pkgs []*ent.Pkg) []string {
+	out := make([]string, 0, len(pkgs))
+	for _, p := range pkgs {
+		out = append(out, catalog.CPV(p))
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestCPV(t *testing.T) {
+	assert.Equal(t, "dev-lang/go-1.22.0", catalog.CPV(&ent.Pkg{Category: "dev-lang", Name: "go", Version: "1.22.0"}))
+	assert.Equal(t, "dev-lang/go-1.22.0-r1", catalog.CPV(&ent.Pkg{Category: "dev-lang", Name: "go", Version: "1.22.0", Revision: "r1"}))
+}
+
+func TestDeleteMatching(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "test")
+
+	foo1 := addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "")
+	addXpak(t, db, store, tgt, "app-misc", "foo-2.0-r1", "")
+	addXpak(t, db, store, tgt, "app-misc", "foobar-1.0", "")
+	addXpak(t, db, store, tgt, "dev-lang", "foo-1.5", "")
+
+	deleted, err := catalog.DeleteMatching(ctx, db, store, discardLogger, tgt, "app-misc/foo-*")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"app-misc/foo-1.0", "app-misc/foo-2.0-r1"}, cpvs(deleted))
+
+	// Patterns can match any category.
+	deleted, err = catalog.DeleteMatching(ctx, db, store, discardLogger, tgt, "*/foo-1.?")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"dev-lang/foo-1.5"}, cpvs(deleted))
+
+	remaining, err := db.Pkg.Query().All(ctx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"app-misc/foobar-1.0"}, cpvs(remaining))
+
+	_, err = store.Get(ctx, foo1.ObjectKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Nothing matching isn't an error.
+	deleted, err = catalog.DeleteMatching(ctx, db, store, discardLogger, tgt, "app-misc/baz-*")
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(deleted))
+}
+
+func TestDeleteMatchingRejectsInvalidPatterns(t *testing.T) {
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "test")
+	addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "")
+
+	for _, pattern := range []string{"foo-*", "app-misc/foo-[", `app-misc/foo-\`} {
+		_, err := catalog.DeleteMatching(context.Background(), db, store, discardLogger, tgt, pattern)
+		assert.ErrorIs(t, err, catalog.ErrInvalidPattern, pattern)
+	}
+
+	n, err := db.Pkg.Query().Count(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+// failingDeleteStorage is a [storage.Storage] that fails to delete
+// objects.
+type failingDeleteStorage struct {
+	storage.Storage
+}
+
+// Delete implements the storage.Storage interface.
+func (failingDeleteStorage) Delete(context.Context, string) error {
+	return errors.New("delete failed")
+}
+
+func TestDeletePackagesIgnoresArchiveFailures(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "test")
+	p := addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "")
+
+	deleted, err := catalog.DeletePackages(ctx, db, failingDeleteStorage{store}, discardLogger, tgt, pkg.NameEQ("foo"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"app-misc/foo-1.0"}, cpvs(deleted))
+
+	// The package is deleted even though its archive is left behind.
+	n, err := db.Pkg.Query().Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, n)
+
+	obj, err := store.Get(ctx, p.ObjectKey)
+	assert.NilError(t, err)
+	obj.Close()
+}
@@ -0,0 +1,246 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package catalog
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/parser"
+	"github.com/jaredallard/binhost/internal/storage"
+)
+
+// ErrChecksumMismatch is returned by Export when a package archive in
+// storage doesn't match the BLAKE2B checksum of its package.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ExportWriter is where Export writes the files of a PKGDIR to. Paths
+// are relative to the root of the PKGDIR and use forward slashes.
+type ExportWriter interface {
+	// Exists returns true if the file at path already exists with the
+	// provided size and modification time, in which case it isn't
+	// written again.
+	Exists(path string, size int64, mtime time.Time) (bool, error)
+
+	// WriteFile writes size bytes read from r to the file at path.
+	WriteFile(ctx context.Context, path string, r io.Reader, size int64, mtime time.Time) error
+}
+
+// ExportResult is the result of Export.
+type ExportResult struct {
+	// Packages is the number of packages in the exported index.
+	Packages int
+
+	// Written is the number of package archives that were written.
+	Written int
+
+	// Unchanged is the number of package archives that already existed
+	// and weren't written again.
+	Unchanged int
+
+	// Size is the number of bytes of package archives written.
+	Size int64
+}
+
+// Export writes the target as a PKGDIR that can be served by any static
+// web server or used as a file:// binhost: the package archives at the
+// paths in the index, followed by the Packages index itself. The index
+// is identical to the one served by the server.
+//
+// Package archives are verified against their BLAKE2B checksum while
+// they're written, so that a corrupted archive fails the export instead
+// of ending up in the PKGDIR. Archives that already exist in the
+// PKGDIR (see [ExportWriter.Exists]) aren't read again, so they're not
+// verified.
+func Export(ctx context.Context, db *ent.Client, store storage.Storage, t *ent.Target, w ExportWriter) (*ExportResult, error) {
+	pkgs, err := indexPkgs(ctx, db, t)
+	if err != nil {
+		return nil, err
+	}
+	index := newIndex(t, pkgs)
+
+	// Encode the index first, so that the packages exported are exactly
+	// those in the index.
+	var buf bytes.Buffer
+	if err := index.EncodeInto(&buf); err != nil {
+		return nil, fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	res := &ExportResult{Packages: len(index.PackageEntries)}
+	for i := range index.PackageEntries {
+		entry := &index.PackageEntries[i]
+		size, mtime := int64(entry.Size), time.Unix(int64(entry.ModifiedTime), 0)
+
+		exists, err := w.Exists(entry.Path, size, mtime)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			res.Unchanged++
+			continue
+		}
+
+		if err := exportObject(ctx, store, pkgs[i].ObjectKey, w, entry, size, mtime); err != nil {
+			return nil, err
+		}
+		res.Written++
+		res.Size += size
+	}
+
+	// The index is written last, so that it never references packages
+	// that haven't been written yet.
+	if err := w.WriteFile(ctx, "Packages", &buf, int64(buf.Len()), t.IndexUpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to write index: %w", err)
+	}
+
+	return res, nil
+}
+
+// exportObject writes the object stored under key to the path of the
+// provided index entry.
+func exportObject(
+	ctx context.Context, store storage.Storage, key string, w ExportWriter, entry *parser.Package, size int64, mtime time.Time,
+) error {
+	path := entry.Path
+	obj, err := store.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get package archive %s: %w", path, err)
+	}
+	defer obj.Close()
+
+	if obj.Size != size {
+		return fmt.Errorf("package archive %s is %d bytes, expected %d", path, obj.Size, size)
+	}
+
+	var r io.Reader = obj
+	if entry.BLAKE2B != "" {
+		r = &verifyingReader{r: obj, size: size, want: entry.BLAKE2B, h: packages.NewBlake2b()}
+	}
+
+	if err := w.WriteFile(ctx, path, r, size, mtime); err != nil {
+		return fmt.Errorf("failed to write package archive %s: %w", path, err)
+	}
+	return nil
+}
+
+// verifyingReader is an [io.Reader] that returns ErrChecksumMismatch
+// once size bytes have been read from r if their hash doesn't match
+// want. This happens before r returns io.EOF, so that readers that stop
+// after size bytes still see the error.
+type verifyingReader struct {
+	r    io.Reader
+	size int64
+	want string
+
+	h hash.Hash
+	n int64
+}
+
+// Read implements the io.Reader interface.
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.h.Write(p[:n]) //nolint:errcheck // Why: hash.Hash never returns an error.
+	v.n += int64(n)
+
+	if v.n == v.size && hex.EncodeToString(v.h.Sum(nil)) != v.want {
+		return n, ErrChecksumMismatch
+	}
+	return n, err
+}
+
+// dirExport is an ExportWriter that writes files into a directory.
+type dirExport struct {
+	dir   string
+	files storage.Storage
+}
+
+// NewDirExport creates an ExportWriter that writes files into the
+// provided directory. Files are replaced atomically, so a directory
+// that is being served can be updated in place. Files that aren't part
+// of the export are left alone.
+func NewDirExport(dir string) ExportWriter {
+	return &dirExport{dir, storage.NewFS(dir)}
+}
+
+func (d *dirExport) Exists(path string, size int64, mtime time.Time) (bool, error) {
+	if !filepath.IsLocal(path) {
+		return false, fmt.Errorf("invalid path %q", path)
+	}
+
+	info, err := os.Stat(filepath.Join(d.dir, filepath.FromSlash(path)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return info.Mode().IsRegular() && info.Size() == size && info.ModTime().Equal(mtime), nil
+}
+
+func (d *dirExport) WriteFile(ctx context.Context, path string, r io.Reader, size int64, mtime time.Time) error {
+	if err := d.files.Put(ctx, path, r, size, ""); err != nil {
+		return err
+	}
+
+	return os.Chtimes(filepath.Join(d.dir, filepath.FromSlash(path)), mtime, mtime)
+}
+
+// tarExport is an ExportWriter that writes files into a tar archive.
+type tarExport struct {
+	tw *tar.Writer
+}
+
+// NewTarExport creates an ExportWriter that writes files into a tar
+// archive written to w. The returned function must be called to finish
+// the archive once the export is done.
+func NewTarExport(w io.Writer) (ExportWriter, func() error) {
+	tw := tar.NewWriter(w)
+	return &tarExport{tw}, tw.Close
+}
+
+func (t *tarExport) Exists(string, int64, time.Time) (bool, error) {
+	return false, nil
+}
+
+func (t *tarExport) WriteFile(_ context.Context, path string, r io.Reader, size int64, mtime time.Time) error {
+	if err := t.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     path,
+		Size:     size,
+		Mode:     0o644,
+		ModTime:  mtime,
+		Format:   tar.FormatPAX,
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(t.tw, r)
+	return err
+}
@@ -0,0 +1,151 @@
+package catalog_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+func TestDirExportWritesFiles(t *testing.T) {
+	dir := t.TempDir()
+	w := catalog.NewDirExport(dir)
+	mtime := time.Unix(1700000000, 0)
+
+	exists, err := w.Exists("app-misc/foo/foo-1.0-1.gpkg.tar", 3, mtime)
+	assert.NilError(t, err)
+	assert.Assert(t, !exists)
+
+	err = w.WriteFile(context.Background(), "app-misc/foo/foo-1.0-1.gpkg.tar", strings.NewReader("foo"), 3, mtime)
+	assert.NilError(t, err)
+
+	b, err := os.ReadFile(filepath.Join(dir, "app-misc", "foo", "foo-1.0-1.gpkg.tar"))
+	assert.NilError(t, err)
+	assert.Equal(t, "foo", string(b))
+
+	exists, err = w.Exists("app-misc/foo/foo-1.0-1.gpkg.tar", 3, mtime)
+	assert.NilError(t, err)
+	assert.Assert(t, exists)
+
+	// A different modification time means the package changed.
+	exists, err = w.Exists("app-misc/foo/foo-1.0-1.gpkg.tar", 3, mtime.Add(time.Second))
+	assert.NilError(t, err)
+	assert.Assert(t, !exists)
+}
+
+func TestDirExportRejectsPathTraversal(t *testing.T) {
+	w := catalog.NewDirExport(t.TempDir())
+
+	_, err := w.Exists("../foo.gpkg.tar", 3, time.Now())
+	assert.ErrorContains(t, err, "invalid path")
+
+	err = w.WriteFile(context.Background(), "../foo.gpkg.tar", strings.NewReader("foo"), 3, time.Now())
+	assert.ErrorContains(t, err, "invalid object key")
+}
+
+func TestTarExportWritesArchive(t *testing.T) {
+	var buf bytes.Buffer
+	w, finish := catalog.NewTarExport(&buf)
+	mtime := time.Unix(1700000000, 0)
+
+	err := w.WriteFile(context.Background(), "app-misc/foo/foo-1.0-1.gpkg.tar", strings.NewReader("foo"), 3, mtime)
+	assert.NilError(t, err)
+	err = w.WriteFile(context.Background(), "Packages", strings.NewReader("PACKAGES: 1\n"), 12, mtime)
+	assert.NilError(t, err)
+	assert.NilError(t, finish())
+
+	tr := tar.NewReader(&buf)
+	var names []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		assert.Assert(t, h.ModTime.Equal(mtime))
+		names = append(names, h.Name)
+	}
+	assert.DeepEqual(t, []string{"app-misc/foo/foo-1.0-1.gpkg.tar", "Packages"}, names)
+}
+
+func TestExportWritesTarget(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "test")
+	foo := addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "")
+	addXpak(t, db, store, tgt, "dev-lang", "go-1.22.0", "")
+
+	// Reload the target to get the time its index was last updated.
+	tgt, err := db.Target.Get(ctx, tgt.ID)
+	assert.NilError(t, err)
+
+	dir := t.TempDir()
+	res, err := catalog.Export(ctx, db, store, tgt, catalog.NewDirExport(dir))
+	assert.NilError(t, err)
+	assert.Equal(t, 2, res.Packages)
+	assert.Equal(t, 2, res.Written)
+
+	// The index is identical to the one served by the server.
+	index, err := catalog.Index(ctx, db, tgt)
+	assert.NilError(t, err)
+	var want bytes.Buffer
+	assert.NilError(t, index.EncodeInto(&want))
+
+	got, err := os.ReadFile(filepath.Join(dir, "Packages"))
+	assert.NilError(t, err)
+	assert.Equal(t, want.String(), string(got))
+
+	b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(foo.Path)))
+	assert.NilError(t, err)
+	assert.Equal(t, foo.Size, int64(len(b)))
+
+	// Exporting again only writes the index.
+	res, err = catalog.Export(ctx, db, store, tgt, catalog.NewDirExport(dir))
+	assert.NilError(t, err)
+	assert.Equal(t, 0, res.Written)
+	assert.Equal(t, 2, res.Unchanged)
+}
+
+func TestExportVerifiesChecksums(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "test")
+	p := addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "")
+
+	// Corrupt the archive without changing its size.
+	obj, err := store.Get(ctx, p.ObjectKey)
+	assert.NilError(t, err)
+	b, err := io.ReadAll(obj)
+	assert.NilError(t, err)
+	obj.Close()
+	b[0] ^= 0xff
+	assert.NilError(t, store.Put(ctx, p.ObjectKey, bytes.NewReader(b), int64(len(b)), ""))
+
+	dir := t.TempDir()
+	_, err = catalog.Export(ctx, db, store, tgt, catalog.NewDirExport(dir))
+	assert.ErrorIs(t, err, catalog.ErrChecksumMismatch)
+
+	// Neither the corrupted archive nor the index were written.
+	entries, err := os.ReadDir(filepath.Join(dir, "app-misc", "foo"))
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(entries))
+	_, err = os.Stat(filepath.Join(dir, "Packages"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	var buf bytes.Buffer
+	w, _ := catalog.NewTarExport(&buf)
+	_, err = catalog.Export(ctx, db, store, tgt, w)
+	assert.ErrorIs(t, err, catalog.ErrChecksumMismatch)
+}
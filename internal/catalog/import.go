@@ -0,0 +1,210 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/parser"
+	"github.com/jaredallard/binhost/internal/storage"
+)
+
+// packageExtensions are the extensions of the package archives Import
+// looks for.
+var packageExtensions = []string{".gpkg.tar", ".tbz2", ".xpak"}
+
+// ImportResult is the result of Import. Packages are identified by
+// their path relative to the PKGDIR.
+type ImportResult struct {
+	// Imported are the packages that were added to the target.
+	Imported []*ent.Pkg
+
+	// Skipped are the packages the target already contained.
+	Skipped []string
+
+	// Conflicts are the packages that weren't imported because the
+	// target already contains a different package with the same version
+	// and BUILD_ID.
+	Conflicts []string
+
+	// Failed contains the errors of the packages that couldn't be
+	// imported.
+	Failed map[string]error
+}
+
+// Import adds all packages found in the provided PKGDIR to the target.
+// Packages are read using [packages.New], falling back to their entry
+// in the Packages index of the PKGDIR, if any, when that fails for
+// reasons other than failing to verify the package against its
+// Manifest (see readPackage).
+//
+// Import is idempotent: packages the target already contains, i.e., a
+// package with the same version and checksum, are skipped. Errors of
+// individual packages are reported in the result instead of stopping
+// the import. Packages whose version and BUILD_ID the target already
+// contains with a different checksum are reported as conflicts.
+func Import(ctx context.Context, db *ent.Client, store storage.Storage, t *ent.Target, pkgdir string, log *slog.Logger) (*ImportResult, error) {
+	index, err := readIndex(filepath.Join(pkgdir, "Packages"))
+	if err != nil {
+		log.Warn("ignoring Packages index", "error", err)
+	}
+
+	entries := make(map[string]*parser.Package, len(index.PackageEntries))
+	for i := range index.PackageEntries {
+		e := &index.PackageEntries[i]
+		entries[e.FilePath()] = e
+	}
+
+	var paths []string
+	err = filepath.WalkDir(pkgdir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		for _, ext := range packageExtensions {
+			if d.Type().IsRegular() && strings.HasSuffix(d.Name(), ext) {
+				paths = append(paths, path)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", pkgdir, err)
+	}
+
+	res := &ImportResult{Failed: make(map[string]error)}
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+
+		rel, err := filepath.Rel(pkgdir, path)
+		if err != nil {
+			return res, err
+		}
+		rel = filepath.ToSlash(rel)
+
+		p, err := importPackage(ctx, db, store, t, path, entries[rel], index.CHost, log)
+		switch {
+		case errors.Is(err, ErrPackageExists):
+			log.Warn("target has a different package with the same BUILD_ID", "path", rel)
+			res.Conflicts = append(res.Conflicts, rel)
+		case err != nil:
+			log.Error("failed to import package", "path", rel, "error", err)
+			res.Failed[rel] = err
+		case p == nil:
+			res.Skipped = append(res.Skipped, rel)
+		default:
+			log.Info("imported package", "path", rel, "cpv", CPV(p), "build_id", p.BuildID)
+			res.Imported = append(res.Imported, p)
+		}
+	}
+
+	return res, nil
+}
+
+// readIndex reads the Packages index at path. An empty index is
+// returned if it doesn't exist or can't be read.
+func readIndex(path string) (*parser.Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &parser.Index{}, nil
+		}
+
+		return &parser.Index{}, err
+	}
+	defer f.Close()
+
+	index, err := parser.ParsePackages(f)
+	if err != nil || index == nil {
+		return &parser.Index{}, err
+	}
+	return index, nil
+}
+
+// importPackage adds the package at path to the target, unless the
+// target already contains it, in which case nil is returned. entry is
+// the entry of the package in the Packages index, if any, and chost is
+// the CHOST of that index.
+func importPackage(
+	ctx context.Context, db *ent.Client, store storage.Storage, t *ent.Target,
+	path string, entry *parser.Package, chost string, log *slog.Logger,
+) (*ent.Pkg, error) {
+	binpkg, err := readPackage(path, entry, log)
+	if err != nil {
+		return nil, err
+	}
+	defer binpkg.Delete() //nolint:errcheck // Why: Best effort delete.
+
+	if binpkg.CHost == "" {
+		binpkg.CHost = chost
+	}
+
+	exists, err := db.Pkg.Query().
+		Where(
+			pkg.TargetIDEQ(t.ID),
+			pkg.CategoryEQ(binpkg.Category),
+			pkg.NameEQ(binpkg.Name),
+			pkg.VersionEQ(binpkg.Version),
+			pkg.RevisionEQ(binpkg.Revision),
+			pkg.Blake2bEQ(binpkg.Checksums.BLAKE2B),
+		).
+		Exist(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query package: %w", err)
+	}
+	if exists {
+		return nil, nil
+	}
+
+	return AddPackage(ctx, db, store, t, binpkg)
+}
+
+// readPackage reads the package at path, falling back to the provided
+// Packages index entry if it can't be read. Packages that don't match
+// their Manifest are corrupted or have been tampered with, so there's
+// no fallback for them.
+func readPackage(path string, entry *parser.Package, log *slog.Logger) (*packages.Package, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open package: %w", err)
+	}
+	defer f.Close()
+
+	binpkg, err := packages.New(f)
+	var merr *packages.ManifestError
+	if err == nil || entry == nil || errors.As(err, &merr) {
+		return binpkg, err
+	}
+
+	log.Warn("failed to read package, using Packages index instead", "path", path, "error", err)
+	binpkg, ierr := packages.FromIndex(path, entry)
+	if ierr != nil {
+		return nil, fmt.Errorf("%w (reading it from the Packages index failed too: %w)", err, ierr)
+	}
+	return binpkg, nil
+}
@@ -0,0 +1,163 @@
+package catalog_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/packages/packagestest"
+	"github.com/jaredallard/binhost/internal/parser"
+	"github.com/jaredallard/binhost/internal/storage"
+	"golang.org/x/crypto/blake2b"
+	"gotest.tools/v3/assert"
+)
+
+// writeFile writes b to the path, relative to dir, creating its parent
+// directories.
+func writeFile(t *testing.T, dir, path string, b []byte) {
+	path = filepath.Join(dir, filepath.FromSlash(path))
+	assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NilError(t, os.WriteFile(path, b, 0o644))
+}
+
+// indexEntry returns a Packages index entry for the package archive b
+// with its size and BLAKE2B checksum.
+func indexEntry(cpv, path, buildID string, b []byte) parser.Package {
+	sum := blake2b.Sum512(b)
+	entry := parser.Package{CPV: cpv, Path: path, BLAKE2B: hex.EncodeToString(sum[:])}
+	entry.BuildID = buildID
+	entry.Repo = "gentoo"
+	entry.Size = len(b)
+	return entry
+}
+
+// tamperedGpkg returns a copy of the test gpkg whose image doesn't
+// match its Manifest anymore.
+func tamperedGpkg(t *testing.T) []byte {
+	f, err := os.Open("../packages/testdata/onepassword-cli-0-1.gpkg.tar")
+	assert.NilError(t, err)
+	defer f.Close()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tr := tar.NewReader(f)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NilError(t, err)
+
+		b, err := io.ReadAll(tr)
+		assert.NilError(t, err)
+		if path.Base(h.Name) == "image.tar.xz" {
+			b[len(b)-1] ^= 0xff
+		}
+
+		assert.NilError(t, tw.WriteHeader(h))
+		_, err = tw.Write(b)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+
+	return buf.Bytes()
+}
+
+// sortedKeys returns the keys of m, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "test")
+
+	pkgdir := t.TempDir()
+	foo := func(image string) []byte {
+		return packagestest.BuildXpak([]byte(image), map[string]string{
+			"CATEGORY": "app-misc\n", "PF": "foo-1.0\n", "BUILD_ID": "1\n", "repository": "gentoo\n",
+		})
+	}
+	writeFile(t, pkgdir, "app-misc/foo/foo-1.0-1.xpak", foo("foo"))
+	writeFile(t, pkgdir, "app-misc/bar-1.0.tbz2", packagestest.BuildXpak([]byte("bar"), map[string]string{
+		"CATEGORY": "app-misc\n", "PF": "bar-1.0\n", "repository": "gentoo\n",
+	}))
+
+	// Packages that can't be read are imported using the Packages index,
+	// unless they aren't listed in it, don't match the checksums in it,
+	// or don't match their Manifest.
+	notAPackage := []byte("not a package")
+	writeFile(t, pkgdir, "app-misc/baz-1.0.tbz2", notAPackage)
+	writeFile(t, pkgdir, "app-misc/broken-1.0.tbz2", notAPackage)
+	writeFile(t, pkgdir, "app-misc/corrupt-1.0.tbz2", notAPackage)
+	tampered := tamperedGpkg(t)
+	writeFile(t, pkgdir, "app-misc/tampered/tampered-1.0-1.gpkg.tar", tampered)
+	index := parser.Index{
+		Profile: parser.Profile{CHost: "x86_64-pc-linux-gnu"},
+		PackageEntries: []parser.Package{
+			indexEntry("app-misc/baz-1.0", "", "2", notAPackage),
+			indexEntry("app-misc/corrupt-1.0", "", "1", []byte("not a pickage")),
+			indexEntry("app-misc/tampered-1.0", "app-misc/tampered/tampered-1.0-1.gpkg.tar", "1", tampered),
+		},
+	}
+	f, err := os.Create(filepath.Join(pkgdir, "Packages"))
+	assert.NilError(t, err)
+	assert.NilError(t, index.EncodeInto(f))
+	assert.NilError(t, f.Close())
+
+	res, err := catalog.Import(ctx, db, store, tgt, pkgdir, discardLogger)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"app-misc/bar-1.0", "app-misc/baz-1.0", "app-misc/foo-1.0"}, cpvs(res.Imported))
+	assert.DeepEqual(t, []string{
+		"app-misc/broken-1.0.tbz2", "app-misc/corrupt-1.0.tbz2", "app-misc/tampered/tampered-1.0-1.gpkg.tar",
+	}, sortedKeys(res.Failed))
+	assert.Equal(t, 0, len(res.Skipped))
+	assert.ErrorContains(t, res.Failed["app-misc/corrupt-1.0.tbz2"], "BLAKE2B mismatch with Packages index")
+	var merr *packages.ManifestError
+	assert.Assert(t, errors.As(res.Failed["app-misc/tampered/tampered-1.0-1.gpkg.tar"], &merr))
+
+	baz, err := db.Pkg.Query().Where(pkg.NameEQ("baz")).Only(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, baz.BuildID)
+	obj, err := store.Get(ctx, baz.ObjectKey)
+	assert.NilError(t, err)
+	defer obj.Close()
+	assert.Equal(t, int64(len("not a package")), obj.Size)
+
+	// Importing again is a no-op.
+	res, err = catalog.Import(ctx, db, store, tgt, pkgdir, discardLogger)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(res.Imported))
+	assert.DeepEqual(t, []string{"app-misc/bar-1.0.tbz2", "app-misc/baz-1.0.tbz2", "app-misc/foo/foo-1.0-1.xpak"}, res.Skipped)
+	assert.Equal(t, 0, len(res.Conflicts))
+
+	// A different package with the same BUILD_ID conflicts with the one
+	// in the target.
+	writeFile(t, pkgdir, "app-misc/foo/foo-1.0-1.xpak", foo("rebuilt foo"))
+	res, err = catalog.Import(ctx, db, store, tgt, pkgdir, discardLogger)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(res.Imported))
+	assert.DeepEqual(t, []string{"app-misc/foo/foo-1.0-1.xpak"}, res.Conflicts)
+	assert.DeepEqual(t, []string{
+		"app-misc/broken-1.0.tbz2", "app-misc/corrupt-1.0.tbz2", "app-misc/tampered/tampered-1.0-1.gpkg.tar",
+	}, sortedKeys(res.Failed))
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/parser"
+)
+
+// chostArches maps the CPU portion of a CHOST to the Gentoo ARCH.
+// Prefixes are matched, so the most specific entries must come first.
+var chostArches = []struct {
+	prefix string
+	arch   string
+}{
+	{"x86_64", "amd64"},
+	{"i386", "x86"},
+	{"i486", "x86"},
+	{"i586", "x86"},
+	{"i686", "x86"},
+	{"aarch64", "arm64"},
+	{"arm", "arm"},
+	{"riscv", "riscv"},
+	{"powerpc64", "ppc64"},
+	{"powerpc", "ppc"},
+	{"loongarch64", "loong"},
+	{"s390", "s390"},
+	{"sparc", "sparc"},
+	{"mips", "mips"},
+	{"hppa", "hppa"},
+	{"alpha", "alpha"},
+	{"m68k", "m68k"},
+}
+
+// InferProfile returns the Profile that can be inferred from the
+// metadata of a package built for it. Only the fields that can be
+// determined from the metadata are set.
+func InferProfile(md *packages.Metadata) *parser.Profile {
+	p := &parser.Profile{
+		CHost:  md.CHost,
+		CBuild: md.CBuild,
+	}
+
+	cpu, _, _ := strings.Cut(md.CHost, "-")
+	for _, a := range chostArches {
+		if strings.HasPrefix(cpu, a.prefix) {
+			p.Arch = a.arch
+			break
+		}
+	}
+
+	return p
+}
+
+// InitProfile sets the Profile of the target if it doesn't have one
+// yet. This is used to infer the profile of a target from the first
+// package uploaded to it.
+func InitProfile(ctx context.Context, db *ent.Client, t *ent.Target, p *parser.Profile) error {
+	if err := db.Target.Update().
+		Where(target.IDEQ(t.ID), target.ProfileIsNil()).
+		SetProfile(p).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set target profile: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package catalog_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/packages/packagestest"
+	"github.com/jaredallard/binhost/internal/parser"
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+func TestInferProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       packages.Metadata
+		expected parser.Profile
+	}{
+		{
+			name:     "amd64",
+			md:       packages.Metadata{CHost: "x86_64-pc-linux-gnu", CBuild: "x86_64-pc-linux-gnu"},
+			expected: parser.Profile{Arch: "amd64", CHost: "x86_64-pc-linux-gnu", CBuild: "x86_64-pc-linux-gnu"},
+		},
+		{
+			name:     "cross compiled",
+			md:       packages.Metadata{CHost: "aarch64-unknown-linux-gnu", CBuild: "x86_64-pc-linux-gnu"},
+			expected: parser.Profile{Arch: "arm64", CHost: "aarch64-unknown-linux-gnu", CBuild: "x86_64-pc-linux-gnu"},
+		},
+		{
+			name:     "x86",
+			md:       packages.Metadata{CHost: "i686-pc-linux-gnu"},
+			expected: parser.Profile{Arch: "x86", CHost: "i686-pc-linux-gnu"},
+		},
+		{
+			name:     "more specific prefix first",
+			md:       packages.Metadata{CHost: "powerpc64le-unknown-linux-gnu"},
+			expected: parser.Profile{Arch: "ppc64", CHost: "powerpc64le-unknown-linux-gnu"},
+		},
+		{
+			name:     "unknown arch",
+			md:       packages.Metadata{CHost: "vax-dec-netbsd"},
+			expected: parser.Profile{CHost: "vax-dec-netbsd"},
+		},
+		{
+			name:     "no CHOST",
+			md:       packages.Metadata{Description: "not part of the profile"},
+			expected: parser.Profile{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, &tt.expected, catalog.InferProfile(&tt.md))
+		})
+	}
+}
+
+func TestInitProfileDoesNotOverwrite(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	tgt := newTarget(t, db, "amd64")
+
+	assert.NilError(t, catalog.InitProfile(ctx, db, tgt, &parser.Profile{Arch: "amd64"}))
+	assert.DeepEqual(t, &parser.Profile{Arch: "amd64"}, db.Target.GetX(ctx, tgt.ID).Profile)
+
+	assert.NilError(t, catalog.InitProfile(ctx, db, tgt, &parser.Profile{Arch: "arm64"}))
+	assert.DeepEqual(t, &parser.Profile{Arch: "amd64"}, db.Target.GetX(ctx, tgt.ID).Profile)
+}
+
+func TestIndexHeaderIsInferredFromFirstPackage(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "amd64")
+
+	for _, chost := range []string{"x86_64-pc-linux-gnu", "aarch64-unknown-linux-gnu"} {
+		binpkg, err := packages.New(bytes.NewReader(packagestest.BuildXpak(nil, map[string]string{
+			"CATEGORY": "app-misc\n",
+			"PF":       "foo-1.0\n",
+			"CHOST":    chost + "\n",
+			"CBUILD":   chost + "\n",
+		})))
+		assert.NilError(t, err)
+		defer binpkg.Delete() //nolint:errcheck // Why: Best effort.
+
+		_, err = catalog.AddPackage(ctx, db, store, tgt, binpkg)
+		assert.NilError(t, err)
+	}
+
+	index, err := catalog.Index(ctx, db, db.Target.GetX(ctx, tgt.ID))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parser.Profile{
+		Arch:   "amd64",
+		CHost:  "x86_64-pc-linux-gnu",
+		CBuild: "x86_64-pc-linux-gnu",
+	}, index.Profile)
+}
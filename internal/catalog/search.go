@@ -0,0 +1,239 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package catalog
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/ent/predicate"
+)
+
+const (
+	// DefaultSearchLimit is the number of packages returned by Search
+	// when no limit is provided.
+	DefaultSearchLimit = 100
+
+	// MaxSearchLimit is the maximum number of packages returned by a
+	// single call to Search.
+	MaxSearchLimit = 1000
+)
+
+// ErrInvalidCursor is returned by Search when the provided cursor
+// wasn't returned by a previous search.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SearchQuery contains the filters for searching packages. Empty
+// fields are not filtered on.
+type SearchQuery struct {
+	// TargetIDs limits the search to the provided targets. All targets
+	// are searched when nil.
+	TargetIDs []uuid.UUID
+
+	// Category is the exact category of the package, e.g., dev-lang.
+	Category string
+
+	// Name is a glob pattern (using * and ?) matched against the
+	// package name, e.g., neovim or rust*.
+	Name string
+
+	// Repository is the repository the package was built from, e.g.,
+	// gentoo.
+	Repository string
+
+	// Slot is the exact SLOT of the package.
+	Slot string
+
+	// Keyword is a keyword the package must have, e.g., ~amd64.
+	Keyword string
+
+	// Use is a USE flag the package must have been built with.
+	Use string
+
+	// BuiltAfter and BuiltBefore limit the BUILD_TIME of the package.
+	// Both are inclusive.
+	BuiltAfter  time.Time
+	BuiltBefore time.Time
+
+	// Cursor is the NextCursor of a previous SearchResult to continue
+	// from.
+	Cursor string
+
+	// Limit is the maximum number of packages to return. Defaults to
+	// DefaultSearchLimit and is capped at MaxSearchLimit.
+	Limit int
+}
+
+// SearchResult is a page of packages returned by Search.
+type SearchResult struct {
+	// Packages are the packages matching the query, with their target
+	// loaded.
+	Packages []*ent.Pkg
+
+	// NextCursor is the cursor for the next page of packages. Empty if
+	// there are no more packages.
+	NextCursor string
+}
+
+// cursor is the position in the result set of a search. Packages are
+// ordered by category, name and then ID.
+type cursor struct {
+	Category string    `json:"c"`
+	Name     string    `json:"n"`
+	ID       uuid.UUID `json:"i"`
+}
+
+// Search returns the packages matching the provided query.
+func Search(ctx context.Context, db *ent.Client, q *SearchQuery) (*SearchResult, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	limit = min(limit, MaxSearchLimit)
+
+	preds, err := q.predicates()
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one more than the limit to know if there's another page.
+	pkgs, err := db.Pkg.Query().
+		Where(preds...).
+		WithTarget().
+		Order(pkg.ByCategory(), pkg.ByName(), pkg.ByID()).
+		Limit(limit + 1).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query packages: %w", err)
+	}
+
+	res := &SearchResult{Packages: pkgs}
+	if len(pkgs) > limit {
+		res.Packages = pkgs[:limit]
+
+		last := res.Packages[limit-1]
+		b, err := json.Marshal(cursor{Category: last.Category, Name: last.Name, ID: last.ID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cursor: %w", err)
+		}
+		res.NextCursor = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	return res, nil
+}
+
+// predicates returns the predicates for the filters in the query.
+func (q *SearchQuery) predicates() ([]predicate.Pkg, error) {
+	var preds []predicate.Pkg
+	if q.TargetIDs != nil {
+		preds = append(preds, pkg.TargetIDIn(q.TargetIDs...))
+	}
+	if q.Category != "" {
+		preds = append(preds, pkg.CategoryEQ(q.Category))
+	}
+	if q.Name != "" {
+		preds = append(preds, func(s *sql.Selector) {
+			s.Where(sql.Like(s.C(pkg.FieldName), globToLike(q.Name)))
+		})
+	}
+	if q.Repository != "" {
+		preds = append(preds, pkg.RepositoryEQ(q.Repository))
+	}
+	if q.Slot != "" {
+		preds = append(preds, func(s *sql.Selector) {
+			s.Where(sqljson.ValueEQ(s.C(pkg.FieldPackageFields), q.Slot, sqljson.Path("Slot")))
+		})
+	}
+	if q.Keyword != "" {
+		preds = append(preds, func(s *sql.Selector) {
+			s.Where(sqljson.ValueContains(s.C(pkg.FieldPackageFields), q.Keyword, sqljson.Path("Keywords")))
+		})
+	}
+	if q.Use != "" {
+		preds = append(preds, hasUseFlag(q.Use))
+	}
+	if !q.BuiltAfter.IsZero() {
+		preds = append(preds, buildTime(sql.OpGTE, q.BuiltAfter))
+	}
+	if !q.BuiltBefore.IsZero() {
+		preds = append(preds, buildTime(sql.OpLTE, q.BuiltBefore))
+	}
+
+	if q.Cursor != "" {
+		b, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+
+		var c cursor
+		if err := json.Unmarshal(b, &c); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+
+		preds = append(preds, func(s *sql.Selector) {
+			s.Where(sql.CompositeGT(
+				[]string{s.C(pkg.FieldCategory), s.C(pkg.FieldName), s.C(pkg.FieldID)},
+				c.Category, c.Name, c.ID,
+			))
+		})
+	}
+
+	return preds, nil
+}
+
+// hasUseFlag returns a predicate matching packages built with the
+// provided USE flag. USE is stored as a space separated string, so the
+// flag is matched as a whole word.
+func hasUseFlag(flag string) predicate.Pkg {
+	return func(s *sql.Selector) {
+		s.Where(sql.P(func(b *sql.Builder) {
+			b.WriteString("(' ' || (").WriteString(s.C(pkg.FieldPackageFields)).WriteString("->>'Use') || ' ') LIKE ")
+			b.Arg("% " + escapeLike(flag) + " %")
+		}))
+	}
+}
+
+// buildTime returns a predicate comparing the BUILD_TIME of packages
+// to the provided time using op.
+func buildTime(op sql.Op, t time.Time) predicate.Pkg {
+	return func(s *sql.Selector) {
+		s.Where(sql.P(func(b *sql.Builder) {
+			b.WriteString("NULLIF(").WriteString(s.C(pkg.FieldPackageFields)).WriteString("->>'BuildTime', '')::bigint")
+			b.WriteOp(op).Arg(t.Unix())
+		}))
+	}
+}
+
+// globToLike converts a glob pattern using * and ? into a LIKE
+// pattern.
+func globToLike(glob string) string {
+	return strings.NewReplacer("*", "%", "?", "_").Replace(escapeLike(glob))
+}
+
+// escapeLike escapes the special characters of a LIKE pattern in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
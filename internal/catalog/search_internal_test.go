@@ -0,0 +1,20 @@
+package catalog
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGlobToLike(t *testing.T) {
+	for glob, like := range map[string]string{
+		"neovim":       "neovim",
+		"rust*":        "rust%",
+		"py?hon":       "py_hon",
+		"100%_done*":   `100\%\_done%`,
+		`back\slash?`:  `back\\slash_`,
+		"*-bin_compat": `%-bin\_compat`,
+	} {
+		assert.Equal(t, like, globToLike(glob), glob)
+	}
+}
@@ -0,0 +1,56 @@
+package catalog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+func TestSearchPaginates(t *testing.T) {
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "test")
+
+	// Multiple builds of the same package are ordered by their ID, so
+	// the cursor has to include it.
+	addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "")
+	addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "")
+	addXpak(t, db, store, tgt, "app-misc", "bar-1.0", "")
+	addXpak(t, db, store, tgt, "dev-lang", "go-1.22.0", "")
+
+	var (
+		got    []string
+		cursor string
+		pages  int
+	)
+	for {
+		res, err := catalog.Search(context.Background(), db, &catalog.SearchQuery{Cursor: cursor, Limit: 1})
+		assert.NilError(t, err)
+		for _, p := range res.Packages {
+			assert.Equal(t, "test", p.Edges.Target.Name)
+			got = append(got, catalog.CPV(p))
+		}
+		pages++
+
+		cursor = res.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	assert.DeepEqual(t, []string{"app-misc/bar-1.0", "app-misc/foo-1.0", "app-misc/foo-1.0", "dev-lang/go-1.22.0"}, got)
+	assert.Equal(t, 4, pages)
+}
+
+func TestSearchRejectsInvalidCursor(t *testing.T) {
+	db := dbtest.Open(t)
+
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24"} {
+		_, err := catalog.Search(context.Background(), db, &catalog.SearchQuery{Cursor: cursor})
+		assert.ErrorIs(t, err, catalog.ErrInvalidCursor, cursor)
+	}
+}
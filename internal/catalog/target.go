@@ -0,0 +1,193 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/ent/token"
+	"github.com/jaredallard/binhost/internal/storage"
+)
+
+var (
+	// ErrTargetNotFound is returned when a target doesn't exist.
+	ErrTargetNotFound = errors.New("target not found")
+
+	// ErrTargetExists is returned by CreateTarget when a target with the
+	// same name already exists.
+	ErrTargetExists = errors.New("target already exists")
+
+	// ErrTargetNotEmpty is returned by DeleteTarget when the target
+	// still contains packages and deletion wasn't forced.
+	ErrTargetNotEmpty = errors.New("target is not empty")
+)
+
+// LookupTarget returns the target with the provided name.
+// ErrTargetNotFound is returned if it doesn't exist.
+func LookupTarget(ctx context.Context, db *ent.Client, name string) (*ent.Target, error) {
+	t, err := db.Target.Query().Where(target.NameEQ(name)).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrTargetNotFound, name)
+		}
+
+		return nil, fmt.Errorf("failed querying target: %w", err)
+	}
+
+	return t, nil
+}
+
+// LookupTargets returns the targets with the provided names, ignoring
+// duplicates. ErrTargetNotFound is returned if any of them doesn't
+// exist.
+func LookupTargets(ctx context.Context, db *ent.Client, names []string) ([]*ent.Target, error) {
+	names = slices.Clone(names)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	targets, err := db.Target.Query().Where(target.NameIn(names...)).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed querying targets: %w", err)
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(targets, func(t *ent.Target) bool { return t.Name == name }) {
+			return nil, fmt.Errorf("%w: %s", ErrTargetNotFound, name)
+		}
+	}
+
+	return targets, nil
+}
+
+// ListTargets returns all targets, ordered by name, along with the
+// TargetStats of those that contain packages (see Stats).
+func ListTargets(ctx context.Context, db *ent.Client) ([]*ent.Target, map[uuid.UUID]TargetStats, error) {
+	targets, err := db.Target.Query().Order(target.ByName()).All(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed querying targets: %w", err)
+	}
+
+	stats, err := Stats(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return targets, stats, nil
+}
+
+// CreateTarget creates an empty target with the provided name.
+// ErrTargetExists is returned if it already exists.
+func CreateTarget(ctx context.Context, db *ent.Client, name string) (*ent.Target, error) {
+	t, err := db.Target.Create().SetName(name).Save(ctx)
+	if err != nil {
+		if ent.IsConstraintError(err) {
+			return nil, fmt.Errorf("%w: %s", ErrTargetExists, name)
+		}
+
+		return nil, fmt.Errorf("failed creating target: %w", err)
+	}
+
+	return t, nil
+}
+
+// TargetStats contains aggregate information about the packages in a
+// target.
+type TargetStats struct {
+	// Packages is the number of packages in the target.
+	Packages int `json:"packages"`
+
+	// Size is the total size, in bytes, of the archives of the packages
+	// in the target.
+	Size int64 `json:"size"`
+}
+
+// Stats returns the TargetStats of every target that contains
+// packages, keyed by target ID. Targets without packages are omitted.
+func Stats(ctx context.Context, db *ent.Client) (map[uuid.UUID]TargetStats, error) {
+	var rows []struct {
+		TargetID uuid.UUID     `json:"target_id"`
+		Count    int           `json:"count"`
+		Size     sql.NullInt64 `json:"size"`
+	}
+	if err := db.Pkg.Query().
+		GroupBy(pkg.FieldTargetID).
+		Aggregate(ent.Count(), ent.As(ent.Sum(pkg.FieldSize), "size")).
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to aggregate packages: %w", err)
+	}
+
+	stats := make(map[uuid.UUID]TargetStats, len(rows))
+	for _, r := range rows {
+		stats[r.TargetID] = TargetStats{Packages: r.Count, Size: r.Size.Int64}
+	}
+
+	return stats, nil
+}
+
+// DeleteTarget deletes the provided target along with all of its
+// packages and their archives. ErrTargetNotEmpty is returned if the
+// target contains packages, unless force is set. Like with
+// DeletePackages, the archives are deleted once the deletion has been
+// committed and failing to delete them is only logged.
+//
+// Tokens whose target scopes are limited to only this target are
+// revoked. Otherwise they'd be left without targets, which grants them
+// access to all targets.
+func DeleteTarget(ctx context.Context, db *ent.Client, store storage.Storage, log *slog.Logger, t *ent.Target, force bool) error {
+	tx, err := db.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // Why: No-op after commit.
+
+	pkgs, err := tx.Pkg.Query().Where(pkg.TargetIDEQ(t.ID)).All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query packages: %w", err)
+	}
+	if len(pkgs) != 0 && !force {
+		return ErrTargetNotEmpty
+	}
+
+	if _, err := tx.Pkg.Delete().Where(pkg.TargetIDEQ(t.ID)).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete packages: %w", err)
+	}
+
+	if _, err := tx.Token.Delete().Where(
+		token.HasTargetsWith(target.IDEQ(t.ID)),
+		token.Not(token.HasTargetsWith(target.IDNEQ(t.ID))),
+	).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	if _, err := tx.Target.Delete().Where(target.IDEQ(t.ID)).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete target: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit deletion: %w", err)
+	}
+
+	deleteArchives(ctx, store, log, pkgs)
+	return nil
+}
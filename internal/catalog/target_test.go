@@ -0,0 +1,111 @@
+package catalog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/auth"
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+func TestStats(t *testing.T) {
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	amd64 := newTarget(t, db, "amd64")
+	newTarget(t, db, "arm64")
+
+	foo := addXpak(t, db, store, amd64, "app-misc", "foo-1.0", "")
+	bar := addXpak(t, db, store, amd64, "app-misc", "bar-1.0", "")
+
+	stats, err := catalog.Stats(context.Background(), db)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[uuid.UUID]catalog.TargetStats{
+		amd64.ID: {Packages: 2, Size: foo.Size + bar.Size},
+	}, stats)
+}
+
+func TestDeleteTargetRequiresForceWhenNotEmpty(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "amd64")
+	p := addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "")
+
+	err := catalog.DeleteTarget(ctx, db, store, discardLogger, tgt, false)
+	assert.ErrorIs(t, err, catalog.ErrTargetNotEmpty)
+
+	n, err := db.Pkg.Query().Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.NilError(t, catalog.DeleteTarget(ctx, db, store, discardLogger, tgt, true))
+
+	n, err = db.Target.Query().Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, n)
+	n, err = db.Pkg.Query().Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, n)
+
+	_, err = store.Get(ctx, p.ObjectKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestDeleteTargetIgnoresArchiveFailures(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	tgt := newTarget(t, db, "amd64")
+	addXpak(t, db, store, tgt, "app-misc", "foo-1.0", "")
+
+	assert.NilError(t, catalog.DeleteTarget(ctx, db, failingDeleteStorage{store}, discardLogger, tgt, true))
+
+	n, err := db.Target.Query().Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestDeleteEmptyTarget(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	tgt := newTarget(t, db, "amd64")
+
+	assert.NilError(t, catalog.DeleteTarget(ctx, db, storage.NewFS(t.TempDir()), discardLogger, tgt, false))
+
+	n, err := db.Target.Query().Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestDeleteTargetRevokesTokensLimitedToIt(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	store := storage.NewFS(t.TempDir())
+	a := newTarget(t, db, "a")
+	b := newTarget(t, db, "b")
+
+	onlyA, _, err := auth.CreateToken(ctx, db, "only-a", []auth.Scope{auth.ScopeTargetWrite}, []*ent.Target{a}, nil)
+	assert.NilError(t, err)
+	both, _, err := auth.CreateToken(ctx, db, "both", []auth.Scope{auth.ScopeTargetWrite}, []*ent.Target{a, b}, nil)
+	assert.NilError(t, err)
+
+	p, err := auth.Authenticate(ctx, db, "", onlyA)
+	assert.NilError(t, err)
+	assert.Assert(t, !p.Can(auth.ScopeTargetWrite, b.ID))
+
+	assert.NilError(t, catalog.DeleteTarget(ctx, db, store, discardLogger, a, false))
+
+	// The token must not be widened to all targets.
+	_, err = auth.Authenticate(ctx, db, "", onlyA)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	p, err = auth.Authenticate(ctx, db, "", both)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []uuid.UUID{b.ID}, p.TargetIDs)
+	assert.Assert(t, p.Can(auth.ScopeTargetWrite, b.ID))
+}
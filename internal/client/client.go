@@ -0,0 +1,283 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package client contains a client for the binhost HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/parser"
+)
+
+// Error is an error response returned by the server.
+type Error struct {
+	// Status is the HTTP status code of the response.
+	Status int
+
+	api.ErrorBody
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (%d %s)", e.Message, e.Status, e.Code)
+}
+
+// IsCode returns true if err is an [Error] with the provided code,
+// e.g., target_not_found.
+func IsCode(err error, code string) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == code
+}
+
+// Client is a client for the binhost HTTP API. Create using the New()
+// function.
+type Client struct {
+	baseURL *url.URL
+	token   string
+
+	// HTTPClient is the HTTP client used to make requests.
+	HTTPClient *http.Client
+}
+
+// New creates a new Client for the server at baseURL, e.g.,
+// https://binhost.example.com, that authenticates using the provided
+// token.
+func New(baseURL, token string) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid server URL %q: scheme must be http or https", baseURL)
+	}
+
+	return &Client{baseURL: u, token: token, HTTPClient: http.DefaultClient}, nil
+}
+
+// ListTargets lists the targets the token can read.
+func (c *Client) ListTargets(ctx context.Context) ([]api.Target, error) {
+	var resp []api.Target
+	return resp, c.do(ctx, http.MethodGet, "/v1/targets", nil, nil, &resp)
+}
+
+// CreateTarget creates a target.
+func (c *Client) CreateTarget(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/v1/targets/"+url.PathEscape(name), nil, nil, nil)
+}
+
+// UpdateTarget renames a target or updates its metadata.
+func (c *Client) UpdateTarget(ctx context.Context, name string, req *api.UpdateTargetRequest) (*api.Target, error) {
+	var resp api.Target
+	return &resp, c.do(ctx, http.MethodPatch, "/v1/targets/"+url.PathEscape(name), nil, jsonBody(req), &resp)
+}
+
+// DeleteTarget deletes a target. Targets containing packages are only
+// deleted, along with their packages, if force is true.
+func (c *Client) DeleteTarget(ctx context.Context, name string, force bool) error {
+	q := url.Values{}
+	if force {
+		q.Set("force", "true")
+	}
+	return c.do(ctx, http.MethodDelete, "/v1/targets/"+url.PathEscape(name), q, nil, nil)
+}
+
+// ListPackages returns a page of the packages in a target matching the
+// provided search parameters (see the OpenAPI document). Packages
+// across all targets are searched if target is empty.
+func (c *Client) ListPackages(ctx context.Context, target string, q url.Values) (*api.ListPackagesResponse, error) {
+	path := "/v1/packages"
+	if target != "" {
+		path = "/v1/targets/" + url.PathEscape(target) + "/packages"
+	}
+
+	var resp api.ListPackagesResponse
+	return &resp, c.do(ctx, http.MethodGet, path, q, nil, &resp)
+}
+
+// DeletePackages deletes all packages in a target whose CPV matches
+// the provided glob pattern, e.g., dev-lang/rust-*.
+func (c *Client) DeletePackages(ctx context.Context, target, pattern string) ([]api.DeletedPackage, error) {
+	var resp []api.DeletedPackage
+	path := "/v1/targets/" + url.PathEscape(target) + "/packages"
+	return resp, c.do(ctx, http.MethodDelete, path, url.Values{"atom": {pattern}}, nil, &resp)
+}
+
+// Upload uploads a package of the provided size to a target. The
+// package is uploaded to the target named after its CHOST if target is
+// empty.
+func (c *Client) Upload(ctx context.Context, target string, r io.Reader, size int64) (*api.UploadResponse, error) {
+	path := "/v1/upload"
+	if target != "" {
+		path = "/v1/targets/" + url.PathEscape(target) + "/upload"
+	}
+
+	var resp api.UploadResponse
+	return &resp, c.do(ctx, http.MethodPost, path, nil, &body{r, size, "application/octet-stream"}, &resp)
+}
+
+// Index returns the Packages index of a target, as served to Portage.
+// The token is sent using HTTP Basic authentication, which is required
+// for private targets.
+func (c *Client) Index(ctx context.Context, target string) (*parser.Index, error) {
+	var authorization string
+	if c.token != "" {
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte("binhost:"+c.token))
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, "/t/"+url.PathEscape(target)+"/Packages", nil, nil, authorization)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	index, err := parser.ParsePackages(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	if index == nil {
+		return &parser.Index{}, nil
+	}
+	return index, nil
+}
+
+// ListTokens lists all tokens.
+func (c *Client) ListTokens(ctx context.Context) ([]api.Token, error) {
+	var resp []api.Token
+	return resp, c.do(ctx, http.MethodGet, "/v1/tokens", nil, nil, &resp)
+}
+
+// CreateToken creates a token. The token itself is only returned by
+// this call.
+func (c *Client) CreateToken(ctx context.Context, req *api.CreateTokenRequest) (*api.Token, error) {
+	var resp api.Token
+	return &resp, c.do(ctx, http.MethodPost, "/v1/tokens", nil, jsonBody(req), &resp)
+}
+
+// RevokeToken revokes a token.
+func (c *Client) RevokeToken(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/v1/tokens/"+id.String(), nil, nil, nil)
+}
+
+// body is the body of a request.
+type body struct {
+	r           io.Reader
+	size        int64
+	contentType string
+}
+
+// jsonBody returns a body containing v encoded as JSON.
+func jsonBody(v any) *body {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// Only request types, which always encode, are passed.
+		panic(err)
+	}
+	return &body{bytes.NewReader(b), int64(len(b)), "application/json"}
+}
+
+// do makes a request to the /v1 API and decodes the JSON response
+// into out, unless out is nil.
+func (c *Client) do(ctx context.Context, method, path string, q url.Values, b *body, out any) error {
+	var authorization string
+	if c.token != "" {
+		authorization = "Bearer " + c.token
+	}
+
+	resp, err := c.send(ctx, method, path, q, b, authorization)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// send makes a request to the provided path, which must already be
+// escaped, with the provided Authorization header. Error responses are
+// returned as an [Error]. The body of the returned response must be
+// closed.
+func (c *Client) send(ctx context.Context, method, path string, q url.Values, b *body, authorization string) (*http.Response, error) {
+	u := c.baseURL.String() + path
+	if len(q) != 0 {
+		u += "?" + q.Encode()
+	}
+
+	var r io.Reader
+	if b != nil {
+		r = b.r
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if b != nil {
+		req.ContentLength = b.size
+		req.Header.Set("Content-Type", b.contentType)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s %s: %w", method, path, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+	return resp, nil
+}
+
+// decodeError returns the [Error] of the provided error response.
+// Responses that aren't JSON error responses, e.g., from a proxy, are
+// converted into an [Error] with the code "unknown".
+func decodeError(resp *http.Response) error {
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read error response: %w", err)
+	}
+
+	var body api.ErrorResponse
+	if err := json.Unmarshal(b, &body); err != nil || body.Error.Code == "" {
+		msg := strings.TrimSpace(string(b))
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		body.Error = api.ErrorBody{Code: "unknown", Message: msg}
+	}
+
+	return &Error{Status: resp.StatusCode, ErrorBody: body.Error}
+}
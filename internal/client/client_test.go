@@ -0,0 +1,60 @@
+package client_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/client"
+	"gotest.tools/v3/assert"
+)
+
+// newTestClient returns a client for a server that serves handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *client.Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := client.New(srv.URL, "bh_test")
+	assert.NilError(t, err)
+	return c
+}
+
+func TestClientDecodesErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"error":{"code":"target_not_found","message":"target not found"}}`) //nolint:errcheck // Why: Test server.
+	})
+
+	err := c.DeleteTarget(context.Background(), "amd64", false)
+	assert.Error(t, err, "target not found (404 target_not_found)")
+	assert.Assert(t, client.IsCode(err, "target_not_found"))
+}
+
+func TestClientDecodesNonJSONErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+
+	_, err := c.ListTargets(context.Background())
+	assert.Error(t, err, "bad gateway (502 unknown)")
+}
+
+func TestClientUploadsToCHostWithoutTarget(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/upload", r.URL.Path)
+		assert.Equal(t, "Bearer bh_test", r.Header.Get("Authorization"))
+		assert.Equal(t, int64(4), r.ContentLength)
+
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"target":"x86_64-pc-linux-gnu","cpv":"app-misc/foo-1.0","build_id":1}`) //nolint:errcheck // Why: Test server.
+	})
+
+	resp, err := c.Upload(context.Background(), "", strings.NewReader("gpkg"), 4)
+	assert.NilError(t, err)
+	assert.Equal(t, "x86_64-pc-linux-gnu", resp.Target)
+	assert.Equal(t, 1, resp.BuildID)
+}
@@ -64,6 +64,20 @@ type Config struct {
 
 	// S3Bucket is the bucket to store files in.
 	S3Bucket string `env:"S3_BUCKET"`
+
+	// StorageDir, if set, is a directory on the local filesystem to
+	// store files in instead of S3.
+	StorageDir string `env:"STORAGE_DIR"`
+
+	// AutoCreateTargets controls whether packages uploaded without a
+	// target are uploaded to a new target named after their CHOST if it
+	// doesn't exist, instead of being rejected.
+	AutoCreateTargets bool `env:"AUTO_CREATE_TARGETS" envDefault:"false"`
+
+	// AdminToken is a static token that is granted the admin scope. It
+	// is intended for creating the first API tokens and should be unset
+	// afterwards.
+	AdminToken string `env:"ADMIN_TOKEN"`
 }
 
 // LoadConfig loads configuration from the environment and returns a
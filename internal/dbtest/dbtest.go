@@ -0,0 +1,50 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package dbtest provides a database client for tests. The client is
+// backed by SQLite, so tests don't need a Postgres server, and only
+// code paths that don't rely on Postgres specific SQL can be tested
+// with it. The rest are tested using Postgres, when a server is
+// available.
+package dbtest
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/ent/enttest"
+	_ "modernc.org/sqlite" // Used by ent.
+)
+
+// Open returns a client for a new, empty database with the schema
+// created. The database is removed when the test finishes.
+func Open(t *testing.T) *ent.Client {
+	t.Helper()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "binhost.db") +
+		"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	client := enttest.NewClient(t, enttest.WithOptions(ent.Driver(entsql.OpenDB(dialect.SQLite, db))))
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck // Why: Best effort.
+	return client
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package dbtest
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// PostgresEnv is the environment variable containing the URL of the
+// Postgres server used by Postgres. Tests using it are skipped if it
+// isn't set.
+const PostgresEnv = "TEST_DATABASE_URL"
+
+// Postgres returns a connection to a new, empty schema on the Postgres
+// server in PostgresEnv, for testing code that relies on Postgres
+// specific SQL. The schema is dropped when the test finishes.
+func Postgres(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv(PostgresEnv)
+	if url == "" {
+		t.Skipf("%s isn't set", PostgresEnv)
+	}
+
+	cfg, err := pgx.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", PostgresEnv, err)
+	}
+
+	admin := stdlib.OpenDB(*cfg)
+	t.Cleanup(func() { admin.Close() }) //nolint:errcheck // Why: Best effort.
+
+	schema := "test_" + uuid.NewString()
+	if _, err := admin.Exec(fmt.Sprintf("CREATE SCHEMA %q", schema)); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(fmt.Sprintf("DROP SCHEMA %q CASCADE", schema)); err != nil {
+			t.Errorf("failed to drop schema: %v", err)
+		}
+	})
+
+	cfg = cfg.Copy()
+	cfg.RuntimeParams["search_path"] = schema
+	db := stdlib.OpenDB(*cfg)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck // Why: Best effort.
+	return db
+}
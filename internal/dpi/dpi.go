@@ -31,10 +31,11 @@ import (
 	"github.com/minio/minio-go/v7/pkg/credentials"
 
 	entsql "entgo.io/ent/dialect/sql"
-	"entgo.io/ent/dialect/sql/schema"
 	_ "github.com/jackc/pgx/v5/stdlib" // Used by ent.
 	"github.com/jaredallard/binhost/internal/config"
 	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/migrations"
+	"github.com/jaredallard/binhost/internal/storage"
 )
 
 // Dependencies contains dependencies for the binhost server that is
@@ -46,15 +47,24 @@ type Dependencies struct {
 	// S3 is a S3 client
 	S3 *minio.Client
 
+	// Storage is the object storage that package archives are stored
+	// in. It is backed by [S3], unless a storage directory is
+	// configured.
+	Storage storage.Storage
+
 	// Conf is the configuration for the binhost server.
 	Conf *config.Config
 
 	// Log is a configured logger that should be used for logging purposes.
 	Log *slog.Logger
+
+	// sqlDB is the database connection used by DB.
+	sqlDB *sql.DB
 }
 
 // New creates a new dependencies struct with all of the required
-// clients and configuration.
+// clients and configuration. The database schema is not created or
+// checked, see [Dependencies.Migrator].
 func New(ctx context.Context, log *slog.Logger) (*Dependencies, error) {
 	cfg, err := config.LoadConfig(log)
 	if err != nil {
@@ -78,9 +88,6 @@ func New(ctx context.Context, log *slog.Logger) (*Dependencies, error) {
 	}
 
 	client := ent.NewClient(ent.Driver(entsql.OpenDB(dialect.Postgres, db)))
-	if err := client.Schema.Create(ctx, schema.WithDropColumn(true), schema.WithDropIndex(true)); err != nil {
-		return nil, fmt.Errorf("failed creating schema resources: %w", err)
-	}
 
 	log.Info("connecting to S3", "endpoint", cfg.S3Endpoint)
 	s3, err := minio.New(cfg.S3Endpoint, &minio.Options{
@@ -90,10 +97,23 @@ func New(ctx context.Context, log *slog.Logger) (*Dependencies, error) {
 		return nil, err
 	}
 
+	store := storage.NewS3(s3, cfg.S3Bucket)
+	if cfg.StorageDir != "" {
+		log.Info("storing files on the filesystem", "dir", cfg.StorageDir)
+		store = storage.NewFS(cfg.StorageDir)
+	}
+
 	return &Dependencies{
-		DB:   client,
-		S3:   s3,
-		Conf: cfg,
-		Log:  log,
+		DB:      client,
+		S3:      s3,
+		Storage: store,
+		Conf:    cfg,
+		Log:     log,
+		sqlDB:   db,
 	}, nil
 }
+
+// Migrator returns a migrator for the database.
+func (d *Dependencies) Migrator() (*migrations.Migrator, error) {
+	return migrations.New(d.sqlDB)
+}
@@ -15,7 +15,18 @@ var (
 		{Name: "category", Type: field.TypeString},
 		{Name: "name", Type: field.TypeString},
 		{Name: "version", Type: field.TypeString},
+		{Name: "revision", Type: field.TypeString, Default: ""},
+		{Name: "build_id", Type: field.TypeInt, Default: 1},
 		{Name: "package_fields", Type: field.TypeJSON},
+		{Name: "format", Type: field.TypeEnum, Enums: []string{"gpkg", "xpak"}, Default: "gpkg"},
+		{Name: "path", Type: field.TypeString},
+		{Name: "object_key", Type: field.TypeString},
+		{Name: "size", Type: field.TypeInt64, Default: 0},
+		{Name: "sha1", Type: field.TypeString, Default: ""},
+		{Name: "md5", Type: field.TypeString, Default: ""},
+		{Name: "blake2b", Type: field.TypeString, Default: ""},
+		{Name: "sha512", Type: field.TypeString, Default: ""},
+		{Name: "mtime", Type: field.TypeTime},
 		{Name: "target_id", Type: field.TypeUUID},
 	}
 	// PkgsTable holds the schema information for the "pkgs" table.
@@ -26,16 +37,21 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "pkgs_targets_target",
-				Columns:    []*schema.Column{PkgsColumns[6]},
+				Columns:    []*schema.Column{PkgsColumns[17]},
 				RefColumns: []*schema.Column{TargetsColumns[0]},
 				OnDelete:   schema.NoAction,
 			},
 		},
 		Indexes: []*schema.Index{
 			{
-				Name:    "pkg_repository_category_name_version_target_id",
+				Name:    "pkg_repository_category_name_version_revision_build_id_target_id",
 				Unique:  true,
-				Columns: []*schema.Column{PkgsColumns[1], PkgsColumns[2], PkgsColumns[3], PkgsColumns[4], PkgsColumns[6]},
+				Columns: []*schema.Column{PkgsColumns[1], PkgsColumns[2], PkgsColumns[3], PkgsColumns[4], PkgsColumns[5], PkgsColumns[6], PkgsColumns[17]},
+			},
+			{
+				Name:    "pkg_target_id_path",
+				Unique:  true,
+				Columns: []*schema.Column{PkgsColumns[17], PkgsColumns[9]},
 			},
 		},
 	}
@@ -43,6 +59,11 @@ var (
 	TargetsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUUID, Unique: true},
 		{Name: "name", Type: field.TypeString, Unique: true},
+		{Name: "description", Type: field.TypeString, Default: ""},
+		{Name: "owner", Type: field.TypeString, Default: ""},
+		{Name: "private", Type: field.TypeBool, Default: false},
+		{Name: "profile", Type: field.TypeJSON, Nullable: true},
+		{Name: "index_updated_at", Type: field.TypeTime},
 	}
 	// TargetsTable holds the schema information for the "targets" table.
 	TargetsTable = &schema.Table{
@@ -50,13 +71,57 @@ var (
 		Columns:    TargetsColumns,
 		PrimaryKey: []*schema.Column{TargetsColumns[0]},
 	}
+	// TokensColumns holds the columns for the "tokens" table.
+	TokensColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID, Unique: true},
+		{Name: "name", Type: field.TypeString},
+		{Name: "hash", Type: field.TypeString, Unique: true},
+		{Name: "scopes", Type: field.TypeJSON},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "expires_at", Type: field.TypeTime, Nullable: true},
+	}
+	// TokensTable holds the schema information for the "tokens" table.
+	TokensTable = &schema.Table{
+		Name:       "tokens",
+		Columns:    TokensColumns,
+		PrimaryKey: []*schema.Column{TokensColumns[0]},
+	}
+	// TokenTargetsColumns holds the columns for the "token_targets" table.
+	TokenTargetsColumns = []*schema.Column{
+		{Name: "token_id", Type: field.TypeUUID},
+		{Name: "target_id", Type: field.TypeUUID},
+	}
+	// TokenTargetsTable holds the schema information for the "token_targets" table.
+	TokenTargetsTable = &schema.Table{
+		Name:       "token_targets",
+		Columns:    TokenTargetsColumns,
+		PrimaryKey: []*schema.Column{TokenTargetsColumns[0], TokenTargetsColumns[1]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "token_targets_token_id",
+				Columns:    []*schema.Column{TokenTargetsColumns[0]},
+				RefColumns: []*schema.Column{TokensColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+			{
+				Symbol:     "token_targets_target_id",
+				Columns:    []*schema.Column{TokenTargetsColumns[1]},
+				RefColumns: []*schema.Column{TargetsColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
 		PkgsTable,
 		TargetsTable,
+		TokensTable,
+		TokenTargetsTable,
 	}
 )
 
 func init() {
 	PkgsTable.ForeignKeys[0].RefTable = TargetsTable
+	TokenTargetsTable.ForeignKeys[0].RefTable = TokensTable
+	TokenTargetsTable.ForeignKeys[1].RefTable = TargetsTable
 }
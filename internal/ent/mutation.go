@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
@@ -14,6 +15,7 @@ import (
 	"github.com/jaredallard/binhost/internal/ent/pkg"
 	"github.com/jaredallard/binhost/internal/ent/predicate"
 	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/ent/token"
 	"github.com/jaredallard/binhost/internal/parser"
 )
 
@@ -28,6 +30,7 @@ const (
 	// Node types.
 	TypePkg    = "Pkg"
 	TypeTarget = "Target"
+	TypeToken  = "Token"
 )
 
 // PkgMutation represents an operation that mutates the Pkg nodes in the graph.
@@ -40,7 +43,20 @@ type PkgMutation struct {
 	category       *string
 	name           *string
 	version        *string
+	revision       *string
+	build_id       *int
+	addbuild_id    *int
 	package_fields **parser.PackageCommon
+	format         *pkg.Format
+	_path          *string
+	object_key     *string
+	size           *int64
+	addsize        *int64
+	sha1           *string
+	md5            *string
+	blake2b        *string
+	sha512         *string
+	mtime          *time.Time
 	clearedFields  map[string]struct{}
 	target         *uuid.UUID
 	clearedtarget  bool
@@ -297,6 +313,98 @@ func (m *PkgMutation) ResetVersion() {
 	m.version = nil
 }
 
+// SetRevision sets the "revision" field.
+func (m *PkgMutation) SetRevision(s string) {
+	m.revision = &s
+}
+
+// Revision returns the value of the "revision" field in the mutation.
+func (m *PkgMutation) Revision() (r string, exists bool) {
+	v := m.revision
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRevision returns the old "revision" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldRevision(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRevision is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRevision requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRevision: %w", err)
+	}
+	return oldValue.Revision, nil
+}
+
+// ResetRevision resets all changes to the "revision" field.
+func (m *PkgMutation) ResetRevision() {
+	m.revision = nil
+}
+
+// SetBuildID sets the "build_id" field.
+func (m *PkgMutation) SetBuildID(i int) {
+	m.build_id = &i
+	m.addbuild_id = nil
+}
+
+// BuildID returns the value of the "build_id" field in the mutation.
+func (m *PkgMutation) BuildID() (r int, exists bool) {
+	v := m.build_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldBuildID returns the old "build_id" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldBuildID(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldBuildID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldBuildID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldBuildID: %w", err)
+	}
+	return oldValue.BuildID, nil
+}
+
+// AddBuildID adds i to the "build_id" field.
+func (m *PkgMutation) AddBuildID(i int) {
+	if m.addbuild_id != nil {
+		*m.addbuild_id += i
+	} else {
+		m.addbuild_id = &i
+	}
+}
+
+// AddedBuildID returns the value that was added to the "build_id" field in this mutation.
+func (m *PkgMutation) AddedBuildID() (r int, exists bool) {
+	v := m.addbuild_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetBuildID resets all changes to the "build_id" field.
+func (m *PkgMutation) ResetBuildID() {
+	m.build_id = nil
+	m.addbuild_id = nil
+}
+
 // SetPackageFields sets the "package_fields" field.
 func (m *PkgMutation) SetPackageFields(pc *parser.PackageCommon) {
 	m.package_fields = &pc
@@ -325,86 +433,1399 @@ func (m *PkgMutation) OldPackageFields(ctx context.Context) (v *parser.PackageCo
 	if err != nil {
 		return v, fmt.Errorf("querying old value for OldPackageFields: %w", err)
 	}
-	return oldValue.PackageFields, nil
+	return oldValue.PackageFields, nil
+}
+
+// ResetPackageFields resets all changes to the "package_fields" field.
+func (m *PkgMutation) ResetPackageFields() {
+	m.package_fields = nil
+}
+
+// SetFormat sets the "format" field.
+func (m *PkgMutation) SetFormat(pk pkg.Format) {
+	m.format = &pk
+}
+
+// Format returns the value of the "format" field in the mutation.
+func (m *PkgMutation) Format() (r pkg.Format, exists bool) {
+	v := m.format
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldFormat returns the old "format" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldFormat(ctx context.Context) (v pkg.Format, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldFormat is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldFormat requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldFormat: %w", err)
+	}
+	return oldValue.Format, nil
+}
+
+// ResetFormat resets all changes to the "format" field.
+func (m *PkgMutation) ResetFormat() {
+	m.format = nil
+}
+
+// SetPath sets the "path" field.
+func (m *PkgMutation) SetPath(s string) {
+	m._path = &s
+}
+
+// Path returns the value of the "path" field in the mutation.
+func (m *PkgMutation) Path() (r string, exists bool) {
+	v := m._path
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPath returns the old "path" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldPath(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPath is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPath requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPath: %w", err)
+	}
+	return oldValue.Path, nil
+}
+
+// ResetPath resets all changes to the "path" field.
+func (m *PkgMutation) ResetPath() {
+	m._path = nil
+}
+
+// SetObjectKey sets the "object_key" field.
+func (m *PkgMutation) SetObjectKey(s string) {
+	m.object_key = &s
+}
+
+// ObjectKey returns the value of the "object_key" field in the mutation.
+func (m *PkgMutation) ObjectKey() (r string, exists bool) {
+	v := m.object_key
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldObjectKey returns the old "object_key" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldObjectKey(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldObjectKey is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldObjectKey requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldObjectKey: %w", err)
+	}
+	return oldValue.ObjectKey, nil
+}
+
+// ResetObjectKey resets all changes to the "object_key" field.
+func (m *PkgMutation) ResetObjectKey() {
+	m.object_key = nil
+}
+
+// SetSize sets the "size" field.
+func (m *PkgMutation) SetSize(i int64) {
+	m.size = &i
+	m.addsize = nil
+}
+
+// Size returns the value of the "size" field in the mutation.
+func (m *PkgMutation) Size() (r int64, exists bool) {
+	v := m.size
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldSize returns the old "size" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldSize(ctx context.Context) (v int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSize is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSize requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSize: %w", err)
+	}
+	return oldValue.Size, nil
+}
+
+// AddSize adds i to the "size" field.
+func (m *PkgMutation) AddSize(i int64) {
+	if m.addsize != nil {
+		*m.addsize += i
+	} else {
+		m.addsize = &i
+	}
+}
+
+// AddedSize returns the value that was added to the "size" field in this mutation.
+func (m *PkgMutation) AddedSize() (r int64, exists bool) {
+	v := m.addsize
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetSize resets all changes to the "size" field.
+func (m *PkgMutation) ResetSize() {
+	m.size = nil
+	m.addsize = nil
+}
+
+// SetSha1 sets the "sha1" field.
+func (m *PkgMutation) SetSha1(s string) {
+	m.sha1 = &s
+}
+
+// Sha1 returns the value of the "sha1" field in the mutation.
+func (m *PkgMutation) Sha1() (r string, exists bool) {
+	v := m.sha1
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldSha1 returns the old "sha1" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldSha1(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSha1 is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSha1 requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSha1: %w", err)
+	}
+	return oldValue.Sha1, nil
+}
+
+// ResetSha1 resets all changes to the "sha1" field.
+func (m *PkgMutation) ResetSha1() {
+	m.sha1 = nil
+}
+
+// SetMd5 sets the "md5" field.
+func (m *PkgMutation) SetMd5(s string) {
+	m.md5 = &s
+}
+
+// Md5 returns the value of the "md5" field in the mutation.
+func (m *PkgMutation) Md5() (r string, exists bool) {
+	v := m.md5
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMd5 returns the old "md5" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldMd5(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMd5 is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMd5 requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMd5: %w", err)
+	}
+	return oldValue.Md5, nil
+}
+
+// ResetMd5 resets all changes to the "md5" field.
+func (m *PkgMutation) ResetMd5() {
+	m.md5 = nil
+}
+
+// SetBlake2b sets the "blake2b" field.
+func (m *PkgMutation) SetBlake2b(s string) {
+	m.blake2b = &s
+}
+
+// Blake2b returns the value of the "blake2b" field in the mutation.
+func (m *PkgMutation) Blake2b() (r string, exists bool) {
+	v := m.blake2b
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldBlake2b returns the old "blake2b" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldBlake2b(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldBlake2b is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldBlake2b requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldBlake2b: %w", err)
+	}
+	return oldValue.Blake2b, nil
+}
+
+// ResetBlake2b resets all changes to the "blake2b" field.
+func (m *PkgMutation) ResetBlake2b() {
+	m.blake2b = nil
+}
+
+// SetSha512 sets the "sha512" field.
+func (m *PkgMutation) SetSha512(s string) {
+	m.sha512 = &s
+}
+
+// Sha512 returns the value of the "sha512" field in the mutation.
+func (m *PkgMutation) Sha512() (r string, exists bool) {
+	v := m.sha512
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldSha512 returns the old "sha512" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldSha512(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSha512 is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSha512 requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSha512: %w", err)
+	}
+	return oldValue.Sha512, nil
+}
+
+// ResetSha512 resets all changes to the "sha512" field.
+func (m *PkgMutation) ResetSha512() {
+	m.sha512 = nil
+}
+
+// SetMtime sets the "mtime" field.
+func (m *PkgMutation) SetMtime(t time.Time) {
+	m.mtime = &t
+}
+
+// Mtime returns the value of the "mtime" field in the mutation.
+func (m *PkgMutation) Mtime() (r time.Time, exists bool) {
+	v := m.mtime
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMtime returns the old "mtime" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldMtime(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMtime is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMtime requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMtime: %w", err)
+	}
+	return oldValue.Mtime, nil
+}
+
+// ResetMtime resets all changes to the "mtime" field.
+func (m *PkgMutation) ResetMtime() {
+	m.mtime = nil
+}
+
+// SetTargetID sets the "target_id" field.
+func (m *PkgMutation) SetTargetID(u uuid.UUID) {
+	m.target = &u
+}
+
+// TargetID returns the value of the "target_id" field in the mutation.
+func (m *PkgMutation) TargetID() (r uuid.UUID, exists bool) {
+	v := m.target
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTargetID returns the old "target_id" field's value of the Pkg entity.
+// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PkgMutation) OldTargetID(ctx context.Context) (v uuid.UUID, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTargetID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTargetID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTargetID: %w", err)
+	}
+	return oldValue.TargetID, nil
+}
+
+// ResetTargetID resets all changes to the "target_id" field.
+func (m *PkgMutation) ResetTargetID() {
+	m.target = nil
+}
+
+// ClearTarget clears the "target" edge to the Target entity.
+func (m *PkgMutation) ClearTarget() {
+	m.clearedtarget = true
+	m.clearedFields[pkg.FieldTargetID] = struct{}{}
+}
+
+// TargetCleared reports if the "target" edge to the Target entity was cleared.
+func (m *PkgMutation) TargetCleared() bool {
+	return m.clearedtarget
+}
+
+// TargetIDs returns the "target" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// TargetID instead. It exists only for internal usage by the builders.
+func (m *PkgMutation) TargetIDs() (ids []uuid.UUID) {
+	if id := m.target; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetTarget resets all changes to the "target" edge.
+func (m *PkgMutation) ResetTarget() {
+	m.target = nil
+	m.clearedtarget = false
+}
+
+// Where appends a list predicates to the PkgMutation builder.
+func (m *PkgMutation) Where(ps ...predicate.Pkg) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the PkgMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *PkgMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Pkg, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *PkgMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *PkgMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (Pkg).
+func (m *PkgMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *PkgMutation) Fields() []string {
+	fields := make([]string, 0, 17)
+	if m.repository != nil {
+		fields = append(fields, pkg.FieldRepository)
+	}
+	if m.category != nil {
+		fields = append(fields, pkg.FieldCategory)
+	}
+	if m.name != nil {
+		fields = append(fields, pkg.FieldName)
+	}
+	if m.version != nil {
+		fields = append(fields, pkg.FieldVersion)
+	}
+	if m.revision != nil {
+		fields = append(fields, pkg.FieldRevision)
+	}
+	if m.build_id != nil {
+		fields = append(fields, pkg.FieldBuildID)
+	}
+	if m.package_fields != nil {
+		fields = append(fields, pkg.FieldPackageFields)
+	}
+	if m.format != nil {
+		fields = append(fields, pkg.FieldFormat)
+	}
+	if m._path != nil {
+		fields = append(fields, pkg.FieldPath)
+	}
+	if m.object_key != nil {
+		fields = append(fields, pkg.FieldObjectKey)
+	}
+	if m.size != nil {
+		fields = append(fields, pkg.FieldSize)
+	}
+	if m.sha1 != nil {
+		fields = append(fields, pkg.FieldSha1)
+	}
+	if m.md5 != nil {
+		fields = append(fields, pkg.FieldMd5)
+	}
+	if m.blake2b != nil {
+		fields = append(fields, pkg.FieldBlake2b)
+	}
+	if m.sha512 != nil {
+		fields = append(fields, pkg.FieldSha512)
+	}
+	if m.mtime != nil {
+		fields = append(fields, pkg.FieldMtime)
+	}
+	if m.target != nil {
+		fields = append(fields, pkg.FieldTargetID)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *PkgMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case pkg.FieldRepository:
+		return m.Repository()
+	case pkg.FieldCategory:
+		return m.Category()
+	case pkg.FieldName:
+		return m.Name()
+	case pkg.FieldVersion:
+		return m.Version()
+	case pkg.FieldRevision:
+		return m.Revision()
+	case pkg.FieldBuildID:
+		return m.BuildID()
+	case pkg.FieldPackageFields:
+		return m.PackageFields()
+	case pkg.FieldFormat:
+		return m.Format()
+	case pkg.FieldPath:
+		return m.Path()
+	case pkg.FieldObjectKey:
+		return m.ObjectKey()
+	case pkg.FieldSize:
+		return m.Size()
+	case pkg.FieldSha1:
+		return m.Sha1()
+	case pkg.FieldMd5:
+		return m.Md5()
+	case pkg.FieldBlake2b:
+		return m.Blake2b()
+	case pkg.FieldSha512:
+		return m.Sha512()
+	case pkg.FieldMtime:
+		return m.Mtime()
+	case pkg.FieldTargetID:
+		return m.TargetID()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *PkgMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case pkg.FieldRepository:
+		return m.OldRepository(ctx)
+	case pkg.FieldCategory:
+		return m.OldCategory(ctx)
+	case pkg.FieldName:
+		return m.OldName(ctx)
+	case pkg.FieldVersion:
+		return m.OldVersion(ctx)
+	case pkg.FieldRevision:
+		return m.OldRevision(ctx)
+	case pkg.FieldBuildID:
+		return m.OldBuildID(ctx)
+	case pkg.FieldPackageFields:
+		return m.OldPackageFields(ctx)
+	case pkg.FieldFormat:
+		return m.OldFormat(ctx)
+	case pkg.FieldPath:
+		return m.OldPath(ctx)
+	case pkg.FieldObjectKey:
+		return m.OldObjectKey(ctx)
+	case pkg.FieldSize:
+		return m.OldSize(ctx)
+	case pkg.FieldSha1:
+		return m.OldSha1(ctx)
+	case pkg.FieldMd5:
+		return m.OldMd5(ctx)
+	case pkg.FieldBlake2b:
+		return m.OldBlake2b(ctx)
+	case pkg.FieldSha512:
+		return m.OldSha512(ctx)
+	case pkg.FieldMtime:
+		return m.OldMtime(ctx)
+	case pkg.FieldTargetID:
+		return m.OldTargetID(ctx)
+	}
+	return nil, fmt.Errorf("unknown Pkg field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *PkgMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case pkg.FieldRepository:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRepository(v)
+		return nil
+	case pkg.FieldCategory:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCategory(v)
+		return nil
+	case pkg.FieldName:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetName(v)
+		return nil
+	case pkg.FieldVersion:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetVersion(v)
+		return nil
+	case pkg.FieldRevision:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRevision(v)
+		return nil
+	case pkg.FieldBuildID:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBuildID(v)
+		return nil
+	case pkg.FieldPackageFields:
+		v, ok := value.(*parser.PackageCommon)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPackageFields(v)
+		return nil
+	case pkg.FieldFormat:
+		v, ok := value.(pkg.Format)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetFormat(v)
+		return nil
+	case pkg.FieldPath:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPath(v)
+		return nil
+	case pkg.FieldObjectKey:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetObjectKey(v)
+		return nil
+	case pkg.FieldSize:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSize(v)
+		return nil
+	case pkg.FieldSha1:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSha1(v)
+		return nil
+	case pkg.FieldMd5:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMd5(v)
+		return nil
+	case pkg.FieldBlake2b:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBlake2b(v)
+		return nil
+	case pkg.FieldSha512:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSha512(v)
+		return nil
+	case pkg.FieldMtime:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMtime(v)
+		return nil
+	case pkg.FieldTargetID:
+		v, ok := value.(uuid.UUID)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTargetID(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Pkg field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *PkgMutation) AddedFields() []string {
+	var fields []string
+	if m.addbuild_id != nil {
+		fields = append(fields, pkg.FieldBuildID)
+	}
+	if m.addsize != nil {
+		fields = append(fields, pkg.FieldSize)
+	}
+	return fields
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *PkgMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	case pkg.FieldBuildID:
+		return m.AddedBuildID()
+	case pkg.FieldSize:
+		return m.AddedSize()
+	}
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *PkgMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	case pkg.FieldBuildID:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddBuildID(v)
+		return nil
+	case pkg.FieldSize:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddSize(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Pkg numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *PkgMutation) ClearedFields() []string {
+	return nil
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *PkgMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *PkgMutation) ClearField(name string) error {
+	return fmt.Errorf("unknown Pkg nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *PkgMutation) ResetField(name string) error {
+	switch name {
+	case pkg.FieldRepository:
+		m.ResetRepository()
+		return nil
+	case pkg.FieldCategory:
+		m.ResetCategory()
+		return nil
+	case pkg.FieldName:
+		m.ResetName()
+		return nil
+	case pkg.FieldVersion:
+		m.ResetVersion()
+		return nil
+	case pkg.FieldRevision:
+		m.ResetRevision()
+		return nil
+	case pkg.FieldBuildID:
+		m.ResetBuildID()
+		return nil
+	case pkg.FieldPackageFields:
+		m.ResetPackageFields()
+		return nil
+	case pkg.FieldFormat:
+		m.ResetFormat()
+		return nil
+	case pkg.FieldPath:
+		m.ResetPath()
+		return nil
+	case pkg.FieldObjectKey:
+		m.ResetObjectKey()
+		return nil
+	case pkg.FieldSize:
+		m.ResetSize()
+		return nil
+	case pkg.FieldSha1:
+		m.ResetSha1()
+		return nil
+	case pkg.FieldMd5:
+		m.ResetMd5()
+		return nil
+	case pkg.FieldBlake2b:
+		m.ResetBlake2b()
+		return nil
+	case pkg.FieldSha512:
+		m.ResetSha512()
+		return nil
+	case pkg.FieldMtime:
+		m.ResetMtime()
+		return nil
+	case pkg.FieldTargetID:
+		m.ResetTargetID()
+		return nil
+	}
+	return fmt.Errorf("unknown Pkg field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *PkgMutation) AddedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.target != nil {
+		edges = append(edges, pkg.EdgeTarget)
+	}
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *PkgMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case pkg.EdgeTarget:
+		if id := m.target; id != nil {
+			return []ent.Value{*id}
+		}
+	}
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *PkgMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 1)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *PkgMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *PkgMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.clearedtarget {
+		edges = append(edges, pkg.EdgeTarget)
+	}
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *PkgMutation) EdgeCleared(name string) bool {
+	switch name {
+	case pkg.EdgeTarget:
+		return m.clearedtarget
+	}
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *PkgMutation) ClearEdge(name string) error {
+	switch name {
+	case pkg.EdgeTarget:
+		m.ClearTarget()
+		return nil
+	}
+	return fmt.Errorf("unknown Pkg unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *PkgMutation) ResetEdge(name string) error {
+	switch name {
+	case pkg.EdgeTarget:
+		m.ResetTarget()
+		return nil
+	}
+	return fmt.Errorf("unknown Pkg edge %s", name)
+}
+
+// TargetMutation represents an operation that mutates the Target nodes in the graph.
+type TargetMutation struct {
+	config
+	op               Op
+	typ              string
+	id               *uuid.UUID
+	name             *string
+	description      *string
+	owner            *string
+	private          *bool
+	profile          **parser.Profile
+	index_updated_at *time.Time
+	clearedFields    map[string]struct{}
+	packages         map[uuid.UUID]struct{}
+	removedpackages  map[uuid.UUID]struct{}
+	clearedpackages  bool
+	tokens           map[uuid.UUID]struct{}
+	removedtokens    map[uuid.UUID]struct{}
+	clearedtokens    bool
+	done             bool
+	oldValue         func(context.Context) (*Target, error)
+	predicates       []predicate.Target
+}
+
+var _ ent.Mutation = (*TargetMutation)(nil)
+
+// targetOption allows management of the mutation configuration using functional options.
+type targetOption func(*TargetMutation)
+
+// newTargetMutation creates new mutation for the Target entity.
+func newTargetMutation(c config, op Op, opts ...targetOption) *TargetMutation {
+	m := &TargetMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeTarget,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withTargetID sets the ID field of the mutation.
+func withTargetID(id uuid.UUID) targetOption {
+	return func(m *TargetMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *Target
+		)
+		m.oldValue = func(ctx context.Context) (*Target, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().Target.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withTarget sets the old Target of the mutation.
+func withTarget(node *Target) targetOption {
+	return func(m *TargetMutation) {
+		m.oldValue = func(context.Context) (*Target, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m TargetMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m TargetMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// SetID sets the value of the id field. Note that this
+// operation is only accepted on creation of Target entities.
+func (m *TargetMutation) SetID(id uuid.UUID) {
+	m.id = &id
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *TargetMutation) ID() (id uuid.UUID, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *TargetMutation) IDs(ctx context.Context) ([]uuid.UUID, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []uuid.UUID{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Target.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetName sets the "name" field.
+func (m *TargetMutation) SetName(s string) {
+	m.name = &s
+}
+
+// Name returns the value of the "name" field in the mutation.
+func (m *TargetMutation) Name() (r string, exists bool) {
+	v := m.name
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldName returns the old "name" field's value of the Target entity.
+// If the Target object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TargetMutation) OldName(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldName is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldName requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldName: %w", err)
+	}
+	return oldValue.Name, nil
+}
+
+// ResetName resets all changes to the "name" field.
+func (m *TargetMutation) ResetName() {
+	m.name = nil
+}
+
+// SetDescription sets the "description" field.
+func (m *TargetMutation) SetDescription(s string) {
+	m.description = &s
+}
+
+// Description returns the value of the "description" field in the mutation.
+func (m *TargetMutation) Description() (r string, exists bool) {
+	v := m.description
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDescription returns the old "description" field's value of the Target entity.
+// If the Target object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TargetMutation) OldDescription(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDescription is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDescription requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDescription: %w", err)
+	}
+	return oldValue.Description, nil
+}
+
+// ResetDescription resets all changes to the "description" field.
+func (m *TargetMutation) ResetDescription() {
+	m.description = nil
+}
+
+// SetOwner sets the "owner" field.
+func (m *TargetMutation) SetOwner(s string) {
+	m.owner = &s
+}
+
+// Owner returns the value of the "owner" field in the mutation.
+func (m *TargetMutation) Owner() (r string, exists bool) {
+	v := m.owner
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldOwner returns the old "owner" field's value of the Target entity.
+// If the Target object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TargetMutation) OldOwner(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldOwner is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldOwner requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldOwner: %w", err)
+	}
+	return oldValue.Owner, nil
+}
+
+// ResetOwner resets all changes to the "owner" field.
+func (m *TargetMutation) ResetOwner() {
+	m.owner = nil
+}
+
+// SetPrivate sets the "private" field.
+func (m *TargetMutation) SetPrivate(b bool) {
+	m.private = &b
+}
+
+// Private returns the value of the "private" field in the mutation.
+func (m *TargetMutation) Private() (r bool, exists bool) {
+	v := m.private
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPrivate returns the old "private" field's value of the Target entity.
+// If the Target object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TargetMutation) OldPrivate(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPrivate is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPrivate requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPrivate: %w", err)
+	}
+	return oldValue.Private, nil
+}
+
+// ResetPrivate resets all changes to the "private" field.
+func (m *TargetMutation) ResetPrivate() {
+	m.private = nil
+}
+
+// SetProfile sets the "profile" field.
+func (m *TargetMutation) SetProfile(pa *parser.Profile) {
+	m.profile = &pa
+}
+
+// Profile returns the value of the "profile" field in the mutation.
+func (m *TargetMutation) Profile() (r *parser.Profile, exists bool) {
+	v := m.profile
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldProfile returns the old "profile" field's value of the Target entity.
+// If the Target object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TargetMutation) OldProfile(ctx context.Context) (v *parser.Profile, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldProfile is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldProfile requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldProfile: %w", err)
+	}
+	return oldValue.Profile, nil
+}
+
+// ClearProfile clears the value of the "profile" field.
+func (m *TargetMutation) ClearProfile() {
+	m.profile = nil
+	m.clearedFields[target.FieldProfile] = struct{}{}
+}
+
+// ProfileCleared returns if the "profile" field was cleared in this mutation.
+func (m *TargetMutation) ProfileCleared() bool {
+	_, ok := m.clearedFields[target.FieldProfile]
+	return ok
+}
+
+// ResetProfile resets all changes to the "profile" field.
+func (m *TargetMutation) ResetProfile() {
+	m.profile = nil
+	delete(m.clearedFields, target.FieldProfile)
+}
+
+// SetIndexUpdatedAt sets the "index_updated_at" field.
+func (m *TargetMutation) SetIndexUpdatedAt(t time.Time) {
+	m.index_updated_at = &t
+}
+
+// IndexUpdatedAt returns the value of the "index_updated_at" field in the mutation.
+func (m *TargetMutation) IndexUpdatedAt() (r time.Time, exists bool) {
+	v := m.index_updated_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldIndexUpdatedAt returns the old "index_updated_at" field's value of the Target entity.
+// If the Target object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TargetMutation) OldIndexUpdatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldIndexUpdatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldIndexUpdatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldIndexUpdatedAt: %w", err)
+	}
+	return oldValue.IndexUpdatedAt, nil
+}
+
+// ResetIndexUpdatedAt resets all changes to the "index_updated_at" field.
+func (m *TargetMutation) ResetIndexUpdatedAt() {
+	m.index_updated_at = nil
+}
+
+// AddPackageIDs adds the "packages" edge to the Pkg entity by ids.
+func (m *TargetMutation) AddPackageIDs(ids ...uuid.UUID) {
+	if m.packages == nil {
+		m.packages = make(map[uuid.UUID]struct{})
+	}
+	for i := range ids {
+		m.packages[ids[i]] = struct{}{}
+	}
+}
+
+// ClearPackages clears the "packages" edge to the Pkg entity.
+func (m *TargetMutation) ClearPackages() {
+	m.clearedpackages = true
+}
+
+// PackagesCleared reports if the "packages" edge to the Pkg entity was cleared.
+func (m *TargetMutation) PackagesCleared() bool {
+	return m.clearedpackages
+}
+
+// RemovePackageIDs removes the "packages" edge to the Pkg entity by IDs.
+func (m *TargetMutation) RemovePackageIDs(ids ...uuid.UUID) {
+	if m.removedpackages == nil {
+		m.removedpackages = make(map[uuid.UUID]struct{})
+	}
+	for i := range ids {
+		delete(m.packages, ids[i])
+		m.removedpackages[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedPackages returns the removed IDs of the "packages" edge to the Pkg entity.
+func (m *TargetMutation) RemovedPackagesIDs() (ids []uuid.UUID) {
+	for id := range m.removedpackages {
+		ids = append(ids, id)
+	}
+	return
 }
 
-// ResetPackageFields resets all changes to the "package_fields" field.
-func (m *PkgMutation) ResetPackageFields() {
-	m.package_fields = nil
+// PackagesIDs returns the "packages" edge IDs in the mutation.
+func (m *TargetMutation) PackagesIDs() (ids []uuid.UUID) {
+	for id := range m.packages {
+		ids = append(ids, id)
+	}
+	return
 }
 
-// SetTargetID sets the "target_id" field.
-func (m *PkgMutation) SetTargetID(u uuid.UUID) {
-	m.target = &u
+// ResetPackages resets all changes to the "packages" edge.
+func (m *TargetMutation) ResetPackages() {
+	m.packages = nil
+	m.clearedpackages = false
+	m.removedpackages = nil
 }
 
-// TargetID returns the value of the "target_id" field in the mutation.
-func (m *PkgMutation) TargetID() (r uuid.UUID, exists bool) {
-	v := m.target
-	if v == nil {
-		return
+// AddTokenIDs adds the "tokens" edge to the Token entity by ids.
+func (m *TargetMutation) AddTokenIDs(ids ...uuid.UUID) {
+	if m.tokens == nil {
+		m.tokens = make(map[uuid.UUID]struct{})
+	}
+	for i := range ids {
+		m.tokens[ids[i]] = struct{}{}
 	}
-	return *v, true
 }
 
-// OldTargetID returns the old "target_id" field's value of the Pkg entity.
-// If the Pkg object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PkgMutation) OldTargetID(ctx context.Context) (v uuid.UUID, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldTargetID is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldTargetID requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldTargetID: %w", err)
-	}
-	return oldValue.TargetID, nil
+// ClearTokens clears the "tokens" edge to the Token entity.
+func (m *TargetMutation) ClearTokens() {
+	m.clearedtokens = true
 }
 
-// ResetTargetID resets all changes to the "target_id" field.
-func (m *PkgMutation) ResetTargetID() {
-	m.target = nil
+// TokensCleared reports if the "tokens" edge to the Token entity was cleared.
+func (m *TargetMutation) TokensCleared() bool {
+	return m.clearedtokens
 }
 
-// ClearTarget clears the "target" edge to the Target entity.
-func (m *PkgMutation) ClearTarget() {
-	m.clearedtarget = true
-	m.clearedFields[pkg.FieldTargetID] = struct{}{}
+// RemoveTokenIDs removes the "tokens" edge to the Token entity by IDs.
+func (m *TargetMutation) RemoveTokenIDs(ids ...uuid.UUID) {
+	if m.removedtokens == nil {
+		m.removedtokens = make(map[uuid.UUID]struct{})
+	}
+	for i := range ids {
+		delete(m.tokens, ids[i])
+		m.removedtokens[ids[i]] = struct{}{}
+	}
 }
 
-// TargetCleared reports if the "target" edge to the Target entity was cleared.
-func (m *PkgMutation) TargetCleared() bool {
-	return m.clearedtarget
+// RemovedTokens returns the removed IDs of the "tokens" edge to the Token entity.
+func (m *TargetMutation) RemovedTokensIDs() (ids []uuid.UUID) {
+	for id := range m.removedtokens {
+		ids = append(ids, id)
+	}
+	return
 }
 
-// TargetIDs returns the "target" edge IDs in the mutation.
-// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
-// TargetID instead. It exists only for internal usage by the builders.
-func (m *PkgMutation) TargetIDs() (ids []uuid.UUID) {
-	if id := m.target; id != nil {
-		ids = append(ids, *id)
+// TokensIDs returns the "tokens" edge IDs in the mutation.
+func (m *TargetMutation) TokensIDs() (ids []uuid.UUID) {
+	for id := range m.tokens {
+		ids = append(ids, id)
 	}
 	return
 }
 
-// ResetTarget resets all changes to the "target" edge.
-func (m *PkgMutation) ResetTarget() {
-	m.target = nil
-	m.clearedtarget = false
+// ResetTokens resets all changes to the "tokens" edge.
+func (m *TargetMutation) ResetTokens() {
+	m.tokens = nil
+	m.clearedtokens = false
+	m.removedtokens = nil
 }
 
-// Where appends a list predicates to the PkgMutation builder.
-func (m *PkgMutation) Where(ps ...predicate.Pkg) {
+// Where appends a list predicates to the TargetMutation builder.
+func (m *TargetMutation) Where(ps ...predicate.Target) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the PkgMutation builder. Using this method,
+// WhereP appends storage-level predicates to the TargetMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *PkgMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Pkg, len(ps))
+func (m *TargetMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Target, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -412,42 +1833,42 @@ func (m *PkgMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *PkgMutation) Op() Op {
+func (m *TargetMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *PkgMutation) SetOp(op Op) {
+func (m *TargetMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Pkg).
-func (m *PkgMutation) Type() string {
+// Type returns the node type of this mutation (Target).
+func (m *TargetMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *PkgMutation) Fields() []string {
+func (m *TargetMutation) Fields() []string {
 	fields := make([]string, 0, 6)
-	if m.repository != nil {
-		fields = append(fields, pkg.FieldRepository)
+	if m.name != nil {
+		fields = append(fields, target.FieldName)
 	}
-	if m.category != nil {
-		fields = append(fields, pkg.FieldCategory)
+	if m.description != nil {
+		fields = append(fields, target.FieldDescription)
 	}
-	if m.name != nil {
-		fields = append(fields, pkg.FieldName)
+	if m.owner != nil {
+		fields = append(fields, target.FieldOwner)
 	}
-	if m.version != nil {
-		fields = append(fields, pkg.FieldVersion)
+	if m.private != nil {
+		fields = append(fields, target.FieldPrivate)
 	}
-	if m.package_fields != nil {
-		fields = append(fields, pkg.FieldPackageFields)
+	if m.profile != nil {
+		fields = append(fields, target.FieldProfile)
 	}
-	if m.target != nil {
-		fields = append(fields, pkg.FieldTargetID)
+	if m.index_updated_at != nil {
+		fields = append(fields, target.FieldIndexUpdatedAt)
 	}
 	return fields
 }
@@ -455,20 +1876,20 @@ func (m *PkgMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *PkgMutation) Field(name string) (ent.Value, bool) {
+func (m *TargetMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case pkg.FieldRepository:
-		return m.Repository()
-	case pkg.FieldCategory:
-		return m.Category()
-	case pkg.FieldName:
+	case target.FieldName:
 		return m.Name()
-	case pkg.FieldVersion:
-		return m.Version()
-	case pkg.FieldPackageFields:
-		return m.PackageFields()
-	case pkg.FieldTargetID:
-		return m.TargetID()
+	case target.FieldDescription:
+		return m.Description()
+	case target.FieldOwner:
+		return m.Owner()
+	case target.FieldPrivate:
+		return m.Private()
+	case target.FieldProfile:
+		return m.Profile()
+	case target.FieldIndexUpdatedAt:
+		return m.IndexUpdatedAt()
 	}
 	return nil, false
 }
@@ -476,243 +1897,293 @@ func (m *PkgMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *PkgMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *TargetMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case pkg.FieldRepository:
-		return m.OldRepository(ctx)
-	case pkg.FieldCategory:
-		return m.OldCategory(ctx)
-	case pkg.FieldName:
+	case target.FieldName:
 		return m.OldName(ctx)
-	case pkg.FieldVersion:
-		return m.OldVersion(ctx)
-	case pkg.FieldPackageFields:
-		return m.OldPackageFields(ctx)
-	case pkg.FieldTargetID:
-		return m.OldTargetID(ctx)
+	case target.FieldDescription:
+		return m.OldDescription(ctx)
+	case target.FieldOwner:
+		return m.OldOwner(ctx)
+	case target.FieldPrivate:
+		return m.OldPrivate(ctx)
+	case target.FieldProfile:
+		return m.OldProfile(ctx)
+	case target.FieldIndexUpdatedAt:
+		return m.OldIndexUpdatedAt(ctx)
 	}
-	return nil, fmt.Errorf("unknown Pkg field %s", name)
+	return nil, fmt.Errorf("unknown Target field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *PkgMutation) SetField(name string, value ent.Value) error {
+func (m *TargetMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case pkg.FieldRepository:
+	case target.FieldName:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetRepository(v)
+		m.SetName(v)
 		return nil
-	case pkg.FieldCategory:
+	case target.FieldDescription:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetCategory(v)
+		m.SetDescription(v)
 		return nil
-	case pkg.FieldName:
+	case target.FieldOwner:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetName(v)
+		m.SetOwner(v)
 		return nil
-	case pkg.FieldVersion:
-		v, ok := value.(string)
+	case target.FieldPrivate:
+		v, ok := value.(bool)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetVersion(v)
+		m.SetPrivate(v)
 		return nil
-	case pkg.FieldPackageFields:
-		v, ok := value.(*parser.PackageCommon)
+	case target.FieldProfile:
+		v, ok := value.(*parser.Profile)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetPackageFields(v)
+		m.SetProfile(v)
 		return nil
-	case pkg.FieldTargetID:
-		v, ok := value.(uuid.UUID)
+	case target.FieldIndexUpdatedAt:
+		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetTargetID(v)
+		m.SetIndexUpdatedAt(v)
 		return nil
 	}
-	return fmt.Errorf("unknown Pkg field %s", name)
+	return fmt.Errorf("unknown Target field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *PkgMutation) AddedFields() []string {
+func (m *TargetMutation) AddedFields() []string {
 	return nil
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *PkgMutation) AddedField(name string) (ent.Value, bool) {
+func (m *TargetMutation) AddedField(name string) (ent.Value, bool) {
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *PkgMutation) AddField(name string, value ent.Value) error {
+func (m *TargetMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown Pkg numeric field %s", name)
+	return fmt.Errorf("unknown Target numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *PkgMutation) ClearedFields() []string {
-	return nil
+func (m *TargetMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(target.FieldProfile) {
+		fields = append(fields, target.FieldProfile)
+	}
+	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *PkgMutation) FieldCleared(name string) bool {
+func (m *TargetMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *PkgMutation) ClearField(name string) error {
-	return fmt.Errorf("unknown Pkg nullable field %s", name)
+func (m *TargetMutation) ClearField(name string) error {
+	switch name {
+	case target.FieldProfile:
+		m.ClearProfile()
+		return nil
+	}
+	return fmt.Errorf("unknown Target nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *PkgMutation) ResetField(name string) error {
+func (m *TargetMutation) ResetField(name string) error {
 	switch name {
-	case pkg.FieldRepository:
-		m.ResetRepository()
+	case target.FieldName:
+		m.ResetName()
 		return nil
-	case pkg.FieldCategory:
-		m.ResetCategory()
+	case target.FieldDescription:
+		m.ResetDescription()
 		return nil
-	case pkg.FieldName:
-		m.ResetName()
+	case target.FieldOwner:
+		m.ResetOwner()
 		return nil
-	case pkg.FieldVersion:
-		m.ResetVersion()
+	case target.FieldPrivate:
+		m.ResetPrivate()
 		return nil
-	case pkg.FieldPackageFields:
-		m.ResetPackageFields()
+	case target.FieldProfile:
+		m.ResetProfile()
 		return nil
-	case pkg.FieldTargetID:
-		m.ResetTargetID()
+	case target.FieldIndexUpdatedAt:
+		m.ResetIndexUpdatedAt()
 		return nil
 	}
-	return fmt.Errorf("unknown Pkg field %s", name)
+	return fmt.Errorf("unknown Target field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *PkgMutation) AddedEdges() []string {
-	edges := make([]string, 0, 1)
-	if m.target != nil {
-		edges = append(edges, pkg.EdgeTarget)
+func (m *TargetMutation) AddedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.packages != nil {
+		edges = append(edges, target.EdgePackages)
+	}
+	if m.tokens != nil {
+		edges = append(edges, target.EdgeTokens)
 	}
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *PkgMutation) AddedIDs(name string) []ent.Value {
+func (m *TargetMutation) AddedIDs(name string) []ent.Value {
 	switch name {
-	case pkg.EdgeTarget:
-		if id := m.target; id != nil {
-			return []ent.Value{*id}
+	case target.EdgePackages:
+		ids := make([]ent.Value, 0, len(m.packages))
+		for id := range m.packages {
+			ids = append(ids, id)
+		}
+		return ids
+	case target.EdgeTokens:
+		ids := make([]ent.Value, 0, len(m.tokens))
+		for id := range m.tokens {
+			ids = append(ids, id)
 		}
+		return ids
 	}
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *PkgMutation) RemovedEdges() []string {
-	edges := make([]string, 0, 1)
+func (m *TargetMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.removedpackages != nil {
+		edges = append(edges, target.EdgePackages)
+	}
+	if m.removedtokens != nil {
+		edges = append(edges, target.EdgeTokens)
+	}
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *PkgMutation) RemovedIDs(name string) []ent.Value {
+func (m *TargetMutation) RemovedIDs(name string) []ent.Value {
+	switch name {
+	case target.EdgePackages:
+		ids := make([]ent.Value, 0, len(m.removedpackages))
+		for id := range m.removedpackages {
+			ids = append(ids, id)
+		}
+		return ids
+	case target.EdgeTokens:
+		ids := make([]ent.Value, 0, len(m.removedtokens))
+		for id := range m.removedtokens {
+			ids = append(ids, id)
+		}
+		return ids
+	}
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *PkgMutation) ClearedEdges() []string {
-	edges := make([]string, 0, 1)
-	if m.clearedtarget {
-		edges = append(edges, pkg.EdgeTarget)
+func (m *TargetMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.clearedpackages {
+		edges = append(edges, target.EdgePackages)
+	}
+	if m.clearedtokens {
+		edges = append(edges, target.EdgeTokens)
 	}
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *PkgMutation) EdgeCleared(name string) bool {
+func (m *TargetMutation) EdgeCleared(name string) bool {
 	switch name {
-	case pkg.EdgeTarget:
-		return m.clearedtarget
+	case target.EdgePackages:
+		return m.clearedpackages
+	case target.EdgeTokens:
+		return m.clearedtokens
 	}
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *PkgMutation) ClearEdge(name string) error {
-	switch name {
-	case pkg.EdgeTarget:
-		m.ClearTarget()
-		return nil
+func (m *TargetMutation) ClearEdge(name string) error {
+	switch name {
 	}
-	return fmt.Errorf("unknown Pkg unique edge %s", name)
+	return fmt.Errorf("unknown Target unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *PkgMutation) ResetEdge(name string) error {
+func (m *TargetMutation) ResetEdge(name string) error {
 	switch name {
-	case pkg.EdgeTarget:
-		m.ResetTarget()
+	case target.EdgePackages:
+		m.ResetPackages()
+		return nil
+	case target.EdgeTokens:
+		m.ResetTokens()
 		return nil
 	}
-	return fmt.Errorf("unknown Pkg edge %s", name)
+	return fmt.Errorf("unknown Target edge %s", name)
 }
 
-// TargetMutation represents an operation that mutates the Target nodes in the graph.
-type TargetMutation struct {
+// TokenMutation represents an operation that mutates the Token nodes in the graph.
+type TokenMutation struct {
 	config
-	op              Op
-	typ             string
-	id              *uuid.UUID
-	name            *string
-	clearedFields   map[string]struct{}
-	packages        map[uuid.UUID]struct{}
-	removedpackages map[uuid.UUID]struct{}
-	clearedpackages bool
-	done            bool
-	oldValue        func(context.Context) (*Target, error)
-	predicates      []predicate.Target
+	op             Op
+	typ            string
+	id             *uuid.UUID
+	name           *string
+	hash           *string
+	scopes         *[]string
+	appendscopes   []string
+	created_at     *time.Time
+	expires_at     *time.Time
+	clearedFields  map[string]struct{}
+	targets        map[uuid.UUID]struct{}
+	removedtargets map[uuid.UUID]struct{}
+	clearedtargets bool
+	done           bool
+	oldValue       func(context.Context) (*Token, error)
+	predicates     []predicate.Token
 }
 
-var _ ent.Mutation = (*TargetMutation)(nil)
+var _ ent.Mutation = (*TokenMutation)(nil)
 
-// targetOption allows management of the mutation configuration using functional options.
-type targetOption func(*TargetMutation)
+// tokenOption allows management of the mutation configuration using functional options.
+type tokenOption func(*TokenMutation)
 
-// newTargetMutation creates new mutation for the Target entity.
-func newTargetMutation(c config, op Op, opts ...targetOption) *TargetMutation {
-	m := &TargetMutation{
+// newTokenMutation creates new mutation for the Token entity.
+func newTokenMutation(c config, op Op, opts ...tokenOption) *TokenMutation {
+	m := &TokenMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeTarget,
+		typ:           TypeToken,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -721,20 +2192,20 @@ func newTargetMutation(c config, op Op, opts ...targetOption) *TargetMutation {
 	return m
 }
 
-// withTargetID sets the ID field of the mutation.
-func withTargetID(id uuid.UUID) targetOption {
-	return func(m *TargetMutation) {
+// withTokenID sets the ID field of the mutation.
+func withTokenID(id uuid.UUID) tokenOption {
+	return func(m *TokenMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *Target
+			value *Token
 		)
-		m.oldValue = func(ctx context.Context) (*Target, error) {
+		m.oldValue = func(ctx context.Context) (*Token, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().Target.Get(ctx, id)
+					value, err = m.Client().Token.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -743,10 +2214,10 @@ func withTargetID(id uuid.UUID) targetOption {
 	}
 }
 
-// withTarget sets the old Target of the mutation.
-func withTarget(node *Target) targetOption {
-	return func(m *TargetMutation) {
-		m.oldValue = func(context.Context) (*Target, error) {
+// withToken sets the old Token of the mutation.
+func withToken(node *Token) tokenOption {
+	return func(m *TokenMutation) {
+		m.oldValue = func(context.Context) (*Token, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -755,7 +2226,7 @@ func withTarget(node *Target) targetOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m TargetMutation) Client() *Client {
+func (m TokenMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -763,7 +2234,7 @@ func (m TargetMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m TargetMutation) Tx() (*Tx, error) {
+func (m TokenMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
@@ -773,14 +2244,14 @@ func (m TargetMutation) Tx() (*Tx, error) {
 }
 
 // SetID sets the value of the id field. Note that this
-// operation is only accepted on creation of Target entities.
-func (m *TargetMutation) SetID(id uuid.UUID) {
+// operation is only accepted on creation of Token entities.
+func (m *TokenMutation) SetID(id uuid.UUID) {
 	m.id = &id
 }
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *TargetMutation) ID() (id uuid.UUID, exists bool) {
+func (m *TokenMutation) ID() (id uuid.UUID, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -791,7 +2262,7 @@ func (m *TargetMutation) ID() (id uuid.UUID, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *TargetMutation) IDs(ctx context.Context) ([]uuid.UUID, error) {
+func (m *TokenMutation) IDs(ctx context.Context) ([]uuid.UUID, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -800,19 +2271,19 @@ func (m *TargetMutation) IDs(ctx context.Context) ([]uuid.UUID, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Target.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().Token.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
 // SetName sets the "name" field.
-func (m *TargetMutation) SetName(s string) {
+func (m *TokenMutation) SetName(s string) {
 	m.name = &s
 }
 
 // Name returns the value of the "name" field in the mutation.
-func (m *TargetMutation) Name() (r string, exists bool) {
+func (m *TokenMutation) Name() (r string, exists bool) {
 	v := m.name
 	if v == nil {
 		return
@@ -820,10 +2291,10 @@ func (m *TargetMutation) Name() (r string, exists bool) {
 	return *v, true
 }
 
-// OldName returns the old "name" field's value of the Target entity.
-// If the Target object wasn't provided to the builder, the object is fetched from the database.
+// OldName returns the old "name" field's value of the Token entity.
+// If the Token object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *TargetMutation) OldName(ctx context.Context) (v string, err error) {
+func (m *TokenMutation) OldName(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldName is only allowed on UpdateOne operations")
 	}
@@ -838,73 +2309,245 @@ func (m *TargetMutation) OldName(ctx context.Context) (v string, err error) {
 }
 
 // ResetName resets all changes to the "name" field.
-func (m *TargetMutation) ResetName() {
+func (m *TokenMutation) ResetName() {
 	m.name = nil
 }
 
-// AddPackageIDs adds the "packages" edge to the Pkg entity by ids.
-func (m *TargetMutation) AddPackageIDs(ids ...uuid.UUID) {
-	if m.packages == nil {
-		m.packages = make(map[uuid.UUID]struct{})
+// SetHash sets the "hash" field.
+func (m *TokenMutation) SetHash(s string) {
+	m.hash = &s
+}
+
+// Hash returns the value of the "hash" field in the mutation.
+func (m *TokenMutation) Hash() (r string, exists bool) {
+	v := m.hash
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldHash returns the old "hash" field's value of the Token entity.
+// If the Token object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TokenMutation) OldHash(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHash is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHash requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHash: %w", err)
+	}
+	return oldValue.Hash, nil
+}
+
+// ResetHash resets all changes to the "hash" field.
+func (m *TokenMutation) ResetHash() {
+	m.hash = nil
+}
+
+// SetScopes sets the "scopes" field.
+func (m *TokenMutation) SetScopes(s []string) {
+	m.scopes = &s
+	m.appendscopes = nil
+}
+
+// Scopes returns the value of the "scopes" field in the mutation.
+func (m *TokenMutation) Scopes() (r []string, exists bool) {
+	v := m.scopes
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldScopes returns the old "scopes" field's value of the Token entity.
+// If the Token object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TokenMutation) OldScopes(ctx context.Context) (v []string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldScopes is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldScopes requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldScopes: %w", err)
+	}
+	return oldValue.Scopes, nil
+}
+
+// AppendScopes adds s to the "scopes" field.
+func (m *TokenMutation) AppendScopes(s []string) {
+	m.appendscopes = append(m.appendscopes, s...)
+}
+
+// AppendedScopes returns the list of values that were appended to the "scopes" field in this mutation.
+func (m *TokenMutation) AppendedScopes() ([]string, bool) {
+	if len(m.appendscopes) == 0 {
+		return nil, false
+	}
+	return m.appendscopes, true
+}
+
+// ResetScopes resets all changes to the "scopes" field.
+func (m *TokenMutation) ResetScopes() {
+	m.scopes = nil
+	m.appendscopes = nil
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *TokenMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *TokenMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the Token entity.
+// If the Token object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TokenMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *TokenMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (m *TokenMutation) SetExpiresAt(t time.Time) {
+	m.expires_at = &t
+}
+
+// ExpiresAt returns the value of the "expires_at" field in the mutation.
+func (m *TokenMutation) ExpiresAt() (r time.Time, exists bool) {
+	v := m.expires_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldExpiresAt returns the old "expires_at" field's value of the Token entity.
+// If the Token object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TokenMutation) OldExpiresAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldExpiresAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldExpiresAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldExpiresAt: %w", err)
+	}
+	return oldValue.ExpiresAt, nil
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (m *TokenMutation) ClearExpiresAt() {
+	m.expires_at = nil
+	m.clearedFields[token.FieldExpiresAt] = struct{}{}
+}
+
+// ExpiresAtCleared returns if the "expires_at" field was cleared in this mutation.
+func (m *TokenMutation) ExpiresAtCleared() bool {
+	_, ok := m.clearedFields[token.FieldExpiresAt]
+	return ok
+}
+
+// ResetExpiresAt resets all changes to the "expires_at" field.
+func (m *TokenMutation) ResetExpiresAt() {
+	m.expires_at = nil
+	delete(m.clearedFields, token.FieldExpiresAt)
+}
+
+// AddTargetIDs adds the "targets" edge to the Target entity by ids.
+func (m *TokenMutation) AddTargetIDs(ids ...uuid.UUID) {
+	if m.targets == nil {
+		m.targets = make(map[uuid.UUID]struct{})
 	}
 	for i := range ids {
-		m.packages[ids[i]] = struct{}{}
+		m.targets[ids[i]] = struct{}{}
 	}
 }
 
-// ClearPackages clears the "packages" edge to the Pkg entity.
-func (m *TargetMutation) ClearPackages() {
-	m.clearedpackages = true
+// ClearTargets clears the "targets" edge to the Target entity.
+func (m *TokenMutation) ClearTargets() {
+	m.clearedtargets = true
 }
 
-// PackagesCleared reports if the "packages" edge to the Pkg entity was cleared.
-func (m *TargetMutation) PackagesCleared() bool {
-	return m.clearedpackages
+// TargetsCleared reports if the "targets" edge to the Target entity was cleared.
+func (m *TokenMutation) TargetsCleared() bool {
+	return m.clearedtargets
 }
 
-// RemovePackageIDs removes the "packages" edge to the Pkg entity by IDs.
-func (m *TargetMutation) RemovePackageIDs(ids ...uuid.UUID) {
-	if m.removedpackages == nil {
-		m.removedpackages = make(map[uuid.UUID]struct{})
+// RemoveTargetIDs removes the "targets" edge to the Target entity by IDs.
+func (m *TokenMutation) RemoveTargetIDs(ids ...uuid.UUID) {
+	if m.removedtargets == nil {
+		m.removedtargets = make(map[uuid.UUID]struct{})
 	}
 	for i := range ids {
-		delete(m.packages, ids[i])
-		m.removedpackages[ids[i]] = struct{}{}
+		delete(m.targets, ids[i])
+		m.removedtargets[ids[i]] = struct{}{}
 	}
 }
 
-// RemovedPackages returns the removed IDs of the "packages" edge to the Pkg entity.
-func (m *TargetMutation) RemovedPackagesIDs() (ids []uuid.UUID) {
-	for id := range m.removedpackages {
+// RemovedTargets returns the removed IDs of the "targets" edge to the Target entity.
+func (m *TokenMutation) RemovedTargetsIDs() (ids []uuid.UUID) {
+	for id := range m.removedtargets {
 		ids = append(ids, id)
 	}
 	return
 }
 
-// PackagesIDs returns the "packages" edge IDs in the mutation.
-func (m *TargetMutation) PackagesIDs() (ids []uuid.UUID) {
-	for id := range m.packages {
+// TargetsIDs returns the "targets" edge IDs in the mutation.
+func (m *TokenMutation) TargetsIDs() (ids []uuid.UUID) {
+	for id := range m.targets {
 		ids = append(ids, id)
 	}
 	return
 }
 
-// ResetPackages resets all changes to the "packages" edge.
-func (m *TargetMutation) ResetPackages() {
-	m.packages = nil
-	m.clearedpackages = false
-	m.removedpackages = nil
+// ResetTargets resets all changes to the "targets" edge.
+func (m *TokenMutation) ResetTargets() {
+	m.targets = nil
+	m.clearedtargets = false
+	m.removedtargets = nil
 }
 
-// Where appends a list predicates to the TargetMutation builder.
-func (m *TargetMutation) Where(ps ...predicate.Target) {
+// Where appends a list predicates to the TokenMutation builder.
+func (m *TokenMutation) Where(ps ...predicate.Token) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the TargetMutation builder. Using this method,
+// WhereP appends storage-level predicates to the TokenMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *TargetMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Target, len(ps))
+func (m *TokenMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Token, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -912,27 +2555,39 @@ func (m *TargetMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *TargetMutation) Op() Op {
+func (m *TokenMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *TargetMutation) SetOp(op Op) {
+func (m *TokenMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Target).
-func (m *TargetMutation) Type() string {
+// Type returns the node type of this mutation (Token).
+func (m *TokenMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *TargetMutation) Fields() []string {
-	fields := make([]string, 0, 1)
+func (m *TokenMutation) Fields() []string {
+	fields := make([]string, 0, 5)
 	if m.name != nil {
-		fields = append(fields, target.FieldName)
+		fields = append(fields, token.FieldName)
+	}
+	if m.hash != nil {
+		fields = append(fields, token.FieldHash)
+	}
+	if m.scopes != nil {
+		fields = append(fields, token.FieldScopes)
+	}
+	if m.created_at != nil {
+		fields = append(fields, token.FieldCreatedAt)
+	}
+	if m.expires_at != nil {
+		fields = append(fields, token.FieldExpiresAt)
 	}
 	return fields
 }
@@ -940,10 +2595,18 @@ func (m *TargetMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *TargetMutation) Field(name string) (ent.Value, bool) {
+func (m *TokenMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case target.FieldName:
+	case token.FieldName:
 		return m.Name()
+	case token.FieldHash:
+		return m.Hash()
+	case token.FieldScopes:
+		return m.Scopes()
+	case token.FieldCreatedAt:
+		return m.CreatedAt()
+	case token.FieldExpiresAt:
+		return m.ExpiresAt()
 	}
 	return nil, false
 }
@@ -951,98 +2614,155 @@ func (m *TargetMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *TargetMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *TokenMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case target.FieldName:
+	case token.FieldName:
 		return m.OldName(ctx)
+	case token.FieldHash:
+		return m.OldHash(ctx)
+	case token.FieldScopes:
+		return m.OldScopes(ctx)
+	case token.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	case token.FieldExpiresAt:
+		return m.OldExpiresAt(ctx)
 	}
-	return nil, fmt.Errorf("unknown Target field %s", name)
+	return nil, fmt.Errorf("unknown Token field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *TargetMutation) SetField(name string, value ent.Value) error {
+func (m *TokenMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case target.FieldName:
+	case token.FieldName:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetName(v)
 		return nil
+	case token.FieldHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHash(v)
+		return nil
+	case token.FieldScopes:
+		v, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetScopes(v)
+		return nil
+	case token.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedAt(v)
+		return nil
+	case token.FieldExpiresAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetExpiresAt(v)
+		return nil
 	}
-	return fmt.Errorf("unknown Target field %s", name)
+	return fmt.Errorf("unknown Token field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *TargetMutation) AddedFields() []string {
+func (m *TokenMutation) AddedFields() []string {
 	return nil
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *TargetMutation) AddedField(name string) (ent.Value, bool) {
+func (m *TokenMutation) AddedField(name string) (ent.Value, bool) {
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *TargetMutation) AddField(name string, value ent.Value) error {
+func (m *TokenMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown Target numeric field %s", name)
+	return fmt.Errorf("unknown Token numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *TargetMutation) ClearedFields() []string {
-	return nil
+func (m *TokenMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(token.FieldExpiresAt) {
+		fields = append(fields, token.FieldExpiresAt)
+	}
+	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *TargetMutation) FieldCleared(name string) bool {
+func (m *TokenMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *TargetMutation) ClearField(name string) error {
-	return fmt.Errorf("unknown Target nullable field %s", name)
+func (m *TokenMutation) ClearField(name string) error {
+	switch name {
+	case token.FieldExpiresAt:
+		m.ClearExpiresAt()
+		return nil
+	}
+	return fmt.Errorf("unknown Token nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *TargetMutation) ResetField(name string) error {
+func (m *TokenMutation) ResetField(name string) error {
 	switch name {
-	case target.FieldName:
+	case token.FieldName:
 		m.ResetName()
 		return nil
+	case token.FieldHash:
+		m.ResetHash()
+		return nil
+	case token.FieldScopes:
+		m.ResetScopes()
+		return nil
+	case token.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	case token.FieldExpiresAt:
+		m.ResetExpiresAt()
+		return nil
 	}
-	return fmt.Errorf("unknown Target field %s", name)
+	return fmt.Errorf("unknown Token field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *TargetMutation) AddedEdges() []string {
+func (m *TokenMutation) AddedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.packages != nil {
-		edges = append(edges, target.EdgePackages)
+	if m.targets != nil {
+		edges = append(edges, token.EdgeTargets)
 	}
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *TargetMutation) AddedIDs(name string) []ent.Value {
+func (m *TokenMutation) AddedIDs(name string) []ent.Value {
 	switch name {
-	case target.EdgePackages:
-		ids := make([]ent.Value, 0, len(m.packages))
-		for id := range m.packages {
+	case token.EdgeTargets:
+		ids := make([]ent.Value, 0, len(m.targets))
+		for id := range m.targets {
 			ids = append(ids, id)
 		}
 		return ids
@@ -1051,21 +2771,21 @@ func (m *TargetMutation) AddedIDs(name string) []ent.Value {
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *TargetMutation) RemovedEdges() []string {
+func (m *TokenMutation) RemovedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.removedpackages != nil {
-		edges = append(edges, target.EdgePackages)
+	if m.removedtargets != nil {
+		edges = append(edges, token.EdgeTargets)
 	}
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *TargetMutation) RemovedIDs(name string) []ent.Value {
+func (m *TokenMutation) RemovedIDs(name string) []ent.Value {
 	switch name {
-	case target.EdgePackages:
-		ids := make([]ent.Value, 0, len(m.removedpackages))
-		for id := range m.removedpackages {
+	case token.EdgeTargets:
+		ids := make([]ent.Value, 0, len(m.removedtargets))
+		for id := range m.removedtargets {
 			ids = append(ids, id)
 		}
 		return ids
@@ -1074,39 +2794,39 @@ func (m *TargetMutation) RemovedIDs(name string) []ent.Value {
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *TargetMutation) ClearedEdges() []string {
+func (m *TokenMutation) ClearedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.clearedpackages {
-		edges = append(edges, target.EdgePackages)
+	if m.clearedtargets {
+		edges = append(edges, token.EdgeTargets)
 	}
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *TargetMutation) EdgeCleared(name string) bool {
+func (m *TokenMutation) EdgeCleared(name string) bool {
 	switch name {
-	case target.EdgePackages:
-		return m.clearedpackages
+	case token.EdgeTargets:
+		return m.clearedtargets
 	}
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *TargetMutation) ClearEdge(name string) error {
+func (m *TokenMutation) ClearEdge(name string) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown Target unique edge %s", name)
+	return fmt.Errorf("unknown Token unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *TargetMutation) ResetEdge(name string) error {
+func (m *TokenMutation) ResetEdge(name string) error {
 	switch name {
-	case target.EdgePackages:
-		m.ResetPackages()
+	case token.EdgeTargets:
+		m.ResetTargets()
 		return nil
 	}
-	return fmt.Errorf("unknown Target edge %s", name)
+	return fmt.Errorf("unknown Token edge %s", name)
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
@@ -28,8 +29,30 @@ type Pkg struct {
 	Name string `json:"name,omitempty"`
 	// Version holds the value of the "version" field.
 	Version string `json:"version,omitempty"`
+	// Revision of the package (e.g., r1), empty if the package has no revision
+	Revision string `json:"revision,omitempty"`
+	// BUILD_ID of the package, allowing multiple builds of the same version (binpkg-multi-instance)
+	BuildID int `json:"build_id,omitempty"`
 	// Gentoo specific fields shared between the index and metadata.tar files
 	PackageFields *parser.PackageCommon `json:"package_fields,omitempty"`
+	// Format of the package archive (BINPKG_FORMAT)
+	Format pkg.Format `json:"format,omitempty"`
+	// Path of the package archive relative to the target, used as PATH in the Packages index
+	Path string `json:"path,omitempty"`
+	// Key of the package archive in object storage
+	ObjectKey string `json:"object_key,omitempty"`
+	// Size of the package archive in bytes
+	Size int64 `json:"size,omitempty"`
+	// Sha1 holds the value of the "sha1" field.
+	Sha1 string `json:"sha1,omitempty"`
+	// Md5 holds the value of the "md5" field.
+	Md5 string `json:"md5,omitempty"`
+	// Blake2b holds the value of the "blake2b" field.
+	Blake2b string `json:"blake2b,omitempty"`
+	// Sha512 holds the value of the "sha512" field.
+	Sha512 string `json:"sha512,omitempty"`
+	// Time the package archive was uploaded
+	Mtime time.Time `json:"mtime,omitempty"`
 	// TargetID holds the value of the "target_id" field.
 	TargetID uuid.UUID `json:"target_id,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
@@ -65,8 +88,12 @@ func (*Pkg) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case pkg.FieldPackageFields:
 			values[i] = new([]byte)
-		case pkg.FieldRepository, pkg.FieldCategory, pkg.FieldName, pkg.FieldVersion:
+		case pkg.FieldBuildID, pkg.FieldSize:
+			values[i] = new(sql.NullInt64)
+		case pkg.FieldRepository, pkg.FieldCategory, pkg.FieldName, pkg.FieldVersion, pkg.FieldRevision, pkg.FieldFormat, pkg.FieldPath, pkg.FieldObjectKey, pkg.FieldSha1, pkg.FieldMd5, pkg.FieldBlake2b, pkg.FieldSha512:
 			values[i] = new(sql.NullString)
+		case pkg.FieldMtime:
+			values[i] = new(sql.NullTime)
 		case pkg.FieldID, pkg.FieldTargetID:
 			values[i] = new(uuid.UUID)
 		default:
@@ -114,6 +141,18 @@ func (pk *Pkg) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				pk.Version = value.String
 			}
+		case pkg.FieldRevision:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field revision", values[i])
+			} else if value.Valid {
+				pk.Revision = value.String
+			}
+		case pkg.FieldBuildID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field build_id", values[i])
+			} else if value.Valid {
+				pk.BuildID = int(value.Int64)
+			}
 		case pkg.FieldPackageFields:
 			if value, ok := values[i].(*[]byte); !ok {
 				return fmt.Errorf("unexpected type %T for field package_fields", values[i])
@@ -122,6 +161,60 @@ func (pk *Pkg) assignValues(columns []string, values []any) error {
 					return fmt.Errorf("unmarshal field package_fields: %w", err)
 				}
 			}
+		case pkg.FieldFormat:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field format", values[i])
+			} else if value.Valid {
+				pk.Format = pkg.Format(value.String)
+			}
+		case pkg.FieldPath:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field path", values[i])
+			} else if value.Valid {
+				pk.Path = value.String
+			}
+		case pkg.FieldObjectKey:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field object_key", values[i])
+			} else if value.Valid {
+				pk.ObjectKey = value.String
+			}
+		case pkg.FieldSize:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field size", values[i])
+			} else if value.Valid {
+				pk.Size = value.Int64
+			}
+		case pkg.FieldSha1:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field sha1", values[i])
+			} else if value.Valid {
+				pk.Sha1 = value.String
+			}
+		case pkg.FieldMd5:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field md5", values[i])
+			} else if value.Valid {
+				pk.Md5 = value.String
+			}
+		case pkg.FieldBlake2b:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field blake2b", values[i])
+			} else if value.Valid {
+				pk.Blake2b = value.String
+			}
+		case pkg.FieldSha512:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field sha512", values[i])
+			} else if value.Valid {
+				pk.Sha512 = value.String
+			}
+		case pkg.FieldMtime:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field mtime", values[i])
+			} else if value.Valid {
+				pk.Mtime = value.Time
+			}
 		case pkg.FieldTargetID:
 			if value, ok := values[i].(*uuid.UUID); !ok {
 				return fmt.Errorf("unexpected type %T for field target_id", values[i])
@@ -181,9 +274,42 @@ func (pk *Pkg) String() string {
 	builder.WriteString("version=")
 	builder.WriteString(pk.Version)
 	builder.WriteString(", ")
+	builder.WriteString("revision=")
+	builder.WriteString(pk.Revision)
+	builder.WriteString(", ")
+	builder.WriteString("build_id=")
+	builder.WriteString(fmt.Sprintf("%v", pk.BuildID))
+	builder.WriteString(", ")
 	builder.WriteString("package_fields=")
 	builder.WriteString(fmt.Sprintf("%v", pk.PackageFields))
 	builder.WriteString(", ")
+	builder.WriteString("format=")
+	builder.WriteString(fmt.Sprintf("%v", pk.Format))
+	builder.WriteString(", ")
+	builder.WriteString("path=")
+	builder.WriteString(pk.Path)
+	builder.WriteString(", ")
+	builder.WriteString("object_key=")
+	builder.WriteString(pk.ObjectKey)
+	builder.WriteString(", ")
+	builder.WriteString("size=")
+	builder.WriteString(fmt.Sprintf("%v", pk.Size))
+	builder.WriteString(", ")
+	builder.WriteString("sha1=")
+	builder.WriteString(pk.Sha1)
+	builder.WriteString(", ")
+	builder.WriteString("md5=")
+	builder.WriteString(pk.Md5)
+	builder.WriteString(", ")
+	builder.WriteString("blake2b=")
+	builder.WriteString(pk.Blake2b)
+	builder.WriteString(", ")
+	builder.WriteString("sha512=")
+	builder.WriteString(pk.Sha512)
+	builder.WriteString(", ")
+	builder.WriteString("mtime=")
+	builder.WriteString(pk.Mtime.Format(time.ANSIC))
+	builder.WriteString(", ")
 	builder.WriteString("target_id=")
 	builder.WriteString(fmt.Sprintf("%v", pk.TargetID))
 	builder.WriteByte(')')
@@ -3,6 +3,9 @@
 package pkg
 
 import (
+	"fmt"
+	"time"
+
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/google/uuid"
@@ -21,8 +24,30 @@ const (
 	FieldName = "name"
 	// FieldVersion holds the string denoting the version field in the database.
 	FieldVersion = "version"
+	// FieldRevision holds the string denoting the revision field in the database.
+	FieldRevision = "revision"
+	// FieldBuildID holds the string denoting the build_id field in the database.
+	FieldBuildID = "build_id"
 	// FieldPackageFields holds the string denoting the package_fields field in the database.
 	FieldPackageFields = "package_fields"
+	// FieldFormat holds the string denoting the format field in the database.
+	FieldFormat = "format"
+	// FieldPath holds the string denoting the path field in the database.
+	FieldPath = "path"
+	// FieldObjectKey holds the string denoting the object_key field in the database.
+	FieldObjectKey = "object_key"
+	// FieldSize holds the string denoting the size field in the database.
+	FieldSize = "size"
+	// FieldSha1 holds the string denoting the sha1 field in the database.
+	FieldSha1 = "sha1"
+	// FieldMd5 holds the string denoting the md5 field in the database.
+	FieldMd5 = "md5"
+	// FieldBlake2b holds the string denoting the blake2b field in the database.
+	FieldBlake2b = "blake2b"
+	// FieldSha512 holds the string denoting the sha512 field in the database.
+	FieldSha512 = "sha512"
+	// FieldMtime holds the string denoting the mtime field in the database.
+	FieldMtime = "mtime"
 	// FieldTargetID holds the string denoting the target_id field in the database.
 	FieldTargetID = "target_id"
 	// EdgeTarget holds the string denoting the target edge name in mutations.
@@ -45,7 +70,18 @@ var Columns = []string{
 	FieldCategory,
 	FieldName,
 	FieldVersion,
+	FieldRevision,
+	FieldBuildID,
 	FieldPackageFields,
+	FieldFormat,
+	FieldPath,
+	FieldObjectKey,
+	FieldSize,
+	FieldSha1,
+	FieldMd5,
+	FieldBlake2b,
+	FieldSha512,
+	FieldMtime,
 	FieldTargetID,
 }
 
@@ -60,10 +96,52 @@ func ValidColumn(column string) bool {
 }
 
 var (
+	// DefaultRevision holds the default value on creation for the "revision" field.
+	DefaultRevision string
+	// DefaultBuildID holds the default value on creation for the "build_id" field.
+	DefaultBuildID int
+	// DefaultSize holds the default value on creation for the "size" field.
+	DefaultSize int64
+	// DefaultSha1 holds the default value on creation for the "sha1" field.
+	DefaultSha1 string
+	// DefaultMd5 holds the default value on creation for the "md5" field.
+	DefaultMd5 string
+	// DefaultBlake2b holds the default value on creation for the "blake2b" field.
+	DefaultBlake2b string
+	// DefaultSha512 holds the default value on creation for the "sha512" field.
+	DefaultSha512 string
+	// DefaultMtime holds the default value on creation for the "mtime" field.
+	DefaultMtime func() time.Time
 	// DefaultID holds the default value on creation for the "id" field.
 	DefaultID func() uuid.UUID
 )
 
+// Format defines the type for the "format" enum field.
+type Format string
+
+// FormatGpkg is the default value of the Format enum.
+const DefaultFormat = FormatGpkg
+
+// Format values.
+const (
+	FormatGpkg Format = "gpkg"
+	FormatXpak Format = "xpak"
+)
+
+func (f Format) String() string {
+	return string(f)
+}
+
+// FormatValidator is a validator for the "format" field enum values. It is called by the builders before save.
+func FormatValidator(f Format) error {
+	switch f {
+	case FormatGpkg, FormatXpak:
+		return nil
+	default:
+		return fmt.Errorf("pkg: invalid enum value for format field: %q", f)
+	}
+}
+
 // OrderOption defines the ordering options for the Pkg queries.
 type OrderOption func(*sql.Selector)
 
@@ -92,6 +170,61 @@ func ByVersion(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldVersion, opts...).ToFunc()
 }
 
+// ByRevision orders the results by the revision field.
+func ByRevision(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRevision, opts...).ToFunc()
+}
+
+// ByBuildID orders the results by the build_id field.
+func ByBuildID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBuildID, opts...).ToFunc()
+}
+
+// ByFormat orders the results by the format field.
+func ByFormat(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldFormat, opts...).ToFunc()
+}
+
+// ByPath orders the results by the path field.
+func ByPath(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPath, opts...).ToFunc()
+}
+
+// ByObjectKey orders the results by the object_key field.
+func ByObjectKey(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldObjectKey, opts...).ToFunc()
+}
+
+// BySize orders the results by the size field.
+func BySize(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSize, opts...).ToFunc()
+}
+
+// BySha1 orders the results by the sha1 field.
+func BySha1(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSha1, opts...).ToFunc()
+}
+
+// ByMd5 orders the results by the md5 field.
+func ByMd5(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMd5, opts...).ToFunc()
+}
+
+// ByBlake2b orders the results by the blake2b field.
+func ByBlake2b(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBlake2b, opts...).ToFunc()
+}
+
+// BySha512 orders the results by the sha512 field.
+func BySha512(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSha512, opts...).ToFunc()
+}
+
+// ByMtime orders the results by the mtime field.
+func ByMtime(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMtime, opts...).ToFunc()
+}
+
 // ByTargetID orders the results by the target_id field.
 func ByTargetID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldTargetID, opts...).ToFunc()
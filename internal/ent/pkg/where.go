@@ -3,6 +3,8 @@
 package pkg
 
 import (
+	"time"
+
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/google/uuid"
@@ -74,6 +76,56 @@ func Version(v string) predicate.Pkg {
 	return predicate.Pkg(sql.FieldEQ(FieldVersion, v))
 }
 
+// Revision applies equality check predicate on the "revision" field. It's identical to RevisionEQ.
+func Revision(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldRevision, v))
+}
+
+// BuildID applies equality check predicate on the "build_id" field. It's identical to BuildIDEQ.
+func BuildID(v int) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldBuildID, v))
+}
+
+// Path applies equality check predicate on the "path" field. It's identical to PathEQ.
+func Path(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldPath, v))
+}
+
+// ObjectKey applies equality check predicate on the "object_key" field. It's identical to ObjectKeyEQ.
+func ObjectKey(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldObjectKey, v))
+}
+
+// Size applies equality check predicate on the "size" field. It's identical to SizeEQ.
+func Size(v int64) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldSize, v))
+}
+
+// Sha1 applies equality check predicate on the "sha1" field. It's identical to Sha1EQ.
+func Sha1(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldSha1, v))
+}
+
+// Md5 applies equality check predicate on the "md5" field. It's identical to Md5EQ.
+func Md5(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldMd5, v))
+}
+
+// Blake2b applies equality check predicate on the "blake2b" field. It's identical to Blake2bEQ.
+func Blake2b(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldBlake2b, v))
+}
+
+// Sha512 applies equality check predicate on the "sha512" field. It's identical to Sha512EQ.
+func Sha512(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldSha512, v))
+}
+
+// Mtime applies equality check predicate on the "mtime" field. It's identical to MtimeEQ.
+func Mtime(v time.Time) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldMtime, v))
+}
+
 // TargetID applies equality check predicate on the "target_id" field. It's identical to TargetIDEQ.
 func TargetID(v uuid.UUID) predicate.Pkg {
 	return predicate.Pkg(sql.FieldEQ(FieldTargetID, v))
@@ -339,6 +391,601 @@ func VersionContainsFold(v string) predicate.Pkg {
 	return predicate.Pkg(sql.FieldContainsFold(FieldVersion, v))
 }
 
+// RevisionEQ applies the EQ predicate on the "revision" field.
+func RevisionEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldRevision, v))
+}
+
+// RevisionNEQ applies the NEQ predicate on the "revision" field.
+func RevisionNEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldRevision, v))
+}
+
+// RevisionIn applies the In predicate on the "revision" field.
+func RevisionIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldRevision, vs...))
+}
+
+// RevisionNotIn applies the NotIn predicate on the "revision" field.
+func RevisionNotIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldRevision, vs...))
+}
+
+// RevisionGT applies the GT predicate on the "revision" field.
+func RevisionGT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldRevision, v))
+}
+
+// RevisionGTE applies the GTE predicate on the "revision" field.
+func RevisionGTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldRevision, v))
+}
+
+// RevisionLT applies the LT predicate on the "revision" field.
+func RevisionLT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldRevision, v))
+}
+
+// RevisionLTE applies the LTE predicate on the "revision" field.
+func RevisionLTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldRevision, v))
+}
+
+// RevisionContains applies the Contains predicate on the "revision" field.
+func RevisionContains(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContains(FieldRevision, v))
+}
+
+// RevisionHasPrefix applies the HasPrefix predicate on the "revision" field.
+func RevisionHasPrefix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasPrefix(FieldRevision, v))
+}
+
+// RevisionHasSuffix applies the HasSuffix predicate on the "revision" field.
+func RevisionHasSuffix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasSuffix(FieldRevision, v))
+}
+
+// RevisionEqualFold applies the EqualFold predicate on the "revision" field.
+func RevisionEqualFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEqualFold(FieldRevision, v))
+}
+
+// RevisionContainsFold applies the ContainsFold predicate on the "revision" field.
+func RevisionContainsFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContainsFold(FieldRevision, v))
+}
+
+// BuildIDEQ applies the EQ predicate on the "build_id" field.
+func BuildIDEQ(v int) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldBuildID, v))
+}
+
+// BuildIDNEQ applies the NEQ predicate on the "build_id" field.
+func BuildIDNEQ(v int) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldBuildID, v))
+}
+
+// BuildIDIn applies the In predicate on the "build_id" field.
+func BuildIDIn(vs ...int) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldBuildID, vs...))
+}
+
+// BuildIDNotIn applies the NotIn predicate on the "build_id" field.
+func BuildIDNotIn(vs ...int) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldBuildID, vs...))
+}
+
+// BuildIDGT applies the GT predicate on the "build_id" field.
+func BuildIDGT(v int) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldBuildID, v))
+}
+
+// BuildIDGTE applies the GTE predicate on the "build_id" field.
+func BuildIDGTE(v int) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldBuildID, v))
+}
+
+// BuildIDLT applies the LT predicate on the "build_id" field.
+func BuildIDLT(v int) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldBuildID, v))
+}
+
+// BuildIDLTE applies the LTE predicate on the "build_id" field.
+func BuildIDLTE(v int) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldBuildID, v))
+}
+
+// FormatEQ applies the EQ predicate on the "format" field.
+func FormatEQ(v Format) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldFormat, v))
+}
+
+// FormatNEQ applies the NEQ predicate on the "format" field.
+func FormatNEQ(v Format) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldFormat, v))
+}
+
+// FormatIn applies the In predicate on the "format" field.
+func FormatIn(vs ...Format) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldFormat, vs...))
+}
+
+// FormatNotIn applies the NotIn predicate on the "format" field.
+func FormatNotIn(vs ...Format) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldFormat, vs...))
+}
+
+// PathEQ applies the EQ predicate on the "path" field.
+func PathEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldPath, v))
+}
+
+// PathNEQ applies the NEQ predicate on the "path" field.
+func PathNEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldPath, v))
+}
+
+// PathIn applies the In predicate on the "path" field.
+func PathIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldPath, vs...))
+}
+
+// PathNotIn applies the NotIn predicate on the "path" field.
+func PathNotIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldPath, vs...))
+}
+
+// PathGT applies the GT predicate on the "path" field.
+func PathGT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldPath, v))
+}
+
+// PathGTE applies the GTE predicate on the "path" field.
+func PathGTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldPath, v))
+}
+
+// PathLT applies the LT predicate on the "path" field.
+func PathLT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldPath, v))
+}
+
+// PathLTE applies the LTE predicate on the "path" field.
+func PathLTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldPath, v))
+}
+
+// PathContains applies the Contains predicate on the "path" field.
+func PathContains(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContains(FieldPath, v))
+}
+
+// PathHasPrefix applies the HasPrefix predicate on the "path" field.
+func PathHasPrefix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasPrefix(FieldPath, v))
+}
+
+// PathHasSuffix applies the HasSuffix predicate on the "path" field.
+func PathHasSuffix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasSuffix(FieldPath, v))
+}
+
+// PathEqualFold applies the EqualFold predicate on the "path" field.
+func PathEqualFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEqualFold(FieldPath, v))
+}
+
+// PathContainsFold applies the ContainsFold predicate on the "path" field.
+func PathContainsFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContainsFold(FieldPath, v))
+}
+
+// ObjectKeyEQ applies the EQ predicate on the "object_key" field.
+func ObjectKeyEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldObjectKey, v))
+}
+
+// ObjectKeyNEQ applies the NEQ predicate on the "object_key" field.
+func ObjectKeyNEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldObjectKey, v))
+}
+
+// ObjectKeyIn applies the In predicate on the "object_key" field.
+func ObjectKeyIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldObjectKey, vs...))
+}
+
+// ObjectKeyNotIn applies the NotIn predicate on the "object_key" field.
+func ObjectKeyNotIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldObjectKey, vs...))
+}
+
+// ObjectKeyGT applies the GT predicate on the "object_key" field.
+func ObjectKeyGT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldObjectKey, v))
+}
+
+// ObjectKeyGTE applies the GTE predicate on the "object_key" field.
+func ObjectKeyGTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldObjectKey, v))
+}
+
+// ObjectKeyLT applies the LT predicate on the "object_key" field.
+func ObjectKeyLT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldObjectKey, v))
+}
+
+// ObjectKeyLTE applies the LTE predicate on the "object_key" field.
+func ObjectKeyLTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldObjectKey, v))
+}
+
+// ObjectKeyContains applies the Contains predicate on the "object_key" field.
+func ObjectKeyContains(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContains(FieldObjectKey, v))
+}
+
+// ObjectKeyHasPrefix applies the HasPrefix predicate on the "object_key" field.
+func ObjectKeyHasPrefix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasPrefix(FieldObjectKey, v))
+}
+
+// ObjectKeyHasSuffix applies the HasSuffix predicate on the "object_key" field.
+func ObjectKeyHasSuffix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasSuffix(FieldObjectKey, v))
+}
+
+// ObjectKeyEqualFold applies the EqualFold predicate on the "object_key" field.
+func ObjectKeyEqualFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEqualFold(FieldObjectKey, v))
+}
+
+// ObjectKeyContainsFold applies the ContainsFold predicate on the "object_key" field.
+func ObjectKeyContainsFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContainsFold(FieldObjectKey, v))
+}
+
+// SizeEQ applies the EQ predicate on the "size" field.
+func SizeEQ(v int64) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldSize, v))
+}
+
+// SizeNEQ applies the NEQ predicate on the "size" field.
+func SizeNEQ(v int64) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldSize, v))
+}
+
+// SizeIn applies the In predicate on the "size" field.
+func SizeIn(vs ...int64) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldSize, vs...))
+}
+
+// SizeNotIn applies the NotIn predicate on the "size" field.
+func SizeNotIn(vs ...int64) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldSize, vs...))
+}
+
+// SizeGT applies the GT predicate on the "size" field.
+func SizeGT(v int64) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldSize, v))
+}
+
+// SizeGTE applies the GTE predicate on the "size" field.
+func SizeGTE(v int64) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldSize, v))
+}
+
+// SizeLT applies the LT predicate on the "size" field.
+func SizeLT(v int64) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldSize, v))
+}
+
+// SizeLTE applies the LTE predicate on the "size" field.
+func SizeLTE(v int64) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldSize, v))
+}
+
+// Sha1EQ applies the EQ predicate on the "sha1" field.
+func Sha1EQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldSha1, v))
+}
+
+// Sha1NEQ applies the NEQ predicate on the "sha1" field.
+func Sha1NEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldSha1, v))
+}
+
+// Sha1In applies the In predicate on the "sha1" field.
+func Sha1In(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldSha1, vs...))
+}
+
+// Sha1NotIn applies the NotIn predicate on the "sha1" field.
+func Sha1NotIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldSha1, vs...))
+}
+
+// Sha1GT applies the GT predicate on the "sha1" field.
+func Sha1GT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldSha1, v))
+}
+
+// Sha1GTE applies the GTE predicate on the "sha1" field.
+func Sha1GTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldSha1, v))
+}
+
+// Sha1LT applies the LT predicate on the "sha1" field.
+func Sha1LT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldSha1, v))
+}
+
+// Sha1LTE applies the LTE predicate on the "sha1" field.
+func Sha1LTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldSha1, v))
+}
+
+// Sha1Contains applies the Contains predicate on the "sha1" field.
+func Sha1Contains(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContains(FieldSha1, v))
+}
+
+// Sha1HasPrefix applies the HasPrefix predicate on the "sha1" field.
+func Sha1HasPrefix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasPrefix(FieldSha1, v))
+}
+
+// Sha1HasSuffix applies the HasSuffix predicate on the "sha1" field.
+func Sha1HasSuffix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasSuffix(FieldSha1, v))
+}
+
+// Sha1EqualFold applies the EqualFold predicate on the "sha1" field.
+func Sha1EqualFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEqualFold(FieldSha1, v))
+}
+
+// Sha1ContainsFold applies the ContainsFold predicate on the "sha1" field.
+func Sha1ContainsFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContainsFold(FieldSha1, v))
+}
+
+// Md5EQ applies the EQ predicate on the "md5" field.
+func Md5EQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldMd5, v))
+}
+
+// Md5NEQ applies the NEQ predicate on the "md5" field.
+func Md5NEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldMd5, v))
+}
+
+// Md5In applies the In predicate on the "md5" field.
+func Md5In(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldMd5, vs...))
+}
+
+// Md5NotIn applies the NotIn predicate on the "md5" field.
+func Md5NotIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldMd5, vs...))
+}
+
+// Md5GT applies the GT predicate on the "md5" field.
+func Md5GT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldMd5, v))
+}
+
+// Md5GTE applies the GTE predicate on the "md5" field.
+func Md5GTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldMd5, v))
+}
+
+// Md5LT applies the LT predicate on the "md5" field.
+func Md5LT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldMd5, v))
+}
+
+// Md5LTE applies the LTE predicate on the "md5" field.
+func Md5LTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldMd5, v))
+}
+
+// Md5Contains applies the Contains predicate on the "md5" field.
+func Md5Contains(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContains(FieldMd5, v))
+}
+
+// Md5HasPrefix applies the HasPrefix predicate on the "md5" field.
+func Md5HasPrefix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasPrefix(FieldMd5, v))
+}
+
+// Md5HasSuffix applies the HasSuffix predicate on the "md5" field.
+func Md5HasSuffix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasSuffix(FieldMd5, v))
+}
+
+// Md5EqualFold applies the EqualFold predicate on the "md5" field.
+func Md5EqualFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEqualFold(FieldMd5, v))
+}
+
+// Md5ContainsFold applies the ContainsFold predicate on the "md5" field.
+func Md5ContainsFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContainsFold(FieldMd5, v))
+}
+
+// Blake2bEQ applies the EQ predicate on the "blake2b" field.
+func Blake2bEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldBlake2b, v))
+}
+
+// Blake2bNEQ applies the NEQ predicate on the "blake2b" field.
+func Blake2bNEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldBlake2b, v))
+}
+
+// Blake2bIn applies the In predicate on the "blake2b" field.
+func Blake2bIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldBlake2b, vs...))
+}
+
+// Blake2bNotIn applies the NotIn predicate on the "blake2b" field.
+func Blake2bNotIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldBlake2b, vs...))
+}
+
+// Blake2bGT applies the GT predicate on the "blake2b" field.
+func Blake2bGT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldBlake2b, v))
+}
+
+// Blake2bGTE applies the GTE predicate on the "blake2b" field.
+func Blake2bGTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldBlake2b, v))
+}
+
+// Blake2bLT applies the LT predicate on the "blake2b" field.
+func Blake2bLT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldBlake2b, v))
+}
+
+// Blake2bLTE applies the LTE predicate on the "blake2b" field.
+func Blake2bLTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldBlake2b, v))
+}
+
+// Blake2bContains applies the Contains predicate on the "blake2b" field.
+func Blake2bContains(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContains(FieldBlake2b, v))
+}
+
+// Blake2bHasPrefix applies the HasPrefix predicate on the "blake2b" field.
+func Blake2bHasPrefix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasPrefix(FieldBlake2b, v))
+}
+
+// Blake2bHasSuffix applies the HasSuffix predicate on the "blake2b" field.
+func Blake2bHasSuffix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasSuffix(FieldBlake2b, v))
+}
+
+// Blake2bEqualFold applies the EqualFold predicate on the "blake2b" field.
+func Blake2bEqualFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEqualFold(FieldBlake2b, v))
+}
+
+// Blake2bContainsFold applies the ContainsFold predicate on the "blake2b" field.
+func Blake2bContainsFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContainsFold(FieldBlake2b, v))
+}
+
+// Sha512EQ applies the EQ predicate on the "sha512" field.
+func Sha512EQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldSha512, v))
+}
+
+// Sha512NEQ applies the NEQ predicate on the "sha512" field.
+func Sha512NEQ(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldSha512, v))
+}
+
+// Sha512In applies the In predicate on the "sha512" field.
+func Sha512In(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldSha512, vs...))
+}
+
+// Sha512NotIn applies the NotIn predicate on the "sha512" field.
+func Sha512NotIn(vs ...string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldSha512, vs...))
+}
+
+// Sha512GT applies the GT predicate on the "sha512" field.
+func Sha512GT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldSha512, v))
+}
+
+// Sha512GTE applies the GTE predicate on the "sha512" field.
+func Sha512GTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldSha512, v))
+}
+
+// Sha512LT applies the LT predicate on the "sha512" field.
+func Sha512LT(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldSha512, v))
+}
+
+// Sha512LTE applies the LTE predicate on the "sha512" field.
+func Sha512LTE(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldSha512, v))
+}
+
+// Sha512Contains applies the Contains predicate on the "sha512" field.
+func Sha512Contains(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContains(FieldSha512, v))
+}
+
+// Sha512HasPrefix applies the HasPrefix predicate on the "sha512" field.
+func Sha512HasPrefix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasPrefix(FieldSha512, v))
+}
+
+// Sha512HasSuffix applies the HasSuffix predicate on the "sha512" field.
+func Sha512HasSuffix(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldHasSuffix(FieldSha512, v))
+}
+
+// Sha512EqualFold applies the EqualFold predicate on the "sha512" field.
+func Sha512EqualFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEqualFold(FieldSha512, v))
+}
+
+// Sha512ContainsFold applies the ContainsFold predicate on the "sha512" field.
+func Sha512ContainsFold(v string) predicate.Pkg {
+	return predicate.Pkg(sql.FieldContainsFold(FieldSha512, v))
+}
+
+// MtimeEQ applies the EQ predicate on the "mtime" field.
+func MtimeEQ(v time.Time) predicate.Pkg {
+	return predicate.Pkg(sql.FieldEQ(FieldMtime, v))
+}
+
+// MtimeNEQ applies the NEQ predicate on the "mtime" field.
+func MtimeNEQ(v time.Time) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNEQ(FieldMtime, v))
+}
+
+// MtimeIn applies the In predicate on the "mtime" field.
+func MtimeIn(vs ...time.Time) predicate.Pkg {
+	return predicate.Pkg(sql.FieldIn(FieldMtime, vs...))
+}
+
+// MtimeNotIn applies the NotIn predicate on the "mtime" field.
+func MtimeNotIn(vs ...time.Time) predicate.Pkg {
+	return predicate.Pkg(sql.FieldNotIn(FieldMtime, vs...))
+}
+
+// MtimeGT applies the GT predicate on the "mtime" field.
+func MtimeGT(v time.Time) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGT(FieldMtime, v))
+}
+
+// MtimeGTE applies the GTE predicate on the "mtime" field.
+func MtimeGTE(v time.Time) predicate.Pkg {
+	return predicate.Pkg(sql.FieldGTE(FieldMtime, v))
+}
+
+// MtimeLT applies the LT predicate on the "mtime" field.
+func MtimeLT(v time.Time) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLT(FieldMtime, v))
+}
+
+// MtimeLTE applies the LTE predicate on the "mtime" field.
+func MtimeLTE(v time.Time) predicate.Pkg {
+	return predicate.Pkg(sql.FieldLTE(FieldMtime, v))
+}
+
 // TargetIDEQ applies the EQ predicate on the "target_id" field.
 func TargetIDEQ(v uuid.UUID) predicate.Pkg {
 	return predicate.Pkg(sql.FieldEQ(FieldTargetID, v))
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
@@ -46,12 +47,150 @@ func (pc *PkgCreate) SetVersion(s string) *PkgCreate {
 	return pc
 }
 
+// SetRevision sets the "revision" field.
+func (pc *PkgCreate) SetRevision(s string) *PkgCreate {
+	pc.mutation.SetRevision(s)
+	return pc
+}
+
+// SetNillableRevision sets the "revision" field if the given value is not nil.
+func (pc *PkgCreate) SetNillableRevision(s *string) *PkgCreate {
+	if s != nil {
+		pc.SetRevision(*s)
+	}
+	return pc
+}
+
+// SetBuildID sets the "build_id" field.
+func (pc *PkgCreate) SetBuildID(i int) *PkgCreate {
+	pc.mutation.SetBuildID(i)
+	return pc
+}
+
+// SetNillableBuildID sets the "build_id" field if the given value is not nil.
+func (pc *PkgCreate) SetNillableBuildID(i *int) *PkgCreate {
+	if i != nil {
+		pc.SetBuildID(*i)
+	}
+	return pc
+}
+
 // SetPackageFields sets the "package_fields" field.
 func (pc *PkgCreate) SetPackageFields(value *parser.PackageCommon) *PkgCreate {
 	pc.mutation.SetPackageFields(value)
 	return pc
 }
 
+// SetFormat sets the "format" field.
+func (pc *PkgCreate) SetFormat(pk pkg.Format) *PkgCreate {
+	pc.mutation.SetFormat(pk)
+	return pc
+}
+
+// SetNillableFormat sets the "format" field if the given value is not nil.
+func (pc *PkgCreate) SetNillableFormat(pk *pkg.Format) *PkgCreate {
+	if pk != nil {
+		pc.SetFormat(*pk)
+	}
+	return pc
+}
+
+// SetPath sets the "path" field.
+func (pc *PkgCreate) SetPath(s string) *PkgCreate {
+	pc.mutation.SetPath(s)
+	return pc
+}
+
+// SetObjectKey sets the "object_key" field.
+func (pc *PkgCreate) SetObjectKey(s string) *PkgCreate {
+	pc.mutation.SetObjectKey(s)
+	return pc
+}
+
+// SetSize sets the "size" field.
+func (pc *PkgCreate) SetSize(i int64) *PkgCreate {
+	pc.mutation.SetSize(i)
+	return pc
+}
+
+// SetNillableSize sets the "size" field if the given value is not nil.
+func (pc *PkgCreate) SetNillableSize(i *int64) *PkgCreate {
+	if i != nil {
+		pc.SetSize(*i)
+	}
+	return pc
+}
+
+// SetSha1 sets the "sha1" field.
+func (pc *PkgCreate) SetSha1(s string) *PkgCreate {
+	pc.mutation.SetSha1(s)
+	return pc
+}
+
+// SetNillableSha1 sets the "sha1" field if the given value is not nil.
+func (pc *PkgCreate) SetNillableSha1(s *string) *PkgCreate {
+	if s != nil {
+		pc.SetSha1(*s)
+	}
+	return pc
+}
+
+// SetMd5 sets the "md5" field.
+func (pc *PkgCreate) SetMd5(s string) *PkgCreate {
+	pc.mutation.SetMd5(s)
+	return pc
+}
+
+// SetNillableMd5 sets the "md5" field if the given value is not nil.
+func (pc *PkgCreate) SetNillableMd5(s *string) *PkgCreate {
+	if s != nil {
+		pc.SetMd5(*s)
+	}
+	return pc
+}
+
+// SetBlake2b sets the "blake2b" field.
+func (pc *PkgCreate) SetBlake2b(s string) *PkgCreate {
+	pc.mutation.SetBlake2b(s)
+	return pc
+}
+
+// SetNillableBlake2b sets the "blake2b" field if the given value is not nil.
+func (pc *PkgCreate) SetNillableBlake2b(s *string) *PkgCreate {
+	if s != nil {
+		pc.SetBlake2b(*s)
+	}
+	return pc
+}
+
+// SetSha512 sets the "sha512" field.
+func (pc *PkgCreate) SetSha512(s string) *PkgCreate {
+	pc.mutation.SetSha512(s)
+	return pc
+}
+
+// SetNillableSha512 sets the "sha512" field if the given value is not nil.
+func (pc *PkgCreate) SetNillableSha512(s *string) *PkgCreate {
+	if s != nil {
+		pc.SetSha512(*s)
+	}
+	return pc
+}
+
+// SetMtime sets the "mtime" field.
+func (pc *PkgCreate) SetMtime(t time.Time) *PkgCreate {
+	pc.mutation.SetMtime(t)
+	return pc
+}
+
+// SetNillableMtime sets the "mtime" field if the given value is not nil.
+func (pc *PkgCreate) SetNillableMtime(t *time.Time) *PkgCreate {
+	if t != nil {
+		pc.SetMtime(*t)
+	}
+	return pc
+}
+
 // SetTargetID sets the "target_id" field.
 func (pc *PkgCreate) SetTargetID(u uuid.UUID) *PkgCreate {
 	pc.mutation.SetTargetID(u)
@@ -112,6 +251,42 @@ func (pc *PkgCreate) ExecX(ctx context.Context) {
 
 // defaults sets the default values of the builder before save.
 func (pc *PkgCreate) defaults() {
+	if _, ok := pc.mutation.Revision(); !ok {
+		v := pkg.DefaultRevision
+		pc.mutation.SetRevision(v)
+	}
+	if _, ok := pc.mutation.BuildID(); !ok {
+		v := pkg.DefaultBuildID
+		pc.mutation.SetBuildID(v)
+	}
+	if _, ok := pc.mutation.Format(); !ok {
+		v := pkg.DefaultFormat
+		pc.mutation.SetFormat(v)
+	}
+	if _, ok := pc.mutation.Size(); !ok {
+		v := pkg.DefaultSize
+		pc.mutation.SetSize(v)
+	}
+	if _, ok := pc.mutation.Sha1(); !ok {
+		v := pkg.DefaultSha1
+		pc.mutation.SetSha1(v)
+	}
+	if _, ok := pc.mutation.Md5(); !ok {
+		v := pkg.DefaultMd5
+		pc.mutation.SetMd5(v)
+	}
+	if _, ok := pc.mutation.Blake2b(); !ok {
+		v := pkg.DefaultBlake2b
+		pc.mutation.SetBlake2b(v)
+	}
+	if _, ok := pc.mutation.Sha512(); !ok {
+		v := pkg.DefaultSha512
+		pc.mutation.SetSha512(v)
+	}
+	if _, ok := pc.mutation.Mtime(); !ok {
+		v := pkg.DefaultMtime()
+		pc.mutation.SetMtime(v)
+	}
 	if _, ok := pc.mutation.ID(); !ok {
 		v := pkg.DefaultID()
 		pc.mutation.SetID(v)
@@ -132,9 +307,47 @@ func (pc *PkgCreate) check() error {
 	if _, ok := pc.mutation.Version(); !ok {
 		return &ValidationError{Name: "version", err: errors.New(`ent: missing required field "Pkg.version"`)}
 	}
+	if _, ok := pc.mutation.Revision(); !ok {
+		return &ValidationError{Name: "revision", err: errors.New(`ent: missing required field "Pkg.revision"`)}
+	}
+	if _, ok := pc.mutation.BuildID(); !ok {
+		return &ValidationError{Name: "build_id", err: errors.New(`ent: missing required field "Pkg.build_id"`)}
+	}
 	if _, ok := pc.mutation.PackageFields(); !ok {
 		return &ValidationError{Name: "package_fields", err: errors.New(`ent: missing required field "Pkg.package_fields"`)}
 	}
+	if _, ok := pc.mutation.Format(); !ok {
+		return &ValidationError{Name: "format", err: errors.New(`ent: missing required field "Pkg.format"`)}
+	}
+	if v, ok := pc.mutation.Format(); ok {
+		if err := pkg.FormatValidator(v); err != nil {
+			return &ValidationError{Name: "format", err: fmt.Errorf(`ent: validator failed for field "Pkg.format": %w`, err)}
+		}
+	}
+	if _, ok := pc.mutation.Path(); !ok {
+		return &ValidationError{Name: "path", err: errors.New(`ent: missing required field "Pkg.path"`)}
+	}
+	if _, ok := pc.mutation.ObjectKey(); !ok {
+		return &ValidationError{Name: "object_key", err: errors.New(`ent: missing required field "Pkg.object_key"`)}
+	}
+	if _, ok := pc.mutation.Size(); !ok {
+		return &ValidationError{Name: "size", err: errors.New(`ent: missing required field "Pkg.size"`)}
+	}
+	if _, ok := pc.mutation.Sha1(); !ok {
+		return &ValidationError{Name: "sha1", err: errors.New(`ent: missing required field "Pkg.sha1"`)}
+	}
+	if _, ok := pc.mutation.Md5(); !ok {
+		return &ValidationError{Name: "md5", err: errors.New(`ent: missing required field "Pkg.md5"`)}
+	}
+	if _, ok := pc.mutation.Blake2b(); !ok {
+		return &ValidationError{Name: "blake2b", err: errors.New(`ent: missing required field "Pkg.blake2b"`)}
+	}
+	if _, ok := pc.mutation.Sha512(); !ok {
+		return &ValidationError{Name: "sha512", err: errors.New(`ent: missing required field "Pkg.sha512"`)}
+	}
+	if _, ok := pc.mutation.Mtime(); !ok {
+		return &ValidationError{Name: "mtime", err: errors.New(`ent: missing required field "Pkg.mtime"`)}
+	}
 	if _, ok := pc.mutation.TargetID(); !ok {
 		return &ValidationError{Name: "target_id", err: errors.New(`ent: missing required field "Pkg.target_id"`)}
 	}
@@ -192,10 +405,54 @@ func (pc *PkgCreate) createSpec() (*Pkg, *sqlgraph.CreateSpec) {
 		_spec.SetField(pkg.FieldVersion, field.TypeString, value)
 		_node.Version = value
 	}
+	if value, ok := pc.mutation.Revision(); ok {
+		_spec.SetField(pkg.FieldRevision, field.TypeString, value)
+		_node.Revision = value
+	}
+	if value, ok := pc.mutation.BuildID(); ok {
+		_spec.SetField(pkg.FieldBuildID, field.TypeInt, value)
+		_node.BuildID = value
+	}
 	if value, ok := pc.mutation.PackageFields(); ok {
 		_spec.SetField(pkg.FieldPackageFields, field.TypeJSON, value)
 		_node.PackageFields = value
 	}
+	if value, ok := pc.mutation.Format(); ok {
+		_spec.SetField(pkg.FieldFormat, field.TypeEnum, value)
+		_node.Format = value
+	}
+	if value, ok := pc.mutation.Path(); ok {
+		_spec.SetField(pkg.FieldPath, field.TypeString, value)
+		_node.Path = value
+	}
+	if value, ok := pc.mutation.ObjectKey(); ok {
+		_spec.SetField(pkg.FieldObjectKey, field.TypeString, value)
+		_node.ObjectKey = value
+	}
+	if value, ok := pc.mutation.Size(); ok {
+		_spec.SetField(pkg.FieldSize, field.TypeInt64, value)
+		_node.Size = value
+	}
+	if value, ok := pc.mutation.Sha1(); ok {
+		_spec.SetField(pkg.FieldSha1, field.TypeString, value)
+		_node.Sha1 = value
+	}
+	if value, ok := pc.mutation.Md5(); ok {
+		_spec.SetField(pkg.FieldMd5, field.TypeString, value)
+		_node.Md5 = value
+	}
+	if value, ok := pc.mutation.Blake2b(); ok {
+		_spec.SetField(pkg.FieldBlake2b, field.TypeString, value)
+		_node.Blake2b = value
+	}
+	if value, ok := pc.mutation.Sha512(); ok {
+		_spec.SetField(pkg.FieldSha512, field.TypeString, value)
+		_node.Sha512 = value
+	}
+	if value, ok := pc.mutation.Mtime(); ok {
+		_spec.SetField(pkg.FieldMtime, field.TypeTime, value)
+		_node.Mtime = value
+	}
 	if nodes := pc.mutation.TargetIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
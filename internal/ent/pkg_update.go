@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
@@ -86,12 +87,180 @@ func (pu *PkgUpdate) SetNillableVersion(s *string) *PkgUpdate {
 	return pu
 }
 
+// SetRevision sets the "revision" field.
+func (pu *PkgUpdate) SetRevision(s string) *PkgUpdate {
+	pu.mutation.SetRevision(s)
+	return pu
+}
+
+// SetNillableRevision sets the "revision" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableRevision(s *string) *PkgUpdate {
+	if s != nil {
+		pu.SetRevision(*s)
+	}
+	return pu
+}
+
+// SetBuildID sets the "build_id" field.
+func (pu *PkgUpdate) SetBuildID(i int) *PkgUpdate {
+	pu.mutation.ResetBuildID()
+	pu.mutation.SetBuildID(i)
+	return pu
+}
+
+// SetNillableBuildID sets the "build_id" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableBuildID(i *int) *PkgUpdate {
+	if i != nil {
+		pu.SetBuildID(*i)
+	}
+	return pu
+}
+
+// AddBuildID adds i to the "build_id" field.
+func (pu *PkgUpdate) AddBuildID(i int) *PkgUpdate {
+	pu.mutation.AddBuildID(i)
+	return pu
+}
+
 // SetPackageFields sets the "package_fields" field.
 func (pu *PkgUpdate) SetPackageFields(pc *parser.PackageCommon) *PkgUpdate {
 	pu.mutation.SetPackageFields(pc)
 	return pu
 }
 
+// SetFormat sets the "format" field.
+func (pu *PkgUpdate) SetFormat(pk pkg.Format) *PkgUpdate {
+	pu.mutation.SetFormat(pk)
+	return pu
+}
+
+// SetNillableFormat sets the "format" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableFormat(pk *pkg.Format) *PkgUpdate {
+	if pk != nil {
+		pu.SetFormat(*pk)
+	}
+	return pu
+}
+
+// SetPath sets the "path" field.
+func (pu *PkgUpdate) SetPath(s string) *PkgUpdate {
+	pu.mutation.SetPath(s)
+	return pu
+}
+
+// SetNillablePath sets the "path" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillablePath(s *string) *PkgUpdate {
+	if s != nil {
+		pu.SetPath(*s)
+	}
+	return pu
+}
+
+// SetObjectKey sets the "object_key" field.
+func (pu *PkgUpdate) SetObjectKey(s string) *PkgUpdate {
+	pu.mutation.SetObjectKey(s)
+	return pu
+}
+
+// SetNillableObjectKey sets the "object_key" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableObjectKey(s *string) *PkgUpdate {
+	if s != nil {
+		pu.SetObjectKey(*s)
+	}
+	return pu
+}
+
+// SetSize sets the "size" field.
+func (pu *PkgUpdate) SetSize(i int64) *PkgUpdate {
+	pu.mutation.ResetSize()
+	pu.mutation.SetSize(i)
+	return pu
+}
+
+// SetNillableSize sets the "size" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableSize(i *int64) *PkgUpdate {
+	if i != nil {
+		pu.SetSize(*i)
+	}
+	return pu
+}
+
+// AddSize adds i to the "size" field.
+func (pu *PkgUpdate) AddSize(i int64) *PkgUpdate {
+	pu.mutation.AddSize(i)
+	return pu
+}
+
+// SetSha1 sets the "sha1" field.
+func (pu *PkgUpdate) SetSha1(s string) *PkgUpdate {
+	pu.mutation.SetSha1(s)
+	return pu
+}
+
+// SetNillableSha1 sets the "sha1" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableSha1(s *string) *PkgUpdate {
+	if s != nil {
+		pu.SetSha1(*s)
+	}
+	return pu
+}
+
+// SetMd5 sets the "md5" field.
+func (pu *PkgUpdate) SetMd5(s string) *PkgUpdate {
+	pu.mutation.SetMd5(s)
+	return pu
+}
+
+// SetNillableMd5 sets the "md5" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableMd5(s *string) *PkgUpdate {
+	if s != nil {
+		pu.SetMd5(*s)
+	}
+	return pu
+}
+
+// SetBlake2b sets the "blake2b" field.
+func (pu *PkgUpdate) SetBlake2b(s string) *PkgUpdate {
+	pu.mutation.SetBlake2b(s)
+	return pu
+}
+
+// SetNillableBlake2b sets the "blake2b" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableBlake2b(s *string) *PkgUpdate {
+	if s != nil {
+		pu.SetBlake2b(*s)
+	}
+	return pu
+}
+
+// SetSha512 sets the "sha512" field.
+func (pu *PkgUpdate) SetSha512(s string) *PkgUpdate {
+	pu.mutation.SetSha512(s)
+	return pu
+}
+
+// SetNillableSha512 sets the "sha512" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableSha512(s *string) *PkgUpdate {
+	if s != nil {
+		pu.SetSha512(*s)
+	}
+	return pu
+}
+
+// SetMtime sets the "mtime" field.
+func (pu *PkgUpdate) SetMtime(t time.Time) *PkgUpdate {
+	pu.mutation.SetMtime(t)
+	return pu
+}
+
+// SetNillableMtime sets the "mtime" field if the given value is not nil.
+func (pu *PkgUpdate) SetNillableMtime(t *time.Time) *PkgUpdate {
+	if t != nil {
+		pu.SetMtime(*t)
+	}
+	return pu
+}
+
 // SetTargetID sets the "target_id" field.
 func (pu *PkgUpdate) SetTargetID(u uuid.UUID) *PkgUpdate {
 	pu.mutation.SetTargetID(u)
@@ -151,6 +320,11 @@ func (pu *PkgUpdate) ExecX(ctx context.Context) {
 
 // check runs all checks and user-defined validators on the builder.
 func (pu *PkgUpdate) check() error {
+	if v, ok := pu.mutation.Format(); ok {
+		if err := pkg.FormatValidator(v); err != nil {
+			return &ValidationError{Name: "format", err: fmt.Errorf(`ent: validator failed for field "Pkg.format": %w`, err)}
+		}
+	}
 	if pu.mutation.TargetCleared() && len(pu.mutation.TargetIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "Pkg.target"`)
 	}
@@ -181,9 +355,48 @@ func (pu *PkgUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := pu.mutation.Version(); ok {
 		_spec.SetField(pkg.FieldVersion, field.TypeString, value)
 	}
+	if value, ok := pu.mutation.Revision(); ok {
+		_spec.SetField(pkg.FieldRevision, field.TypeString, value)
+	}
+	if value, ok := pu.mutation.BuildID(); ok {
+		_spec.SetField(pkg.FieldBuildID, field.TypeInt, value)
+	}
+	if value, ok := pu.mutation.AddedBuildID(); ok {
+		_spec.AddField(pkg.FieldBuildID, field.TypeInt, value)
+	}
 	if value, ok := pu.mutation.PackageFields(); ok {
 		_spec.SetField(pkg.FieldPackageFields, field.TypeJSON, value)
 	}
+	if value, ok := pu.mutation.Format(); ok {
+		_spec.SetField(pkg.FieldFormat, field.TypeEnum, value)
+	}
+	if value, ok := pu.mutation.Path(); ok {
+		_spec.SetField(pkg.FieldPath, field.TypeString, value)
+	}
+	if value, ok := pu.mutation.ObjectKey(); ok {
+		_spec.SetField(pkg.FieldObjectKey, field.TypeString, value)
+	}
+	if value, ok := pu.mutation.Size(); ok {
+		_spec.SetField(pkg.FieldSize, field.TypeInt64, value)
+	}
+	if value, ok := pu.mutation.AddedSize(); ok {
+		_spec.AddField(pkg.FieldSize, field.TypeInt64, value)
+	}
+	if value, ok := pu.mutation.Sha1(); ok {
+		_spec.SetField(pkg.FieldSha1, field.TypeString, value)
+	}
+	if value, ok := pu.mutation.Md5(); ok {
+		_spec.SetField(pkg.FieldMd5, field.TypeString, value)
+	}
+	if value, ok := pu.mutation.Blake2b(); ok {
+		_spec.SetField(pkg.FieldBlake2b, field.TypeString, value)
+	}
+	if value, ok := pu.mutation.Sha512(); ok {
+		_spec.SetField(pkg.FieldSha512, field.TypeString, value)
+	}
+	if value, ok := pu.mutation.Mtime(); ok {
+		_spec.SetField(pkg.FieldMtime, field.TypeTime, value)
+	}
 	if pu.mutation.TargetCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -289,12 +502,180 @@ func (puo *PkgUpdateOne) SetNillableVersion(s *string) *PkgUpdateOne {
 	return puo
 }
 
+// SetRevision sets the "revision" field.
+func (puo *PkgUpdateOne) SetRevision(s string) *PkgUpdateOne {
+	puo.mutation.SetRevision(s)
+	return puo
+}
+
+// SetNillableRevision sets the "revision" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableRevision(s *string) *PkgUpdateOne {
+	if s != nil {
+		puo.SetRevision(*s)
+	}
+	return puo
+}
+
+// SetBuildID sets the "build_id" field.
+func (puo *PkgUpdateOne) SetBuildID(i int) *PkgUpdateOne {
+	puo.mutation.ResetBuildID()
+	puo.mutation.SetBuildID(i)
+	return puo
+}
+
+// SetNillableBuildID sets the "build_id" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableBuildID(i *int) *PkgUpdateOne {
+	if i != nil {
+		puo.SetBuildID(*i)
+	}
+	return puo
+}
+
+// AddBuildID adds i to the "build_id" field.
+func (puo *PkgUpdateOne) AddBuildID(i int) *PkgUpdateOne {
+	puo.mutation.AddBuildID(i)
+	return puo
+}
+
 // SetPackageFields sets the "package_fields" field.
 func (puo *PkgUpdateOne) SetPackageFields(pc *parser.PackageCommon) *PkgUpdateOne {
 	puo.mutation.SetPackageFields(pc)
 	return puo
 }
 
+// SetFormat sets the "format" field.
+func (puo *PkgUpdateOne) SetFormat(pk pkg.Format) *PkgUpdateOne {
+	puo.mutation.SetFormat(pk)
+	return puo
+}
+
+// SetNillableFormat sets the "format" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableFormat(pk *pkg.Format) *PkgUpdateOne {
+	if pk != nil {
+		puo.SetFormat(*pk)
+	}
+	return puo
+}
+
+// SetPath sets the "path" field.
+func (puo *PkgUpdateOne) SetPath(s string) *PkgUpdateOne {
+	puo.mutation.SetPath(s)
+	return puo
+}
+
+// SetNillablePath sets the "path" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillablePath(s *string) *PkgUpdateOne {
+	if s != nil {
+		puo.SetPath(*s)
+	}
+	return puo
+}
+
+// SetObjectKey sets the "object_key" field.
+func (puo *PkgUpdateOne) SetObjectKey(s string) *PkgUpdateOne {
+	puo.mutation.SetObjectKey(s)
+	return puo
+}
+
+// SetNillableObjectKey sets the "object_key" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableObjectKey(s *string) *PkgUpdateOne {
+	if s != nil {
+		puo.SetObjectKey(*s)
+	}
+	return puo
+}
+
+// SetSize sets the "size" field.
+func (puo *PkgUpdateOne) SetSize(i int64) *PkgUpdateOne {
+	puo.mutation.ResetSize()
+	puo.mutation.SetSize(i)
+	return puo
+}
+
+// SetNillableSize sets the "size" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableSize(i *int64) *PkgUpdateOne {
+	if i != nil {
+		puo.SetSize(*i)
+	}
+	return puo
+}
+
+// AddSize adds i to the "size" field.
+func (puo *PkgUpdateOne) AddSize(i int64) *PkgUpdateOne {
+	puo.mutation.AddSize(i)
+	return puo
+}
+
+// SetSha1 sets the "sha1" field.
+func (puo *PkgUpdateOne) SetSha1(s string) *PkgUpdateOne {
+	puo.mutation.SetSha1(s)
+	return puo
+}
+
+// SetNillableSha1 sets the "sha1" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableSha1(s *string) *PkgUpdateOne {
+	if s != nil {
+		puo.SetSha1(*s)
+	}
+	return puo
+}
+
+// SetMd5 sets the "md5" field.
+func (puo *PkgUpdateOne) SetMd5(s string) *PkgUpdateOne {
+	puo.mutation.SetMd5(s)
+	return puo
+}
+
+// SetNillableMd5 sets the "md5" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableMd5(s *string) *PkgUpdateOne {
+	if s != nil {
+		puo.SetMd5(*s)
+	}
+	return puo
+}
+
+// SetBlake2b sets the "blake2b" field.
+func (puo *PkgUpdateOne) SetBlake2b(s string) *PkgUpdateOne {
+	puo.mutation.SetBlake2b(s)
+	return puo
+}
+
+// SetNillableBlake2b sets the "blake2b" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableBlake2b(s *string) *PkgUpdateOne {
+	if s != nil {
+		puo.SetBlake2b(*s)
+	}
+	return puo
+}
+
+// SetSha512 sets the "sha512" field.
+func (puo *PkgUpdateOne) SetSha512(s string) *PkgUpdateOne {
+	puo.mutation.SetSha512(s)
+	return puo
+}
+
+// SetNillableSha512 sets the "sha512" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableSha512(s *string) *PkgUpdateOne {
+	if s != nil {
+		puo.SetSha512(*s)
+	}
+	return puo
+}
+
+// SetMtime sets the "mtime" field.
+func (puo *PkgUpdateOne) SetMtime(t time.Time) *PkgUpdateOne {
+	puo.mutation.SetMtime(t)
+	return puo
+}
+
+// SetNillableMtime sets the "mtime" field if the given value is not nil.
+func (puo *PkgUpdateOne) SetNillableMtime(t *time.Time) *PkgUpdateOne {
+	if t != nil {
+		puo.SetMtime(*t)
+	}
+	return puo
+}
+
 // SetTargetID sets the "target_id" field.
 func (puo *PkgUpdateOne) SetTargetID(u uuid.UUID) *PkgUpdateOne {
 	puo.mutation.SetTargetID(u)
@@ -367,6 +748,11 @@ func (puo *PkgUpdateOne) ExecX(ctx context.Context) {
 
 // check runs all checks and user-defined validators on the builder.
 func (puo *PkgUpdateOne) check() error {
+	if v, ok := puo.mutation.Format(); ok {
+		if err := pkg.FormatValidator(v); err != nil {
+			return &ValidationError{Name: "format", err: fmt.Errorf(`ent: validator failed for field "Pkg.format": %w`, err)}
+		}
+	}
 	if puo.mutation.TargetCleared() && len(puo.mutation.TargetIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "Pkg.target"`)
 	}
@@ -414,9 +800,48 @@ func (puo *PkgUpdateOne) sqlSave(ctx context.Context) (_node *Pkg, err error) {
 	if value, ok := puo.mutation.Version(); ok {
 		_spec.SetField(pkg.FieldVersion, field.TypeString, value)
 	}
+	if value, ok := puo.mutation.Revision(); ok {
+		_spec.SetField(pkg.FieldRevision, field.TypeString, value)
+	}
+	if value, ok := puo.mutation.BuildID(); ok {
+		_spec.SetField(pkg.FieldBuildID, field.TypeInt, value)
+	}
+	if value, ok := puo.mutation.AddedBuildID(); ok {
+		_spec.AddField(pkg.FieldBuildID, field.TypeInt, value)
+	}
 	if value, ok := puo.mutation.PackageFields(); ok {
 		_spec.SetField(pkg.FieldPackageFields, field.TypeJSON, value)
 	}
+	if value, ok := puo.mutation.Format(); ok {
+		_spec.SetField(pkg.FieldFormat, field.TypeEnum, value)
+	}
+	if value, ok := puo.mutation.Path(); ok {
+		_spec.SetField(pkg.FieldPath, field.TypeString, value)
+	}
+	if value, ok := puo.mutation.ObjectKey(); ok {
+		_spec.SetField(pkg.FieldObjectKey, field.TypeString, value)
+	}
+	if value, ok := puo.mutation.Size(); ok {
+		_spec.SetField(pkg.FieldSize, field.TypeInt64, value)
+	}
+	if value, ok := puo.mutation.AddedSize(); ok {
+		_spec.AddField(pkg.FieldSize, field.TypeInt64, value)
+	}
+	if value, ok := puo.mutation.Sha1(); ok {
+		_spec.SetField(pkg.FieldSha1, field.TypeString, value)
+	}
+	if value, ok := puo.mutation.Md5(); ok {
+		_spec.SetField(pkg.FieldMd5, field.TypeString, value)
+	}
+	if value, ok := puo.mutation.Blake2b(); ok {
+		_spec.SetField(pkg.FieldBlake2b, field.TypeString, value)
+	}
+	if value, ok := puo.mutation.Sha512(); ok {
+		_spec.SetField(pkg.FieldSha512, field.TypeString, value)
+	}
+	if value, ok := puo.mutation.Mtime(); ok {
+		_spec.SetField(pkg.FieldMtime, field.TypeTime, value)
+	}
 	if puo.mutation.TargetCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
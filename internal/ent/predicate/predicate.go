@@ -11,3 +11,6 @@ type Pkg func(*sql.Selector)
 
 // Target is the predicate function for target builders.
 type Target func(*sql.Selector)
+
+// Token is the predicate function for token builders.
+type Token func(*sql.Selector)
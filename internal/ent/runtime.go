@@ -3,10 +3,13 @@
 package ent
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/jaredallard/binhost/internal/ent/pkg"
 	"github.com/jaredallard/binhost/internal/ent/schema"
 	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/ent/token"
 )
 
 // The init function reads all schema descriptors with runtime code
@@ -15,14 +18,72 @@ import (
 func init() {
 	pkgFields := schema.Pkg{}.Fields()
 	_ = pkgFields
+	// pkgDescRevision is the schema descriptor for revision field.
+	pkgDescRevision := pkgFields[5].Descriptor()
+	// pkg.DefaultRevision holds the default value on creation for the revision field.
+	pkg.DefaultRevision = pkgDescRevision.Default.(string)
+	// pkgDescBuildID is the schema descriptor for build_id field.
+	pkgDescBuildID := pkgFields[6].Descriptor()
+	// pkg.DefaultBuildID holds the default value on creation for the build_id field.
+	pkg.DefaultBuildID = pkgDescBuildID.Default.(int)
+	// pkgDescSize is the schema descriptor for size field.
+	pkgDescSize := pkgFields[11].Descriptor()
+	// pkg.DefaultSize holds the default value on creation for the size field.
+	pkg.DefaultSize = pkgDescSize.Default.(int64)
+	// pkgDescSha1 is the schema descriptor for sha1 field.
+	pkgDescSha1 := pkgFields[12].Descriptor()
+	// pkg.DefaultSha1 holds the default value on creation for the sha1 field.
+	pkg.DefaultSha1 = pkgDescSha1.Default.(string)
+	// pkgDescMd5 is the schema descriptor for md5 field.
+	pkgDescMd5 := pkgFields[13].Descriptor()
+	// pkg.DefaultMd5 holds the default value on creation for the md5 field.
+	pkg.DefaultMd5 = pkgDescMd5.Default.(string)
+	// pkgDescBlake2b is the schema descriptor for blake2b field.
+	pkgDescBlake2b := pkgFields[14].Descriptor()
+	// pkg.DefaultBlake2b holds the default value on creation for the blake2b field.
+	pkg.DefaultBlake2b = pkgDescBlake2b.Default.(string)
+	// pkgDescSha512 is the schema descriptor for sha512 field.
+	pkgDescSha512 := pkgFields[15].Descriptor()
+	// pkg.DefaultSha512 holds the default value on creation for the sha512 field.
+	pkg.DefaultSha512 = pkgDescSha512.Default.(string)
+	// pkgDescMtime is the schema descriptor for mtime field.
+	pkgDescMtime := pkgFields[16].Descriptor()
+	// pkg.DefaultMtime holds the default value on creation for the mtime field.
+	pkg.DefaultMtime = pkgDescMtime.Default.(func() time.Time)
 	// pkgDescID is the schema descriptor for id field.
 	pkgDescID := pkgFields[0].Descriptor()
 	// pkg.DefaultID holds the default value on creation for the id field.
 	pkg.DefaultID = pkgDescID.Default.(func() uuid.UUID)
 	targetFields := schema.Target{}.Fields()
 	_ = targetFields
+	// targetDescDescription is the schema descriptor for description field.
+	targetDescDescription := targetFields[2].Descriptor()
+	// target.DefaultDescription holds the default value on creation for the description field.
+	target.DefaultDescription = targetDescDescription.Default.(string)
+	// targetDescOwner is the schema descriptor for owner field.
+	targetDescOwner := targetFields[3].Descriptor()
+	// target.DefaultOwner holds the default value on creation for the owner field.
+	target.DefaultOwner = targetDescOwner.Default.(string)
+	// targetDescPrivate is the schema descriptor for private field.
+	targetDescPrivate := targetFields[4].Descriptor()
+	// target.DefaultPrivate holds the default value on creation for the private field.
+	target.DefaultPrivate = targetDescPrivate.Default.(bool)
+	// targetDescIndexUpdatedAt is the schema descriptor for index_updated_at field.
+	targetDescIndexUpdatedAt := targetFields[6].Descriptor()
+	// target.DefaultIndexUpdatedAt holds the default value on creation for the index_updated_at field.
+	target.DefaultIndexUpdatedAt = targetDescIndexUpdatedAt.Default.(func() time.Time)
 	// targetDescID is the schema descriptor for id field.
 	targetDescID := targetFields[0].Descriptor()
 	// target.DefaultID holds the default value on creation for the id field.
 	target.DefaultID = targetDescID.Default.(func() uuid.UUID)
+	tokenFields := schema.Token{}.Fields()
+	_ = tokenFields
+	// tokenDescCreatedAt is the schema descriptor for created_at field.
+	tokenDescCreatedAt := tokenFields[4].Descriptor()
+	// token.DefaultCreatedAt holds the default value on creation for the created_at field.
+	token.DefaultCreatedAt = tokenDescCreatedAt.Default.(func() time.Time)
+	// tokenDescID is the schema descriptor for id field.
+	tokenDescID := tokenFields[0].Descriptor()
+	// token.DefaultID holds the default value on creation for the id field.
+	token.DefaultID = tokenDescID.Default.(func() uuid.UUID)
 }
@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"time"
+
 	"entgo.io/ent"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
@@ -22,15 +24,34 @@ func (Pkg) Fields() []ent.Field {
 		field.String("category"),
 		field.String("name"),
 		field.String("version"),
+		field.String("revision").Default("").
+			Comment("Revision of the package (e.g., r1), empty if the package has no revision"),
+		field.Int("build_id").Default(1).
+			Comment("BUILD_ID of the package, allowing multiple builds of the same version (binpkg-multi-instance)"),
 		field.JSON("package_fields", &parser.PackageCommon{}).
 			Comment("Gentoo specific fields shared between the index and metadata.tar files"),
+		field.Enum("format").Values("gpkg", "xpak").Default("gpkg").
+			Comment("Format of the package archive (BINPKG_FORMAT)"),
+		field.String("path").
+			Comment("Path of the package archive relative to the target, used as PATH in the Packages index"),
+		field.String("object_key").
+			Comment("Key of the package archive in object storage"),
+		field.Int64("size").Default(0).
+			Comment("Size of the package archive in bytes"),
+		field.String("sha1").Default(""),
+		field.String("md5").Default(""),
+		field.String("blake2b").Default(""),
+		field.String("sha512").Default(""),
+		field.Time("mtime").Default(time.Now).
+			Comment("Time the package archive was uploaded"),
 		field.UUID("target_id", uuid.UUID{}),
 	}
 }
 
 func (Pkg) Indexes() []ent.Index {
 	return []ent.Index{
-		index.Fields("repository", "category", "name", "version", "target_id").Unique(),
+		index.Fields("repository", "category", "name", "version", "revision", "build_id", "target_id").Unique(),
+		index.Fields("target_id", "path").Unique(),
 	}
 }
 
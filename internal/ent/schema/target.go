@@ -1,10 +1,13 @@
 package schema
 
 import (
+	"time"
+
 	"entgo.io/ent"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/parser"
 )
 
 // Target holds the schema definition for the Target entity.
@@ -17,6 +20,15 @@ func (Target) Fields() []ent.Field {
 	return []ent.Field{
 		field.UUID("id", uuid.UUID{}).Default(uuid.New).Unique(),
 		field.String("name").Unique(),
+		field.String("description").Default(""),
+		field.String("owner").Default("").
+			Comment("Person or team responsible for the target"),
+		field.Bool("private").Default(false).
+			Comment("Private targets can only be read with a token with the target:read scope"),
+		field.JSON("profile", &parser.Profile{}).Optional().
+			Comment("Header of the Packages index of the target"),
+		field.Time("index_updated_at").Default(time.Now).
+			Comment("Last time the packages in the target changed, used as the TIMESTAMP of the Packages index"),
 	}
 }
 
@@ -24,5 +36,6 @@ func (Target) Fields() []ent.Field {
 func (Target) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.From("packages", Pkg.Type).Ref("target"),
+		edge.From("tokens", Token.Type).Ref("targets"),
 	}
 }
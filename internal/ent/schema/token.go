@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+)
+
+// Token holds the schema definition for the Token entity.
+type Token struct {
+	ent.Schema
+}
+
+// Fields of the Token.
+func (Token) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New).Unique(),
+		field.String("name").
+			Comment("Human readable name of the token, e.g., what it is used by"),
+		field.String("hash").Unique().Sensitive().
+			Comment("SHA256 of the token, the token itself is never stored"),
+		field.Strings("scopes").
+			Comment("Scopes granted to the token, e.g., target:write"),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("expires_at").Optional().Nillable(),
+	}
+}
+
+// Edges of the Token.
+func (Token) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("targets", Target.Type).
+			Comment("Targets the target scopes are limited to, all targets if empty"),
+	}
+}
@@ -3,13 +3,16 @@
 package ent
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"github.com/google/uuid"
 	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/parser"
 )
 
 // Target is the model entity for the Target schema.
@@ -19,6 +22,16 @@ type Target struct {
 	ID uuid.UUID `json:"id,omitempty"`
 	// Name holds the value of the "name" field.
 	Name string `json:"name,omitempty"`
+	// Description holds the value of the "description" field.
+	Description string `json:"description,omitempty"`
+	// Person or team responsible for the target
+	Owner string `json:"owner,omitempty"`
+	// Private targets can only be read with a token with the target:read scope
+	Private bool `json:"private,omitempty"`
+	// Header of the Packages index of the target
+	Profile *parser.Profile `json:"profile,omitempty"`
+	// Last time the packages in the target changed, used as the TIMESTAMP of the Packages index
+	IndexUpdatedAt time.Time `json:"index_updated_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the TargetQuery when eager-loading is set.
 	Edges        TargetEdges `json:"edges"`
@@ -29,9 +42,11 @@ type Target struct {
 type TargetEdges struct {
 	// Packages holds the value of the packages edge.
 	Packages []*Pkg `json:"packages,omitempty"`
+	// Tokens holds the value of the tokens edge.
+	Tokens []*Token `json:"tokens,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [1]bool
+	loadedTypes [2]bool
 }
 
 // PackagesOrErr returns the Packages value or an error if the edge
@@ -43,13 +58,28 @@ func (e TargetEdges) PackagesOrErr() ([]*Pkg, error) {
 	return nil, &NotLoadedError{edge: "packages"}
 }
 
+// TokensOrErr returns the Tokens value or an error if the edge
+// was not loaded in eager-loading.
+func (e TargetEdges) TokensOrErr() ([]*Token, error) {
+	if e.loadedTypes[1] {
+		return e.Tokens, nil
+	}
+	return nil, &NotLoadedError{edge: "tokens"}
+}
+
 // scanValues returns the types for scanning values from sql.Rows.
 func (*Target) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case target.FieldName:
+		case target.FieldProfile:
+			values[i] = new([]byte)
+		case target.FieldPrivate:
+			values[i] = new(sql.NullBool)
+		case target.FieldName, target.FieldDescription, target.FieldOwner:
 			values[i] = new(sql.NullString)
+		case target.FieldIndexUpdatedAt:
+			values[i] = new(sql.NullTime)
 		case target.FieldID:
 			values[i] = new(uuid.UUID)
 		default:
@@ -79,6 +109,38 @@ func (t *Target) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				t.Name = value.String
 			}
+		case target.FieldDescription:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field description", values[i])
+			} else if value.Valid {
+				t.Description = value.String
+			}
+		case target.FieldOwner:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field owner", values[i])
+			} else if value.Valid {
+				t.Owner = value.String
+			}
+		case target.FieldPrivate:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field private", values[i])
+			} else if value.Valid {
+				t.Private = value.Bool
+			}
+		case target.FieldProfile:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field profile", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &t.Profile); err != nil {
+					return fmt.Errorf("unmarshal field profile: %w", err)
+				}
+			}
+		case target.FieldIndexUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field index_updated_at", values[i])
+			} else if value.Valid {
+				t.IndexUpdatedAt = value.Time
+			}
 		default:
 			t.selectValues.Set(columns[i], values[i])
 		}
@@ -97,6 +159,11 @@ func (t *Target) QueryPackages() *PkgQuery {
 	return NewTargetClient(t.config).QueryPackages(t)
 }
 
+// QueryTokens queries the "tokens" edge of the Target entity.
+func (t *Target) QueryTokens() *TokenQuery {
+	return NewTargetClient(t.config).QueryTokens(t)
+}
+
 // Update returns a builder for updating this Target.
 // Note that you need to call Target.Unwrap() before calling this method if this Target
 // was returned from a transaction, and the transaction was committed or rolled back.
@@ -122,6 +189,21 @@ func (t *Target) String() string {
 	builder.WriteString(fmt.Sprintf("id=%v, ", t.ID))
 	builder.WriteString("name=")
 	builder.WriteString(t.Name)
+	builder.WriteString(", ")
+	builder.WriteString("description=")
+	builder.WriteString(t.Description)
+	builder.WriteString(", ")
+	builder.WriteString("owner=")
+	builder.WriteString(t.Owner)
+	builder.WriteString(", ")
+	builder.WriteString("private=")
+	builder.WriteString(fmt.Sprintf("%v", t.Private))
+	builder.WriteString(", ")
+	builder.WriteString("profile=")
+	builder.WriteString(fmt.Sprintf("%v", t.Profile))
+	builder.WriteString(", ")
+	builder.WriteString("index_updated_at=")
+	builder.WriteString(t.IndexUpdatedAt.Format(time.ANSIC))
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -3,6 +3,8 @@
 package target
 
 import (
+	"time"
+
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/google/uuid"
@@ -15,8 +17,20 @@ const (
 	FieldID = "id"
 	// FieldName holds the string denoting the name field in the database.
 	FieldName = "name"
+	// FieldDescription holds the string denoting the description field in the database.
+	FieldDescription = "description"
+	// FieldOwner holds the string denoting the owner field in the database.
+	FieldOwner = "owner"
+	// FieldPrivate holds the string denoting the private field in the database.
+	FieldPrivate = "private"
+	// FieldProfile holds the string denoting the profile field in the database.
+	FieldProfile = "profile"
+	// FieldIndexUpdatedAt holds the string denoting the index_updated_at field in the database.
+	FieldIndexUpdatedAt = "index_updated_at"
 	// EdgePackages holds the string denoting the packages edge name in mutations.
 	EdgePackages = "packages"
+	// EdgeTokens holds the string denoting the tokens edge name in mutations.
+	EdgeTokens = "tokens"
 	// Table holds the table name of the target in the database.
 	Table = "targets"
 	// PackagesTable is the table that holds the packages relation/edge.
@@ -26,14 +40,30 @@ const (
 	PackagesInverseTable = "pkgs"
 	// PackagesColumn is the table column denoting the packages relation/edge.
 	PackagesColumn = "target_id"
+	// TokensTable is the table that holds the tokens relation/edge. The primary key declared below.
+	TokensTable = "token_targets"
+	// TokensInverseTable is the table name for the Token entity.
+	// It exists in this package in order to avoid circular dependency with the "token" package.
+	TokensInverseTable = "tokens"
 )
 
 // Columns holds all SQL columns for target fields.
 var Columns = []string{
 	FieldID,
 	FieldName,
+	FieldDescription,
+	FieldOwner,
+	FieldPrivate,
+	FieldProfile,
+	FieldIndexUpdatedAt,
 }
 
+var (
+	// TokensPrimaryKey and TokensColumn2 are the table columns denoting the
+	// primary key for the tokens relation (M2M).
+	TokensPrimaryKey = []string{"token_id", "target_id"}
+)
+
 // ValidColumn reports if the column name is valid (part of the table columns).
 func ValidColumn(column string) bool {
 	for i := range Columns {
@@ -45,6 +75,14 @@ func ValidColumn(column string) bool {
 }
 
 var (
+	// DefaultDescription holds the default value on creation for the "description" field.
+	DefaultDescription string
+	// DefaultOwner holds the default value on creation for the "owner" field.
+	DefaultOwner string
+	// DefaultPrivate holds the default value on creation for the "private" field.
+	DefaultPrivate bool
+	// DefaultIndexUpdatedAt holds the default value on creation for the "index_updated_at" field.
+	DefaultIndexUpdatedAt func() time.Time
 	// DefaultID holds the default value on creation for the "id" field.
 	DefaultID func() uuid.UUID
 )
@@ -62,6 +100,26 @@ func ByName(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldName, opts...).ToFunc()
 }
 
+// ByDescription orders the results by the description field.
+func ByDescription(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDescription, opts...).ToFunc()
+}
+
+// ByOwner orders the results by the owner field.
+func ByOwner(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldOwner, opts...).ToFunc()
+}
+
+// ByPrivate orders the results by the private field.
+func ByPrivate(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPrivate, opts...).ToFunc()
+}
+
+// ByIndexUpdatedAt orders the results by the index_updated_at field.
+func ByIndexUpdatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldIndexUpdatedAt, opts...).ToFunc()
+}
+
 // ByPackagesCount orders the results by packages count.
 func ByPackagesCount(opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
@@ -75,6 +133,20 @@ func ByPackages(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
 		sqlgraph.OrderByNeighborTerms(s, newPackagesStep(), append([]sql.OrderTerm{term}, terms...)...)
 	}
 }
+
+// ByTokensCount orders the results by tokens count.
+func ByTokensCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newTokensStep(), opts...)
+	}
+}
+
+// ByTokens orders the results by tokens terms.
+func ByTokens(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newTokensStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
 func newPackagesStep() *sqlgraph.Step {
 	return sqlgraph.NewStep(
 		sqlgraph.From(Table, FieldID),
@@ -82,3 +154,10 @@ func newPackagesStep() *sqlgraph.Step {
 		sqlgraph.Edge(sqlgraph.O2M, true, PackagesTable, PackagesColumn),
 	)
 }
+func newTokensStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(TokensInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2M, true, TokensTable, TokensPrimaryKey...),
+	)
+}
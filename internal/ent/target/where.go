@@ -3,6 +3,8 @@
 package target
 
 import (
+	"time"
+
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/google/uuid"
@@ -59,6 +61,26 @@ func Name(v string) predicate.Target {
 	return predicate.Target(sql.FieldEQ(FieldName, v))
 }
 
+// Description applies equality check predicate on the "description" field. It's identical to DescriptionEQ.
+func Description(v string) predicate.Target {
+	return predicate.Target(sql.FieldEQ(FieldDescription, v))
+}
+
+// Owner applies equality check predicate on the "owner" field. It's identical to OwnerEQ.
+func Owner(v string) predicate.Target {
+	return predicate.Target(sql.FieldEQ(FieldOwner, v))
+}
+
+// Private applies equality check predicate on the "private" field. It's identical to PrivateEQ.
+func Private(v bool) predicate.Target {
+	return predicate.Target(sql.FieldEQ(FieldPrivate, v))
+}
+
+// IndexUpdatedAt applies equality check predicate on the "index_updated_at" field. It's identical to IndexUpdatedAtEQ.
+func IndexUpdatedAt(v time.Time) predicate.Target {
+	return predicate.Target(sql.FieldEQ(FieldIndexUpdatedAt, v))
+}
+
 // NameEQ applies the EQ predicate on the "name" field.
 func NameEQ(v string) predicate.Target {
 	return predicate.Target(sql.FieldEQ(FieldName, v))
@@ -124,6 +146,196 @@ func NameContainsFold(v string) predicate.Target {
 	return predicate.Target(sql.FieldContainsFold(FieldName, v))
 }
 
+// DescriptionEQ applies the EQ predicate on the "description" field.
+func DescriptionEQ(v string) predicate.Target {
+	return predicate.Target(sql.FieldEQ(FieldDescription, v))
+}
+
+// DescriptionNEQ applies the NEQ predicate on the "description" field.
+func DescriptionNEQ(v string) predicate.Target {
+	return predicate.Target(sql.FieldNEQ(FieldDescription, v))
+}
+
+// DescriptionIn applies the In predicate on the "description" field.
+func DescriptionIn(vs ...string) predicate.Target {
+	return predicate.Target(sql.FieldIn(FieldDescription, vs...))
+}
+
+// DescriptionNotIn applies the NotIn predicate on the "description" field.
+func DescriptionNotIn(vs ...string) predicate.Target {
+	return predicate.Target(sql.FieldNotIn(FieldDescription, vs...))
+}
+
+// DescriptionGT applies the GT predicate on the "description" field.
+func DescriptionGT(v string) predicate.Target {
+	return predicate.Target(sql.FieldGT(FieldDescription, v))
+}
+
+// DescriptionGTE applies the GTE predicate on the "description" field.
+func DescriptionGTE(v string) predicate.Target {
+	return predicate.Target(sql.FieldGTE(FieldDescription, v))
+}
+
+// DescriptionLT applies the LT predicate on the "description" field.
+func DescriptionLT(v string) predicate.Target {
+	return predicate.Target(sql.FieldLT(FieldDescription, v))
+}
+
+// DescriptionLTE applies the LTE predicate on the "description" field.
+func DescriptionLTE(v string) predicate.Target {
+	return predicate.Target(sql.FieldLTE(FieldDescription, v))
+}
+
+// DescriptionContains applies the Contains predicate on the "description" field.
+func DescriptionContains(v string) predicate.Target {
+	return predicate.Target(sql.FieldContains(FieldDescription, v))
+}
+
+// DescriptionHasPrefix applies the HasPrefix predicate on the "description" field.
+func DescriptionHasPrefix(v string) predicate.Target {
+	return predicate.Target(sql.FieldHasPrefix(FieldDescription, v))
+}
+
+// DescriptionHasSuffix applies the HasSuffix predicate on the "description" field.
+func DescriptionHasSuffix(v string) predicate.Target {
+	return predicate.Target(sql.FieldHasSuffix(FieldDescription, v))
+}
+
+// DescriptionEqualFold applies the EqualFold predicate on the "description" field.
+func DescriptionEqualFold(v string) predicate.Target {
+	return predicate.Target(sql.FieldEqualFold(FieldDescription, v))
+}
+
+// DescriptionContainsFold applies the ContainsFold predicate on the "description" field.
+func DescriptionContainsFold(v string) predicate.Target {
+	return predicate.Target(sql.FieldContainsFold(FieldDescription, v))
+}
+
+// OwnerEQ applies the EQ predicate on the "owner" field.
+func OwnerEQ(v string) predicate.Target {
+	return predicate.Target(sql.FieldEQ(FieldOwner, v))
+}
+
+// OwnerNEQ applies the NEQ predicate on the "owner" field.
+func OwnerNEQ(v string) predicate.Target {
+	return predicate.Target(sql.FieldNEQ(FieldOwner, v))
+}
+
+// OwnerIn applies the In predicate on the "owner" field.
+func OwnerIn(vs ...string) predicate.Target {
+	return predicate.Target(sql.FieldIn(FieldOwner, vs...))
+}
+
+// OwnerNotIn applies the NotIn predicate on the "owner" field.
+func OwnerNotIn(vs ...string) predicate.Target {
+	return predicate.Target(sql.FieldNotIn(FieldOwner, vs...))
+}
+
+// OwnerGT applies the GT predicate on the "owner" field.
+func OwnerGT(v string) predicate.Target {
+	return predicate.Target(sql.FieldGT(FieldOwner, v))
+}
+
+// OwnerGTE applies the GTE predicate on the "owner" field.
+func OwnerGTE(v string) predicate.Target {
+	return predicate.Target(sql.FieldGTE(FieldOwner, v))
+}
+
+// OwnerLT applies the LT predicate on the "owner" field.
+func OwnerLT(v string) predicate.Target {
+	return predicate.Target(sql.FieldLT(FieldOwner, v))
+}
+
+// OwnerLTE applies the LTE predicate on the "owner" field.
+func OwnerLTE(v string) predicate.Target {
+	return predicate.Target(sql.FieldLTE(FieldOwner, v))
+}
+
+// OwnerContains applies the Contains predicate on the "owner" field.
+func OwnerContains(v string) predicate.Target {
+	return predicate.Target(sql.FieldContains(FieldOwner, v))
+}
+
+// OwnerHasPrefix applies the HasPrefix predicate on the "owner" field.
+func OwnerHasPrefix(v string) predicate.Target {
+	return predicate.Target(sql.FieldHasPrefix(FieldOwner, v))
+}
+
+// OwnerHasSuffix applies the HasSuffix predicate on the "owner" field.
+func OwnerHasSuffix(v string) predicate.Target {
+	return predicate.Target(sql.FieldHasSuffix(FieldOwner, v))
+}
+
+// OwnerEqualFold applies the EqualFold predicate on the "owner" field.
+func OwnerEqualFold(v string) predicate.Target {
+	return predicate.Target(sql.FieldEqualFold(FieldOwner, v))
+}
+
+// OwnerContainsFold applies the ContainsFold predicate on the "owner" field.
+func OwnerContainsFold(v string) predicate.Target {
+	return predicate.Target(sql.FieldContainsFold(FieldOwner, v))
+}
+
+// PrivateEQ applies the EQ predicate on the "private" field.
+func PrivateEQ(v bool) predicate.Target {
+	return predicate.Target(sql.FieldEQ(FieldPrivate, v))
+}
+
+// PrivateNEQ applies the NEQ predicate on the "private" field.
+func PrivateNEQ(v bool) predicate.Target {
+	return predicate.Target(sql.FieldNEQ(FieldPrivate, v))
+}
+
+// ProfileIsNil applies the IsNil predicate on the "profile" field.
+func ProfileIsNil() predicate.Target {
+	return predicate.Target(sql.FieldIsNull(FieldProfile))
+}
+
+// ProfileNotNil applies the NotNil predicate on the "profile" field.
+func ProfileNotNil() predicate.Target {
+	return predicate.Target(sql.FieldNotNull(FieldProfile))
+}
+
+// IndexUpdatedAtEQ applies the EQ predicate on the "index_updated_at" field.
+func IndexUpdatedAtEQ(v time.Time) predicate.Target {
+	return predicate.Target(sql.FieldEQ(FieldIndexUpdatedAt, v))
+}
+
+// IndexUpdatedAtNEQ applies the NEQ predicate on the "index_updated_at" field.
+func IndexUpdatedAtNEQ(v time.Time) predicate.Target {
+	return predicate.Target(sql.FieldNEQ(FieldIndexUpdatedAt, v))
+}
+
+// IndexUpdatedAtIn applies the In predicate on the "index_updated_at" field.
+func IndexUpdatedAtIn(vs ...time.Time) predicate.Target {
+	return predicate.Target(sql.FieldIn(FieldIndexUpdatedAt, vs...))
+}
+
+// IndexUpdatedAtNotIn applies the NotIn predicate on the "index_updated_at" field.
+func IndexUpdatedAtNotIn(vs ...time.Time) predicate.Target {
+	return predicate.Target(sql.FieldNotIn(FieldIndexUpdatedAt, vs...))
+}
+
+// IndexUpdatedAtGT applies the GT predicate on the "index_updated_at" field.
+func IndexUpdatedAtGT(v time.Time) predicate.Target {
+	return predicate.Target(sql.FieldGT(FieldIndexUpdatedAt, v))
+}
+
+// IndexUpdatedAtGTE applies the GTE predicate on the "index_updated_at" field.
+func IndexUpdatedAtGTE(v time.Time) predicate.Target {
+	return predicate.Target(sql.FieldGTE(FieldIndexUpdatedAt, v))
+}
+
+// IndexUpdatedAtLT applies the LT predicate on the "index_updated_at" field.
+func IndexUpdatedAtLT(v time.Time) predicate.Target {
+	return predicate.Target(sql.FieldLT(FieldIndexUpdatedAt, v))
+}
+
+// IndexUpdatedAtLTE applies the LTE predicate on the "index_updated_at" field.
+func IndexUpdatedAtLTE(v time.Time) predicate.Target {
+	return predicate.Target(sql.FieldLTE(FieldIndexUpdatedAt, v))
+}
+
 // HasPackages applies the HasEdge predicate on the "packages" edge.
 func HasPackages() predicate.Target {
 	return predicate.Target(func(s *sql.Selector) {
@@ -147,6 +359,29 @@ func HasPackagesWith(preds ...predicate.Pkg) predicate.Target {
 	})
 }
 
+// HasTokens applies the HasEdge predicate on the "tokens" edge.
+func HasTokens() predicate.Target {
+	return predicate.Target(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2M, true, TokensTable, TokensPrimaryKey...),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasTokensWith applies the HasEdge predicate on the "tokens" edge with a given conditions (other predicates).
+func HasTokensWith(preds ...predicate.Token) predicate.Target {
+	return predicate.Target(func(s *sql.Selector) {
+		step := newTokensStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.Target) predicate.Target {
 	return predicate.Target(sql.AndPredicates(predicates...))
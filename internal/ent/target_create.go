@@ -6,12 +6,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
 	"github.com/google/uuid"
 	"github.com/jaredallard/binhost/internal/ent/pkg"
 	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/ent/token"
+	"github.com/jaredallard/binhost/internal/parser"
 )
 
 // TargetCreate is the builder for creating a Target entity.
@@ -27,6 +30,68 @@ func (tc *TargetCreate) SetName(s string) *TargetCreate {
 	return tc
 }
 
+// SetDescription sets the "description" field.
+func (tc *TargetCreate) SetDescription(s string) *TargetCreate {
+	tc.mutation.SetDescription(s)
+	return tc
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (tc *TargetCreate) SetNillableDescription(s *string) *TargetCreate {
+	if s != nil {
+		tc.SetDescription(*s)
+	}
+	return tc
+}
+
+// SetOwner sets the "owner" field.
+func (tc *TargetCreate) SetOwner(s string) *TargetCreate {
+	tc.mutation.SetOwner(s)
+	return tc
+}
+
+// SetNillableOwner sets the "owner" field if the given value is not nil.
+func (tc *TargetCreate) SetNillableOwner(s *string) *TargetCreate {
+	if s != nil {
+		tc.SetOwner(*s)
+	}
+	return tc
+}
+
+// SetPrivate sets the "private" field.
+func (tc *TargetCreate) SetPrivate(b bool) *TargetCreate {
+	tc.mutation.SetPrivate(b)
+	return tc
+}
+
+// SetNillablePrivate sets the "private" field if the given value is not nil.
+func (tc *TargetCreate) SetNillablePrivate(b *bool) *TargetCreate {
+	if b != nil {
+		tc.SetPrivate(*b)
+	}
+	return tc
+}
+
+// SetProfile sets the "profile" field.
+func (tc *TargetCreate) SetProfile(pa *parser.Profile) *TargetCreate {
+	tc.mutation.SetProfile(pa)
+	return tc
+}
+
+// SetIndexUpdatedAt sets the "index_updated_at" field.
+func (tc *TargetCreate) SetIndexUpdatedAt(t time.Time) *TargetCreate {
+	tc.mutation.SetIndexUpdatedAt(t)
+	return tc
+}
+
+// SetNillableIndexUpdatedAt sets the "index_updated_at" field if the given value is not nil.
+func (tc *TargetCreate) SetNillableIndexUpdatedAt(t *time.Time) *TargetCreate {
+	if t != nil {
+		tc.SetIndexUpdatedAt(*t)
+	}
+	return tc
+}
+
 // SetID sets the "id" field.
 func (tc *TargetCreate) SetID(u uuid.UUID) *TargetCreate {
 	tc.mutation.SetID(u)
@@ -56,6 +121,21 @@ func (tc *TargetCreate) AddPackages(p ...*Pkg) *TargetCreate {
 	return tc.AddPackageIDs(ids...)
 }
 
+// AddTokenIDs adds the "tokens" edge to the Token entity by IDs.
+func (tc *TargetCreate) AddTokenIDs(ids ...uuid.UUID) *TargetCreate {
+	tc.mutation.AddTokenIDs(ids...)
+	return tc
+}
+
+// AddTokens adds the "tokens" edges to the Token entity.
+func (tc *TargetCreate) AddTokens(t ...*Token) *TargetCreate {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tc.AddTokenIDs(ids...)
+}
+
 // Mutation returns the TargetMutation object of the builder.
 func (tc *TargetCreate) Mutation() *TargetMutation {
 	return tc.mutation
@@ -91,6 +171,22 @@ func (tc *TargetCreate) ExecX(ctx context.Context) {
 
 // defaults sets the default values of the builder before save.
 func (tc *TargetCreate) defaults() {
+	if _, ok := tc.mutation.Description(); !ok {
+		v := target.DefaultDescription
+		tc.mutation.SetDescription(v)
+	}
+	if _, ok := tc.mutation.Owner(); !ok {
+		v := target.DefaultOwner
+		tc.mutation.SetOwner(v)
+	}
+	if _, ok := tc.mutation.Private(); !ok {
+		v := target.DefaultPrivate
+		tc.mutation.SetPrivate(v)
+	}
+	if _, ok := tc.mutation.IndexUpdatedAt(); !ok {
+		v := target.DefaultIndexUpdatedAt()
+		tc.mutation.SetIndexUpdatedAt(v)
+	}
 	if _, ok := tc.mutation.ID(); !ok {
 		v := target.DefaultID()
 		tc.mutation.SetID(v)
@@ -102,6 +198,18 @@ func (tc *TargetCreate) check() error {
 	if _, ok := tc.mutation.Name(); !ok {
 		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "Target.name"`)}
 	}
+	if _, ok := tc.mutation.Description(); !ok {
+		return &ValidationError{Name: "description", err: errors.New(`ent: missing required field "Target.description"`)}
+	}
+	if _, ok := tc.mutation.Owner(); !ok {
+		return &ValidationError{Name: "owner", err: errors.New(`ent: missing required field "Target.owner"`)}
+	}
+	if _, ok := tc.mutation.Private(); !ok {
+		return &ValidationError{Name: "private", err: errors.New(`ent: missing required field "Target.private"`)}
+	}
+	if _, ok := tc.mutation.IndexUpdatedAt(); !ok {
+		return &ValidationError{Name: "index_updated_at", err: errors.New(`ent: missing required field "Target.index_updated_at"`)}
+	}
 	return nil
 }
 
@@ -141,6 +249,26 @@ func (tc *TargetCreate) createSpec() (*Target, *sqlgraph.CreateSpec) {
 		_spec.SetField(target.FieldName, field.TypeString, value)
 		_node.Name = value
 	}
+	if value, ok := tc.mutation.Description(); ok {
+		_spec.SetField(target.FieldDescription, field.TypeString, value)
+		_node.Description = value
+	}
+	if value, ok := tc.mutation.Owner(); ok {
+		_spec.SetField(target.FieldOwner, field.TypeString, value)
+		_node.Owner = value
+	}
+	if value, ok := tc.mutation.Private(); ok {
+		_spec.SetField(target.FieldPrivate, field.TypeBool, value)
+		_node.Private = value
+	}
+	if value, ok := tc.mutation.Profile(); ok {
+		_spec.SetField(target.FieldProfile, field.TypeJSON, value)
+		_node.Profile = value
+	}
+	if value, ok := tc.mutation.IndexUpdatedAt(); ok {
+		_spec.SetField(target.FieldIndexUpdatedAt, field.TypeTime, value)
+		_node.IndexUpdatedAt = value
+	}
 	if nodes := tc.mutation.PackagesIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -157,6 +285,22 @@ func (tc *TargetCreate) createSpec() (*Target, *sqlgraph.CreateSpec) {
 		}
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := tc.mutation.TokensIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   target.TokensTable,
+			Columns: target.TokensPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
@@ -16,6 +16,7 @@ import (
 	"github.com/jaredallard/binhost/internal/ent/pkg"
 	"github.com/jaredallard/binhost/internal/ent/predicate"
 	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/ent/token"
 )
 
 // TargetQuery is the builder for querying Target entities.
@@ -26,6 +27,7 @@ type TargetQuery struct {
 	inters       []Interceptor
 	predicates   []predicate.Target
 	withPackages *PkgQuery
+	withTokens   *TokenQuery
 	// intermediate query (i.e. traversal path).
 	sql  *sql.Selector
 	path func(context.Context) (*sql.Selector, error)
@@ -84,6 +86,28 @@ func (tq *TargetQuery) QueryPackages() *PkgQuery {
 	return query
 }
 
+// QueryTokens chains the current query on the "tokens" edge.
+func (tq *TargetQuery) QueryTokens() *TokenQuery {
+	query := (&TokenClient{config: tq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := tq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := tq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(target.Table, target.FieldID, selector),
+			sqlgraph.To(token.Table, token.FieldID),
+			sqlgraph.Edge(sqlgraph.M2M, true, target.TokensTable, target.TokensPrimaryKey...),
+		)
+		fromU = sqlgraph.SetNeighbors(tq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
 // First returns the first Target entity from the query.
 // Returns a *NotFoundError when no Target was found.
 func (tq *TargetQuery) First(ctx context.Context) (*Target, error) {
@@ -277,6 +301,7 @@ func (tq *TargetQuery) Clone() *TargetQuery {
 		inters:       append([]Interceptor{}, tq.inters...),
 		predicates:   append([]predicate.Target{}, tq.predicates...),
 		withPackages: tq.withPackages.Clone(),
+		withTokens:   tq.withTokens.Clone(),
 		// clone intermediate query.
 		sql:  tq.sql.Clone(),
 		path: tq.path,
@@ -294,6 +319,17 @@ func (tq *TargetQuery) WithPackages(opts ...func(*PkgQuery)) *TargetQuery {
 	return tq
 }
 
+// WithTokens tells the query-builder to eager-load the nodes that are connected to
+// the "tokens" edge. The optional arguments are used to configure the query builder of the edge.
+func (tq *TargetQuery) WithTokens(opts ...func(*TokenQuery)) *TargetQuery {
+	query := (&TokenClient{config: tq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	tq.withTokens = query
+	return tq
+}
+
 // GroupBy is used to group vertices by one or more fields/columns.
 // It is often used with aggregate functions, like: count, max, mean, min, sum.
 //
@@ -372,8 +408,9 @@ func (tq *TargetQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Targe
 	var (
 		nodes       = []*Target{}
 		_spec       = tq.querySpec()
-		loadedTypes = [1]bool{
+		loadedTypes = [2]bool{
 			tq.withPackages != nil,
+			tq.withTokens != nil,
 		}
 	)
 	_spec.ScanValues = func(columns []string) ([]any, error) {
@@ -401,6 +438,13 @@ func (tq *TargetQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Targe
 			return nil, err
 		}
 	}
+	if query := tq.withTokens; query != nil {
+		if err := tq.loadTokens(ctx, query, nodes,
+			func(n *Target) { n.Edges.Tokens = []*Token{} },
+			func(n *Target, e *Token) { n.Edges.Tokens = append(n.Edges.Tokens, e) }); err != nil {
+			return nil, err
+		}
+	}
 	return nodes, nil
 }
 
@@ -434,6 +478,67 @@ func (tq *TargetQuery) loadPackages(ctx context.Context, query *PkgQuery, nodes
 	}
 	return nil
 }
+func (tq *TargetQuery) loadTokens(ctx context.Context, query *TokenQuery, nodes []*Target, init func(*Target), assign func(*Target, *Token)) error {
+	edgeIDs := make([]driver.Value, len(nodes))
+	byID := make(map[uuid.UUID]*Target)
+	nids := make(map[uuid.UUID]map[*Target]struct{})
+	for i, node := range nodes {
+		edgeIDs[i] = node.ID
+		byID[node.ID] = node
+		if init != nil {
+			init(node)
+		}
+	}
+	query.Where(func(s *sql.Selector) {
+		joinT := sql.Table(target.TokensTable)
+		s.Join(joinT).On(s.C(token.FieldID), joinT.C(target.TokensPrimaryKey[0]))
+		s.Where(sql.InValues(joinT.C(target.TokensPrimaryKey[1]), edgeIDs...))
+		columns := s.SelectedColumns()
+		s.Select(joinT.C(target.TokensPrimaryKey[1]))
+		s.AppendSelect(columns...)
+		s.SetDistinct(false)
+	})
+	if err := query.prepareQuery(ctx); err != nil {
+		return err
+	}
+	qr := QuerierFunc(func(ctx context.Context, q Query) (Value, error) {
+		return query.sqlAll(ctx, func(_ context.Context, spec *sqlgraph.QuerySpec) {
+			assign := spec.Assign
+			values := spec.ScanValues
+			spec.ScanValues = func(columns []string) ([]any, error) {
+				values, err := values(columns[1:])
+				if err != nil {
+					return nil, err
+				}
+				return append([]any{new(uuid.UUID)}, values...), nil
+			}
+			spec.Assign = func(columns []string, values []any) error {
+				outValue := *values[0].(*uuid.UUID)
+				inValue := *values[1].(*uuid.UUID)
+				if nids[inValue] == nil {
+					nids[inValue] = map[*Target]struct{}{byID[outValue]: {}}
+					return assign(columns[1:], values[1:])
+				}
+				nids[inValue][byID[outValue]] = struct{}{}
+				return nil
+			}
+		})
+	})
+	neighbors, err := withInterceptors[[]*Token](ctx, query, qr, query.inters)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected "tokens" node returned %v`, n.ID)
+		}
+		for kn := range nodes {
+			assign(kn, n)
+		}
+	}
+	return nil
+}
 
 func (tq *TargetQuery) sqlCount(ctx context.Context) (int, error) {
 	_spec := tq.querySpec()
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
@@ -14,6 +15,8 @@ import (
 	"github.com/jaredallard/binhost/internal/ent/pkg"
 	"github.com/jaredallard/binhost/internal/ent/predicate"
 	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/ent/token"
+	"github.com/jaredallard/binhost/internal/parser"
 )
 
 // TargetUpdate is the builder for updating Target entities.
@@ -43,6 +46,74 @@ func (tu *TargetUpdate) SetNillableName(s *string) *TargetUpdate {
 	return tu
 }
 
+// SetDescription sets the "description" field.
+func (tu *TargetUpdate) SetDescription(s string) *TargetUpdate {
+	tu.mutation.SetDescription(s)
+	return tu
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (tu *TargetUpdate) SetNillableDescription(s *string) *TargetUpdate {
+	if s != nil {
+		tu.SetDescription(*s)
+	}
+	return tu
+}
+
+// SetOwner sets the "owner" field.
+func (tu *TargetUpdate) SetOwner(s string) *TargetUpdate {
+	tu.mutation.SetOwner(s)
+	return tu
+}
+
+// SetNillableOwner sets the "owner" field if the given value is not nil.
+func (tu *TargetUpdate) SetNillableOwner(s *string) *TargetUpdate {
+	if s != nil {
+		tu.SetOwner(*s)
+	}
+	return tu
+}
+
+// SetPrivate sets the "private" field.
+func (tu *TargetUpdate) SetPrivate(b bool) *TargetUpdate {
+	tu.mutation.SetPrivate(b)
+	return tu
+}
+
+// SetNillablePrivate sets the "private" field if the given value is not nil.
+func (tu *TargetUpdate) SetNillablePrivate(b *bool) *TargetUpdate {
+	if b != nil {
+		tu.SetPrivate(*b)
+	}
+	return tu
+}
+
+// SetProfile sets the "profile" field.
+func (tu *TargetUpdate) SetProfile(pa *parser.Profile) *TargetUpdate {
+	tu.mutation.SetProfile(pa)
+	return tu
+}
+
+// ClearProfile clears the value of the "profile" field.
+func (tu *TargetUpdate) ClearProfile() *TargetUpdate {
+	tu.mutation.ClearProfile()
+	return tu
+}
+
+// SetIndexUpdatedAt sets the "index_updated_at" field.
+func (tu *TargetUpdate) SetIndexUpdatedAt(t time.Time) *TargetUpdate {
+	tu.mutation.SetIndexUpdatedAt(t)
+	return tu
+}
+
+// SetNillableIndexUpdatedAt sets the "index_updated_at" field if the given value is not nil.
+func (tu *TargetUpdate) SetNillableIndexUpdatedAt(t *time.Time) *TargetUpdate {
+	if t != nil {
+		tu.SetIndexUpdatedAt(*t)
+	}
+	return tu
+}
+
 // AddPackageIDs adds the "packages" edge to the Pkg entity by IDs.
 func (tu *TargetUpdate) AddPackageIDs(ids ...uuid.UUID) *TargetUpdate {
 	tu.mutation.AddPackageIDs(ids...)
@@ -58,6 +129,21 @@ func (tu *TargetUpdate) AddPackages(p ...*Pkg) *TargetUpdate {
 	return tu.AddPackageIDs(ids...)
 }
 
+// AddTokenIDs adds the "tokens" edge to the Token entity by IDs.
+func (tu *TargetUpdate) AddTokenIDs(ids ...uuid.UUID) *TargetUpdate {
+	tu.mutation.AddTokenIDs(ids...)
+	return tu
+}
+
+// AddTokens adds the "tokens" edges to the Token entity.
+func (tu *TargetUpdate) AddTokens(t ...*Token) *TargetUpdate {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tu.AddTokenIDs(ids...)
+}
+
 // Mutation returns the TargetMutation object of the builder.
 func (tu *TargetUpdate) Mutation() *TargetMutation {
 	return tu.mutation
@@ -84,6 +170,27 @@ func (tu *TargetUpdate) RemovePackages(p ...*Pkg) *TargetUpdate {
 	return tu.RemovePackageIDs(ids...)
 }
 
+// ClearTokens clears all "tokens" edges to the Token entity.
+func (tu *TargetUpdate) ClearTokens() *TargetUpdate {
+	tu.mutation.ClearTokens()
+	return tu
+}
+
+// RemoveTokenIDs removes the "tokens" edge to Token entities by IDs.
+func (tu *TargetUpdate) RemoveTokenIDs(ids ...uuid.UUID) *TargetUpdate {
+	tu.mutation.RemoveTokenIDs(ids...)
+	return tu
+}
+
+// RemoveTokens removes "tokens" edges to Token entities.
+func (tu *TargetUpdate) RemoveTokens(t ...*Token) *TargetUpdate {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tu.RemoveTokenIDs(ids...)
+}
+
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (tu *TargetUpdate) Save(ctx context.Context) (int, error) {
 	return withHooks(ctx, tu.sqlSave, tu.mutation, tu.hooks)
@@ -123,6 +230,24 @@ func (tu *TargetUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := tu.mutation.Name(); ok {
 		_spec.SetField(target.FieldName, field.TypeString, value)
 	}
+	if value, ok := tu.mutation.Description(); ok {
+		_spec.SetField(target.FieldDescription, field.TypeString, value)
+	}
+	if value, ok := tu.mutation.Owner(); ok {
+		_spec.SetField(target.FieldOwner, field.TypeString, value)
+	}
+	if value, ok := tu.mutation.Private(); ok {
+		_spec.SetField(target.FieldPrivate, field.TypeBool, value)
+	}
+	if value, ok := tu.mutation.Profile(); ok {
+		_spec.SetField(target.FieldProfile, field.TypeJSON, value)
+	}
+	if tu.mutation.ProfileCleared() {
+		_spec.ClearField(target.FieldProfile, field.TypeJSON)
+	}
+	if value, ok := tu.mutation.IndexUpdatedAt(); ok {
+		_spec.SetField(target.FieldIndexUpdatedAt, field.TypeTime, value)
+	}
 	if tu.mutation.PackagesCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -168,6 +293,51 @@ func (tu *TargetUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	if tu.mutation.TokensCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   target.TokensTable,
+			Columns: target.TokensPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.RemovedTokensIDs(); len(nodes) > 0 && !tu.mutation.TokensCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   target.TokensTable,
+			Columns: target.TokensPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.TokensIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   target.TokensTable,
+			Columns: target.TokensPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
 	if n, err = sqlgraph.UpdateNodes(ctx, tu.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{target.Label}
@@ -202,6 +372,74 @@ func (tuo *TargetUpdateOne) SetNillableName(s *string) *TargetUpdateOne {
 	return tuo
 }
 
+// SetDescription sets the "description" field.
+func (tuo *TargetUpdateOne) SetDescription(s string) *TargetUpdateOne {
+	tuo.mutation.SetDescription(s)
+	return tuo
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (tuo *TargetUpdateOne) SetNillableDescription(s *string) *TargetUpdateOne {
+	if s != nil {
+		tuo.SetDescription(*s)
+	}
+	return tuo
+}
+
+// SetOwner sets the "owner" field.
+func (tuo *TargetUpdateOne) SetOwner(s string) *TargetUpdateOne {
+	tuo.mutation.SetOwner(s)
+	return tuo
+}
+
+// SetNillableOwner sets the "owner" field if the given value is not nil.
+func (tuo *TargetUpdateOne) SetNillableOwner(s *string) *TargetUpdateOne {
+	if s != nil {
+		tuo.SetOwner(*s)
+	}
+	return tuo
+}
+
+// SetPrivate sets the "private" field.
+func (tuo *TargetUpdateOne) SetPrivate(b bool) *TargetUpdateOne {
+	tuo.mutation.SetPrivate(b)
+	return tuo
+}
+
+// SetNillablePrivate sets the "private" field if the given value is not nil.
+func (tuo *TargetUpdateOne) SetNillablePrivate(b *bool) *TargetUpdateOne {
+	if b != nil {
+		tuo.SetPrivate(*b)
+	}
+	return tuo
+}
+
+// SetProfile sets the "profile" field.
+func (tuo *TargetUpdateOne) SetProfile(pa *parser.Profile) *TargetUpdateOne {
+	tuo.mutation.SetProfile(pa)
+	return tuo
+}
+
+// ClearProfile clears the value of the "profile" field.
+func (tuo *TargetUpdateOne) ClearProfile() *TargetUpdateOne {
+	tuo.mutation.ClearProfile()
+	return tuo
+}
+
+// SetIndexUpdatedAt sets the "index_updated_at" field.
+func (tuo *TargetUpdateOne) SetIndexUpdatedAt(t time.Time) *TargetUpdateOne {
+	tuo.mutation.SetIndexUpdatedAt(t)
+	return tuo
+}
+
+// SetNillableIndexUpdatedAt sets the "index_updated_at" field if the given value is not nil.
+func (tuo *TargetUpdateOne) SetNillableIndexUpdatedAt(t *time.Time) *TargetUpdateOne {
+	if t != nil {
+		tuo.SetIndexUpdatedAt(*t)
+	}
+	return tuo
+}
+
 // AddPackageIDs adds the "packages" edge to the Pkg entity by IDs.
 func (tuo *TargetUpdateOne) AddPackageIDs(ids ...uuid.UUID) *TargetUpdateOne {
 	tuo.mutation.AddPackageIDs(ids...)
@@ -217,6 +455,21 @@ func (tuo *TargetUpdateOne) AddPackages(p ...*Pkg) *TargetUpdateOne {
 	return tuo.AddPackageIDs(ids...)
 }
 
+// AddTokenIDs adds the "tokens" edge to the Token entity by IDs.
+func (tuo *TargetUpdateOne) AddTokenIDs(ids ...uuid.UUID) *TargetUpdateOne {
+	tuo.mutation.AddTokenIDs(ids...)
+	return tuo
+}
+
+// AddTokens adds the "tokens" edges to the Token entity.
+func (tuo *TargetUpdateOne) AddTokens(t ...*Token) *TargetUpdateOne {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tuo.AddTokenIDs(ids...)
+}
+
 // Mutation returns the TargetMutation object of the builder.
 func (tuo *TargetUpdateOne) Mutation() *TargetMutation {
 	return tuo.mutation
@@ -243,6 +496,27 @@ func (tuo *TargetUpdateOne) RemovePackages(p ...*Pkg) *TargetUpdateOne {
 	return tuo.RemovePackageIDs(ids...)
 }
 
+// ClearTokens clears all "tokens" edges to the Token entity.
+func (tuo *TargetUpdateOne) ClearTokens() *TargetUpdateOne {
+	tuo.mutation.ClearTokens()
+	return tuo
+}
+
+// RemoveTokenIDs removes the "tokens" edge to Token entities by IDs.
+func (tuo *TargetUpdateOne) RemoveTokenIDs(ids ...uuid.UUID) *TargetUpdateOne {
+	tuo.mutation.RemoveTokenIDs(ids...)
+	return tuo
+}
+
+// RemoveTokens removes "tokens" edges to Token entities.
+func (tuo *TargetUpdateOne) RemoveTokens(t ...*Token) *TargetUpdateOne {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tuo.RemoveTokenIDs(ids...)
+}
+
 // Where appends a list predicates to the TargetUpdate builder.
 func (tuo *TargetUpdateOne) Where(ps ...predicate.Target) *TargetUpdateOne {
 	tuo.mutation.Where(ps...)
@@ -312,6 +586,24 @@ func (tuo *TargetUpdateOne) sqlSave(ctx context.Context) (_node *Target, err err
 	if value, ok := tuo.mutation.Name(); ok {
 		_spec.SetField(target.FieldName, field.TypeString, value)
 	}
+	if value, ok := tuo.mutation.Description(); ok {
+		_spec.SetField(target.FieldDescription, field.TypeString, value)
+	}
+	if value, ok := tuo.mutation.Owner(); ok {
+		_spec.SetField(target.FieldOwner, field.TypeString, value)
+	}
+	if value, ok := tuo.mutation.Private(); ok {
+		_spec.SetField(target.FieldPrivate, field.TypeBool, value)
+	}
+	if value, ok := tuo.mutation.Profile(); ok {
+		_spec.SetField(target.FieldProfile, field.TypeJSON, value)
+	}
+	if tuo.mutation.ProfileCleared() {
+		_spec.ClearField(target.FieldProfile, field.TypeJSON)
+	}
+	if value, ok := tuo.mutation.IndexUpdatedAt(); ok {
+		_spec.SetField(target.FieldIndexUpdatedAt, field.TypeTime, value)
+	}
 	if tuo.mutation.PackagesCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -357,6 +649,51 @@ func (tuo *TargetUpdateOne) sqlSave(ctx context.Context) (_node *Target, err err
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	if tuo.mutation.TokensCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   target.TokensTable,
+			Columns: target.TokensPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.RemovedTokensIDs(); len(nodes) > 0 && !tuo.mutation.TokensCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   target.TokensTable,
+			Columns: target.TokensPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.TokensIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   target.TokensTable,
+			Columns: target.TokensPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
 	_node = &Target{config: tuo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
@@ -0,0 +1,315 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/ent/token"
+)
+
+// TokenCreate is the builder for creating a Token entity.
+type TokenCreate struct {
+	config
+	mutation *TokenMutation
+	hooks    []Hook
+}
+
+// SetName sets the "name" field.
+func (tc *TokenCreate) SetName(s string) *TokenCreate {
+	tc.mutation.SetName(s)
+	return tc
+}
+
+// SetHash sets the "hash" field.
+func (tc *TokenCreate) SetHash(s string) *TokenCreate {
+	tc.mutation.SetHash(s)
+	return tc
+}
+
+// SetScopes sets the "scopes" field.
+func (tc *TokenCreate) SetScopes(s []string) *TokenCreate {
+	tc.mutation.SetScopes(s)
+	return tc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (tc *TokenCreate) SetCreatedAt(t time.Time) *TokenCreate {
+	tc.mutation.SetCreatedAt(t)
+	return tc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (tc *TokenCreate) SetNillableCreatedAt(t *time.Time) *TokenCreate {
+	if t != nil {
+		tc.SetCreatedAt(*t)
+	}
+	return tc
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (tc *TokenCreate) SetExpiresAt(t time.Time) *TokenCreate {
+	tc.mutation.SetExpiresAt(t)
+	return tc
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (tc *TokenCreate) SetNillableExpiresAt(t *time.Time) *TokenCreate {
+	if t != nil {
+		tc.SetExpiresAt(*t)
+	}
+	return tc
+}
+
+// SetID sets the "id" field.
+func (tc *TokenCreate) SetID(u uuid.UUID) *TokenCreate {
+	tc.mutation.SetID(u)
+	return tc
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (tc *TokenCreate) SetNillableID(u *uuid.UUID) *TokenCreate {
+	if u != nil {
+		tc.SetID(*u)
+	}
+	return tc
+}
+
+// AddTargetIDs adds the "targets" edge to the Target entity by IDs.
+func (tc *TokenCreate) AddTargetIDs(ids ...uuid.UUID) *TokenCreate {
+	tc.mutation.AddTargetIDs(ids...)
+	return tc
+}
+
+// AddTargets adds the "targets" edges to the Target entity.
+func (tc *TokenCreate) AddTargets(t ...*Target) *TokenCreate {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tc.AddTargetIDs(ids...)
+}
+
+// Mutation returns the TokenMutation object of the builder.
+func (tc *TokenCreate) Mutation() *TokenMutation {
+	return tc.mutation
+}
+
+// Save creates the Token in the database.
+func (tc *TokenCreate) Save(ctx context.Context) (*Token, error) {
+	tc.defaults()
+	return withHooks(ctx, tc.sqlSave, tc.mutation, tc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (tc *TokenCreate) SaveX(ctx context.Context) *Token {
+	v, err := tc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (tc *TokenCreate) Exec(ctx context.Context) error {
+	_, err := tc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (tc *TokenCreate) ExecX(ctx context.Context) {
+	if err := tc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (tc *TokenCreate) defaults() {
+	if _, ok := tc.mutation.CreatedAt(); !ok {
+		v := token.DefaultCreatedAt()
+		tc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := tc.mutation.ID(); !ok {
+		v := token.DefaultID()
+		tc.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (tc *TokenCreate) check() error {
+	if _, ok := tc.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "Token.name"`)}
+	}
+	if _, ok := tc.mutation.Hash(); !ok {
+		return &ValidationError{Name: "hash", err: errors.New(`ent: missing required field "Token.hash"`)}
+	}
+	if _, ok := tc.mutation.Scopes(); !ok {
+		return &ValidationError{Name: "scopes", err: errors.New(`ent: missing required field "Token.scopes"`)}
+	}
+	if _, ok := tc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Token.created_at"`)}
+	}
+	return nil
+}
+
+func (tc *TokenCreate) sqlSave(ctx context.Context) (*Token, error) {
+	if err := tc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := tc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, tc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(*uuid.UUID); ok {
+			_node.ID = *id
+		} else if err := _node.ID.Scan(_spec.ID.Value); err != nil {
+			return nil, err
+		}
+	}
+	tc.mutation.id = &_node.ID
+	tc.mutation.done = true
+	return _node, nil
+}
+
+func (tc *TokenCreate) createSpec() (*Token, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Token{config: tc.config}
+		_spec = sqlgraph.NewCreateSpec(token.Table, sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID))
+	)
+	if id, ok := tc.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = &id
+	}
+	if value, ok := tc.mutation.Name(); ok {
+		_spec.SetField(token.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := tc.mutation.Hash(); ok {
+		_spec.SetField(token.FieldHash, field.TypeString, value)
+		_node.Hash = value
+	}
+	if value, ok := tc.mutation.Scopes(); ok {
+		_spec.SetField(token.FieldScopes, field.TypeJSON, value)
+		_node.Scopes = value
+	}
+	if value, ok := tc.mutation.CreatedAt(); ok {
+		_spec.SetField(token.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := tc.mutation.ExpiresAt(); ok {
+		_spec.SetField(token.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = &value
+	}
+	if nodes := tc.mutation.TargetsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   token.TargetsTable,
+			Columns: token.TargetsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(target.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// TokenCreateBulk is the builder for creating many Token entities in bulk.
+type TokenCreateBulk struct {
+	config
+	err      error
+	builders []*TokenCreate
+}
+
+// Save creates the Token entities in the database.
+func (tcb *TokenCreateBulk) Save(ctx context.Context) ([]*Token, error) {
+	if tcb.err != nil {
+		return nil, tcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(tcb.builders))
+	nodes := make([]*Token, len(tcb.builders))
+	mutators := make([]Mutator, len(tcb.builders))
+	for i := range tcb.builders {
+		func(i int, root context.Context) {
+			builder := tcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*TokenMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, tcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, tcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, tcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (tcb *TokenCreateBulk) SaveX(ctx context.Context) []*Token {
+	v, err := tcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (tcb *TokenCreateBulk) Exec(ctx context.Context) error {
+	_, err := tcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (tcb *TokenCreateBulk) ExecX(ctx context.Context) {
+	if err := tcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,501 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/ent/predicate"
+	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/ent/token"
+)
+
+// TokenUpdate is the builder for updating Token entities.
+type TokenUpdate struct {
+	config
+	hooks    []Hook
+	mutation *TokenMutation
+}
+
+// Where appends a list predicates to the TokenUpdate builder.
+func (tu *TokenUpdate) Where(ps ...predicate.Token) *TokenUpdate {
+	tu.mutation.Where(ps...)
+	return tu
+}
+
+// SetName sets the "name" field.
+func (tu *TokenUpdate) SetName(s string) *TokenUpdate {
+	tu.mutation.SetName(s)
+	return tu
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (tu *TokenUpdate) SetNillableName(s *string) *TokenUpdate {
+	if s != nil {
+		tu.SetName(*s)
+	}
+	return tu
+}
+
+// SetHash sets the "hash" field.
+func (tu *TokenUpdate) SetHash(s string) *TokenUpdate {
+	tu.mutation.SetHash(s)
+	return tu
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (tu *TokenUpdate) SetNillableHash(s *string) *TokenUpdate {
+	if s != nil {
+		tu.SetHash(*s)
+	}
+	return tu
+}
+
+// SetScopes sets the "scopes" field.
+func (tu *TokenUpdate) SetScopes(s []string) *TokenUpdate {
+	tu.mutation.SetScopes(s)
+	return tu
+}
+
+// AppendScopes appends s to the "scopes" field.
+func (tu *TokenUpdate) AppendScopes(s []string) *TokenUpdate {
+	tu.mutation.AppendScopes(s)
+	return tu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (tu *TokenUpdate) SetExpiresAt(t time.Time) *TokenUpdate {
+	tu.mutation.SetExpiresAt(t)
+	return tu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (tu *TokenUpdate) SetNillableExpiresAt(t *time.Time) *TokenUpdate {
+	if t != nil {
+		tu.SetExpiresAt(*t)
+	}
+	return tu
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (tu *TokenUpdate) ClearExpiresAt() *TokenUpdate {
+	tu.mutation.ClearExpiresAt()
+	return tu
+}
+
+// AddTargetIDs adds the "targets" edge to the Target entity by IDs.
+func (tu *TokenUpdate) AddTargetIDs(ids ...uuid.UUID) *TokenUpdate {
+	tu.mutation.AddTargetIDs(ids...)
+	return tu
+}
+
+// AddTargets adds the "targets" edges to the Target entity.
+func (tu *TokenUpdate) AddTargets(t ...*Target) *TokenUpdate {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tu.AddTargetIDs(ids...)
+}
+
+// Mutation returns the TokenMutation object of the builder.
+func (tu *TokenUpdate) Mutation() *TokenMutation {
+	return tu.mutation
+}
+
+// ClearTargets clears all "targets" edges to the Target entity.
+func (tu *TokenUpdate) ClearTargets() *TokenUpdate {
+	tu.mutation.ClearTargets()
+	return tu
+}
+
+// RemoveTargetIDs removes the "targets" edge to Target entities by IDs.
+func (tu *TokenUpdate) RemoveTargetIDs(ids ...uuid.UUID) *TokenUpdate {
+	tu.mutation.RemoveTargetIDs(ids...)
+	return tu
+}
+
+// RemoveTargets removes "targets" edges to Target entities.
+func (tu *TokenUpdate) RemoveTargets(t ...*Target) *TokenUpdate {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tu.RemoveTargetIDs(ids...)
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (tu *TokenUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, tu.sqlSave, tu.mutation, tu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (tu *TokenUpdate) SaveX(ctx context.Context) int {
+	affected, err := tu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (tu *TokenUpdate) Exec(ctx context.Context) error {
+	_, err := tu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (tu *TokenUpdate) ExecX(ctx context.Context) {
+	if err := tu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (tu *TokenUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(token.Table, token.Columns, sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID))
+	if ps := tu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := tu.mutation.Name(); ok {
+		_spec.SetField(token.FieldName, field.TypeString, value)
+	}
+	if value, ok := tu.mutation.Hash(); ok {
+		_spec.SetField(token.FieldHash, field.TypeString, value)
+	}
+	if value, ok := tu.mutation.Scopes(); ok {
+		_spec.SetField(token.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := tu.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, token.FieldScopes, value)
+		})
+	}
+	if value, ok := tu.mutation.ExpiresAt(); ok {
+		_spec.SetField(token.FieldExpiresAt, field.TypeTime, value)
+	}
+	if tu.mutation.ExpiresAtCleared() {
+		_spec.ClearField(token.FieldExpiresAt, field.TypeTime)
+	}
+	if tu.mutation.TargetsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   token.TargetsTable,
+			Columns: token.TargetsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(target.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.RemovedTargetsIDs(); len(nodes) > 0 && !tu.mutation.TargetsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   token.TargetsTable,
+			Columns: token.TargetsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(target.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.TargetsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   token.TargetsTable,
+			Columns: token.TargetsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(target.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, tu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{token.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	tu.mutation.done = true
+	return n, nil
+}
+
+// TokenUpdateOne is the builder for updating a single Token entity.
+type TokenUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *TokenMutation
+}
+
+// SetName sets the "name" field.
+func (tuo *TokenUpdateOne) SetName(s string) *TokenUpdateOne {
+	tuo.mutation.SetName(s)
+	return tuo
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (tuo *TokenUpdateOne) SetNillableName(s *string) *TokenUpdateOne {
+	if s != nil {
+		tuo.SetName(*s)
+	}
+	return tuo
+}
+
+// SetHash sets the "hash" field.
+func (tuo *TokenUpdateOne) SetHash(s string) *TokenUpdateOne {
+	tuo.mutation.SetHash(s)
+	return tuo
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (tuo *TokenUpdateOne) SetNillableHash(s *string) *TokenUpdateOne {
+	if s != nil {
+		tuo.SetHash(*s)
+	}
+	return tuo
+}
+
+// SetScopes sets the "scopes" field.
+func (tuo *TokenUpdateOne) SetScopes(s []string) *TokenUpdateOne {
+	tuo.mutation.SetScopes(s)
+	return tuo
+}
+
+// AppendScopes appends s to the "scopes" field.
+func (tuo *TokenUpdateOne) AppendScopes(s []string) *TokenUpdateOne {
+	tuo.mutation.AppendScopes(s)
+	return tuo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (tuo *TokenUpdateOne) SetExpiresAt(t time.Time) *TokenUpdateOne {
+	tuo.mutation.SetExpiresAt(t)
+	return tuo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (tuo *TokenUpdateOne) SetNillableExpiresAt(t *time.Time) *TokenUpdateOne {
+	if t != nil {
+		tuo.SetExpiresAt(*t)
+	}
+	return tuo
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (tuo *TokenUpdateOne) ClearExpiresAt() *TokenUpdateOne {
+	tuo.mutation.ClearExpiresAt()
+	return tuo
+}
+
+// AddTargetIDs adds the "targets" edge to the Target entity by IDs.
+func (tuo *TokenUpdateOne) AddTargetIDs(ids ...uuid.UUID) *TokenUpdateOne {
+	tuo.mutation.AddTargetIDs(ids...)
+	return tuo
+}
+
+// AddTargets adds the "targets" edges to the Target entity.
+func (tuo *TokenUpdateOne) AddTargets(t ...*Target) *TokenUpdateOne {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tuo.AddTargetIDs(ids...)
+}
+
+// Mutation returns the TokenMutation object of the builder.
+func (tuo *TokenUpdateOne) Mutation() *TokenMutation {
+	return tuo.mutation
+}
+
+// ClearTargets clears all "targets" edges to the Target entity.
+func (tuo *TokenUpdateOne) ClearTargets() *TokenUpdateOne {
+	tuo.mutation.ClearTargets()
+	return tuo
+}
+
+// RemoveTargetIDs removes the "targets" edge to Target entities by IDs.
+func (tuo *TokenUpdateOne) RemoveTargetIDs(ids ...uuid.UUID) *TokenUpdateOne {
+	tuo.mutation.RemoveTargetIDs(ids...)
+	return tuo
+}
+
+// RemoveTargets removes "targets" edges to Target entities.
+func (tuo *TokenUpdateOne) RemoveTargets(t ...*Target) *TokenUpdateOne {
+	ids := make([]uuid.UUID, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tuo.RemoveTargetIDs(ids...)
+}
+
+// Where appends a list predicates to the TokenUpdate builder.
+func (tuo *TokenUpdateOne) Where(ps ...predicate.Token) *TokenUpdateOne {
+	tuo.mutation.Where(ps...)
+	return tuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (tuo *TokenUpdateOne) Select(field string, fields ...string) *TokenUpdateOne {
+	tuo.fields = append([]string{field}, fields...)
+	return tuo
+}
+
+// Save executes the query and returns the updated Token entity.
+func (tuo *TokenUpdateOne) Save(ctx context.Context) (*Token, error) {
+	return withHooks(ctx, tuo.sqlSave, tuo.mutation, tuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (tuo *TokenUpdateOne) SaveX(ctx context.Context) *Token {
+	node, err := tuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (tuo *TokenUpdateOne) Exec(ctx context.Context) error {
+	_, err := tuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (tuo *TokenUpdateOne) ExecX(ctx context.Context) {
+	if err := tuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (tuo *TokenUpdateOne) sqlSave(ctx context.Context) (_node *Token, err error) {
+	_spec := sqlgraph.NewUpdateSpec(token.Table, token.Columns, sqlgraph.NewFieldSpec(token.FieldID, field.TypeUUID))
+	id, ok := tuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Token.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := tuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, token.FieldID)
+		for _, f := range fields {
+			if !token.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != token.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := tuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := tuo.mutation.Name(); ok {
+		_spec.SetField(token.FieldName, field.TypeString, value)
+	}
+	if value, ok := tuo.mutation.Hash(); ok {
+		_spec.SetField(token.FieldHash, field.TypeString, value)
+	}
+	if value, ok := tuo.mutation.Scopes(); ok {
+		_spec.SetField(token.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := tuo.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, token.FieldScopes, value)
+		})
+	}
+	if value, ok := tuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(token.FieldExpiresAt, field.TypeTime, value)
+	}
+	if tuo.mutation.ExpiresAtCleared() {
+		_spec.ClearField(token.FieldExpiresAt, field.TypeTime)
+	}
+	if tuo.mutation.TargetsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   token.TargetsTable,
+			Columns: token.TargetsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(target.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.RemovedTargetsIDs(); len(nodes) > 0 && !tuo.mutation.TargetsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   token.TargetsTable,
+			Columns: token.TargetsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(target.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.TargetsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   token.TargetsTable,
+			Columns: token.TargetsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(target.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &Token{config: tuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, tuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{token.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	tuo.mutation.done = true
+	return _node, nil
+}
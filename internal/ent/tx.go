@@ -16,6 +16,8 @@ type Tx struct {
 	Pkg *PkgClient
 	// Target is the client for interacting with the Target builders.
 	Target *TargetClient
+	// Token is the client for interacting with the Token builders.
+	Token *TokenClient
 
 	// lazily loaded.
 	client     *Client
@@ -149,6 +151,7 @@ func (tx *Tx) Client() *Client {
 func (tx *Tx) init() {
 	tx.Pkg = NewPkgClient(tx.config)
 	tx.Target = NewTargetClient(tx.config)
+	tx.Token = NewTokenClient(tx.config)
 }
 
 // txDriver wraps the given dialect.Tx with a nop dialect.Driver implementation.
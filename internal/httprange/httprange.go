@@ -0,0 +1,127 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package httprange implements parsing of HTTP Range request headers.
+//
+// See: https://www.rfc-editor.org/rfc/rfc9110#section-14
+package httprange
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxRanges is the maximum number of ranges accepted in a single
+// header. Headers with more ranges are ignored to prevent clients from
+// requesting many small (or overlapping) ranges.
+const MaxRanges = 32
+
+// ErrUnsatisfiable is returned by Parse when none of the requested
+// ranges overlap with the representation.
+var ErrUnsatisfiable = errors.New("range not satisfiable")
+
+// Range is a range of bytes of a representation.
+type Range struct {
+	// Start is the offset of the first byte of the range.
+	Start int64
+
+	// Length is the number of bytes in the range.
+	Length int64
+}
+
+// ContentRange returns the value of the Content-Range header for the
+// range of a representation of size bytes.
+func (r Range) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.Start+r.Length-1, size)
+}
+
+// Parse parses the value of a Range header for a representation of
+// size bytes. Ranges that extend past the end of the representation
+// are shortened to fit it.
+//
+// nil is returned, and the header should be ignored, when the header
+// is empty, malformed, uses a unit other than bytes or contains more
+// than MaxRanges ranges. ErrUnsatisfiable is returned when none of the
+// ranges can be satisfied.
+func Parse(header string, size int64) ([]Range, error) {
+	specs, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return nil, nil
+	}
+
+	parts := strings.Split(specs, ",")
+	if len(parts) > MaxRanges {
+		return nil, nil
+	}
+
+	ranges := make([]Range, 0, len(parts))
+	for _, part := range parts {
+		first, last, ok := strings.Cut(strings.TrimSpace(part), "-")
+		if !ok {
+			return nil, nil
+		}
+
+		if first == "" {
+			// Suffix range, e.g., -500 for the last 500 bytes.
+			n, err := parseInt(last)
+			if err != nil {
+				return nil, nil
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+
+			n = min(n, size)
+			ranges = append(ranges, Range{Start: size - n, Length: n})
+			continue
+		}
+
+		start, err := parseInt(first)
+		if err != nil {
+			return nil, nil
+		}
+
+		end := size - 1
+		if last != "" {
+			if end, err = parseInt(last); err != nil || end < start {
+				return nil, nil
+			}
+			end = min(end, size-1)
+		}
+
+		if start >= size {
+			continue
+		}
+
+		ranges = append(ranges, Range{Start: start, Length: end - start + 1})
+	}
+
+	if len(ranges) == 0 {
+		return nil, ErrUnsatisfiable
+	}
+
+	return ranges, nil
+}
+
+// parseInt parses a non-negative decimal integer.
+func parseInt(s string) (int64, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid integer %q", s)
+	}
+
+	return strconv.ParseInt(s, 10, 64)
+}
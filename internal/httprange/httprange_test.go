@@ -0,0 +1,51 @@
+package httprange_test
+
+import (
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/httprange"
+	"gotest.tools/v3/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    []httprange.Range
+		wantErr error
+	}{
+		{header: "", want: nil},
+		{header: "bytes=0-99", want: []httprange.Range{{Start: 0, Length: 100}}},
+		{header: "bytes=900-", want: []httprange.Range{{Start: 900, Length: 100}}},
+		{header: "bytes=-100", want: []httprange.Range{{Start: 900, Length: 100}}},
+		{header: "bytes=-5000", want: []httprange.Range{{Start: 0, Length: 1000}}},
+		{header: "bytes=990-2000", want: []httprange.Range{{Start: 990, Length: 10}}},
+		{header: "bytes=0-0, 10-19,-1", want: []httprange.Range{
+			{Start: 0, Length: 1}, {Start: 10, Length: 10}, {Start: 999, Length: 1},
+		}},
+		{header: "bytes=0-0,1000-1100", want: []httprange.Range{{Start: 0, Length: 1}}},
+		{header: "bytes=1000-", wantErr: httprange.ErrUnsatisfiable},
+		{header: "bytes=-0", wantErr: httprange.ErrUnsatisfiable},
+		{header: "items=0-10", want: nil},
+		{header: "bytes=10-5", want: nil},
+		{header: "bytes=abc", want: nil},
+		{header: "bytes=+1-5", want: nil},
+		{header: "bytes=0-1,2-3,4-5,6-7,8-9,0-1,2-3,4-5,6-7,8-9,0-1,2-3,4-5,6-7,8-9,0-1," +
+			"2-3,4-5,6-7,8-9,0-1,2-3,4-5,6-7,8-9,0-1,2-3,4-5,6-7,8-9,0-1,2-3,4-5", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, err := httprange.Parse(tt.header, 1000)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.want, got)
+		})
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	assert.Equal(t, "bytes 10-19/1000", httprange.Range{Start: 10, Length: 10}.ContentRange(1000))
+}
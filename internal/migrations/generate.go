@@ -0,0 +1,241 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build ignore
+
+// generate creates a new versioned migration from the changes between
+// the ent schema and the schema the existing migrations create, which
+// is tracked in schema.hcl. No database is required.
+//
+// Usage: go run ./internal/migrations/generate.go <name>
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	entschema "entgo.io/ent/dialect/sql/schema"
+	entmigrate "github.com/jaredallard/binhost/internal/ent/migrate"
+)
+
+const (
+	// dir is the directory containing the migrations and schema.hcl.
+	dir = "internal/migrations"
+
+	// schemaName is the name of the schema the tables are created in.
+	schemaName = "public"
+)
+
+// versionRe matches the version of a migration file.
+var versionRe = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+
+func main() {
+	if len(os.Args) != 2 || !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(os.Args[1]) {
+		log.Fatal("usage: go run ./internal/migrations/generate.go <name>, e.g., add_target_owner")
+	}
+
+	if err := generate(context.Background(), os.Args[1]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate writes the migration with the provided name.
+func generate(ctx context.Context, name string) error {
+	current, err := currentRealm()
+	if err != nil {
+		return err
+	}
+
+	desired, err := desiredRealm(ctx)
+	if err != nil {
+		return err
+	}
+
+	up, err := plan(ctx, current, desired)
+	if err != nil {
+		return err
+	}
+	if up == "" {
+		fmt.Println("no changes")
+		return nil
+	}
+
+	down, err := plan(ctx, desired, current)
+	if err != nil {
+		return err
+	}
+
+	version, err := nextVersion()
+	if err != nil {
+		return err
+	}
+
+	base := filepath.Join(dir, "sql", fmt.Sprintf("%04d_%s", version, name))
+	if err := os.WriteFile(base+".up.sql", []byte(up), 0o644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".down.sql", []byte(down), 0o644); err != nil {
+		return err
+	}
+
+	b, err := postgres.MarshalHCL(desired.Schemas[0])
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.hcl"), b, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Println("created", base+".up.sql")
+	return nil
+}
+
+// currentRealm returns the schema created by the existing migrations.
+func currentRealm() (*schema.Realm, error) {
+	realm := schema.NewRealm()
+
+	b, err := os.ReadFile(filepath.Join(dir, "schema.hcl"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return realm.AddSchemas(schema.New(schemaName)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := postgres.EvalHCLBytes(b, realm, nil); err != nil {
+		return nil, fmt.Errorf("failed to read schema.hcl: %w", err)
+	}
+	return realm, nil
+}
+
+// desiredRealm returns the schema described by the ent schema.
+func desiredRealm(ctx context.Context) (*schema.Realm, error) {
+	m, err := entschema.NewMigrate(versionDriver{})
+	if err != nil {
+		return nil, err
+	}
+
+	realm, err := m.StateReader(entmigrate.Tables...).ReadState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ent schema: %w", err)
+	}
+	for _, s := range realm.Schemas {
+		s.Name = schemaName
+	}
+	return realm, nil
+}
+
+// plan returns the SQL statements that change the schema from one
+// realm to another.
+func plan(ctx context.Context, from, to *schema.Realm) (string, error) {
+	changes, err := postgres.DefaultDiff.RealmDiff(from, to)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff schemas: %w", err)
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	p, err := postgres.DefaultPlan.PlanChanges(ctx, "", changes, func(o *migrate.PlanOptions) {
+		o.SchemaQualifier = new(string)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to plan changes: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, c := range p.Changes {
+		if c.Comment != "" {
+			fmt.Fprintf(&sb, "-- %s\n", strings.ToUpper(c.Comment[:1])+c.Comment[1:])
+		}
+		fmt.Fprintf(&sb, "%s;\n", c.Cmd)
+	}
+	return sb.String(), nil
+}
+
+// nextVersion returns the version of the next migration.
+func nextVersion() (int, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, "sql"))
+	if err != nil {
+		return 0, err
+	}
+
+	latest := 0
+	for _, e := range entries {
+		if m := versionRe.FindStringSubmatch(e.Name()); m != nil {
+			v, err := strconv.Atoi(m[1])
+			if err != nil {
+				return 0, err
+			}
+			latest = max(latest, v)
+		}
+	}
+	return latest + 1, nil
+}
+
+// versionDriver is an ent driver that only answers the server version
+// query ent makes before converting its schema.
+type versionDriver struct {
+	dialect.Driver
+}
+
+func (versionDriver) Dialect() string { return dialect.Postgres }
+
+func (versionDriver) Query(_ context.Context, query string, _, v any) error {
+	if query != "SHOW server_version_num" {
+		return fmt.Errorf("unexpected query: %s", query)
+	}
+
+	rows, ok := v.(*entsql.Rows)
+	if !ok {
+		return fmt.Errorf("unexpected rows type %T", v)
+	}
+	*rows = entsql.Rows{ColumnScanner: &versionRows{}}
+	return nil
+}
+
+// versionRows contains a single row with a PostgreSQL 15 server
+// version.
+type versionRows struct {
+	entsql.ColumnScanner
+	read bool
+}
+
+func (r *versionRows) Next() bool {
+	next := !r.read
+	r.read = true
+	return next
+}
+
+func (r *versionRows) Scan(dest ...any) error {
+	*(dest[0].(*string)) = "150000"
+	return nil
+}
+
+func (r *versionRows) Err() error   { return nil }
+func (r *versionRows) Close() error { return nil }
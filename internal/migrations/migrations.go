@@ -0,0 +1,392 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package migrations contains the versioned migrations of the binhost
+// database and applies them.
+//
+// Migrations are generated from the ent schema by running:
+//
+//	go run ./internal/migrations/generate.go <name>
+//
+// Each migration runs in its own transaction, together with the update
+// of the version stored in the schema_migrations table, so a failed
+// migration leaves the database at the previous version.
+//
+// Migrations that delete data refuse to run unless the deletion was
+// confirmed using Migrator.ConfirmDataLoss. They check the
+// binhost.confirm_data_loss setting and raise an error with the
+// SQLSTATE dataLossCode if it isn't set:
+//
+//	IF current_setting('binhost.confirm_data_loss', true) IS DISTINCT FROM 'on' THEN
+//		RAISE EXCEPTION USING ERRCODE = 'BH001', MESSAGE = '...';
+//	END IF;
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// files contains the migrations, named <version>_<name>.up.sql and
+// <version>_<name>.down.sql.
+//
+//go:embed sql/*.sql
+var files embed.FS
+
+// fileRe matches the name of a migration file.
+var fileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// lockID is the ID of the advisory lock held while migrating, so that
+// concurrent migrations don't step on each other.
+const lockID = 0x62696e686f7374 // "binhost"
+
+// baseline is the version of the first migration, which is the schema
+// created automatically by releases before versioned migrations.
+// Databases created by those releases have no version recorded and
+// are adopted at this version.
+const baseline = 1
+
+// dataLossCode is the SQLSTATE raised by migrations that would delete
+// data without confirmation.
+const dataLossCode = "BH001"
+
+// ErrVersionMismatch is returned by Check when the database isn't at
+// the latest version.
+var ErrVersionMismatch = errors.New("database is not at the expected version")
+
+// ErrDataLoss is returned by Up when a migration would delete data and
+// ConfirmDataLoss wasn't called.
+var ErrDataLoss = errors.New("migration would delete data")
+
+// Migration is a versioned migration.
+type Migration struct {
+	Version int
+	Name    string
+
+	// Up and Down are the SQL statements that apply and revert the
+	// migration.
+	Up   string
+	Down string
+}
+
+// All returns all migrations ordered by version.
+func All() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		m := fileRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", e.Name())
+		}
+		version, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %q: %w", e.Name(), err)
+		}
+
+		b, err := fs.ReadFile(files, "sql/"+e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has multiple names: %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(b)
+		} else {
+			mig.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d is missing its up or down file", mig.Version)
+		}
+		migrations = append(migrations, *mig)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+	return migrations, nil
+}
+
+// Migrator applies migrations to a database. Create using the New()
+// function.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+
+	// confirmDataLoss is true if migrations may delete data.
+	confirmDataLoss bool
+}
+
+// New creates a new Migrator for the provided PostgreSQL database.
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// ConfirmDataLoss allows migrations to delete data, which they
+// otherwise refuse to do by returning ErrDataLoss.
+func (m *Migrator) ConfirmDataLoss() {
+	m.confirmDataLoss = true
+}
+
+// Migrations returns all migrations ordered by version.
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Latest returns the version of the latest migration.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the version of the database, 0 if no migrations
+// have been applied. Databases created by releases before versioned
+// migrations are at the version of the first migration.
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	exists, err := tableExists(ctx, m.db, "schema_migrations")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query schema version: %w", err)
+	}
+	if !exists {
+		return unversioned(ctx, m.db)
+	}
+
+	return version(ctx, m.db)
+}
+
+// Check returns an error wrapping ErrVersionMismatch if the database
+// isn't at the latest version.
+func (m *Migrator) Check(ctx context.Context) error {
+	v, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if v != m.Latest() {
+		return fmt.Errorf("%w: database is at version %d, expected %d", ErrVersionMismatch, v, m.Latest())
+	}
+
+	return nil
+}
+
+// Up applies the next n pending migrations, or all of them if n is
+// not positive, returning the migrations that were applied.
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var applied []Migration
+	for n <= 0 || len(applied) < n {
+		mig, err := m.step(ctx, func(v int) (*Migration, int, error) {
+			i, err := m.index(v)
+			if err != nil || i+1 == len(m.migrations) {
+				return nil, 0, err
+			}
+
+			next := &m.migrations[i+1]
+			return next, next.Version, nil
+		}, func(mig *Migration) string { return mig.Up })
+		if err != nil || mig == nil {
+			return applied, err
+		}
+		applied = append(applied, *mig)
+	}
+
+	return applied, nil
+}
+
+// Down reverts the last n applied migrations, returning the migrations
+// that were reverted.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	var reverted []Migration
+	for len(reverted) < n {
+		mig, err := m.step(ctx, func(v int) (*Migration, int, error) {
+			i, err := m.index(v)
+			if err != nil || i == -1 {
+				return nil, 0, err
+			}
+
+			prev := 0
+			if i > 0 {
+				prev = m.migrations[i-1].Version
+			}
+			return &m.migrations[i], prev, nil
+		}, func(mig *Migration) string { return mig.Down })
+		if err != nil || mig == nil {
+			return reverted, err
+		}
+		reverted = append(reverted, *mig)
+	}
+
+	return reverted, nil
+}
+
+// Force sets the version of the database without applying any
+// migrations, e.g., to recover from a migration that was partially
+// applied by hand.
+func (m *Migrator) Force(ctx context.Context, v int) error {
+	if _, err := m.index(v); err != nil {
+		return err
+	}
+
+	_, err := m.step(ctx, func(int) (*Migration, int, error) {
+		return &Migration{Version: v}, v, nil
+	}, func(*Migration) string { return "" })
+	return err
+}
+
+// index returns the index of the migration with the provided version,
+// or -1 for version 0.
+func (m *Migrator) index(v int) (int, error) {
+	if v == 0 {
+		return -1, nil
+	}
+
+	i := slices.IndexFunc(m.migrations, func(mig Migration) bool { return mig.Version == v })
+	if i == -1 {
+		return 0, fmt.Errorf("database is at unknown version %d, is it managed by a newer release?", v)
+	}
+	return i, nil
+}
+
+// step runs a single migration in a transaction. next returns the
+// migration to run based on the current version, along with the
+// version of the database once it ran, or nil if there is nothing to
+// do. query returns the SQL to run for the migration.
+func (m *Migrator) step(
+	ctx context.Context,
+	next func(v int) (*Migration, int, error),
+	query func(*Migration) string,
+) (*Migration, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // Why: No-op after commit.
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", lockID); err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if m.confirmDataLoss {
+		if _, err := tx.ExecContext(ctx, "SELECT set_config('binhost.confirm_data_loss', 'on', true)"); err != nil {
+			return nil, fmt.Errorf("failed to confirm data loss: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL)"); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	// Read the version after acquiring the lock, since another process
+	// may have migrated the database in the meantime.
+	v, err := version(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	mig, to, err := next(v)
+	if err != nil || mig == nil {
+		return nil, err
+	}
+
+	if q := query(mig); q != "" {
+		if _, err := tx.ExecContext(ctx, q); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == dataLossCode {
+				return nil, fmt.Errorf("%w: %d_%s: %s", ErrDataLoss, mig.Version, mig.Name, pgErr.Message)
+			}
+			return nil, fmt.Errorf("failed to run migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("failed to update schema version: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", to); err != nil {
+		return nil, fmt.Errorf("failed to update schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	return mig, nil
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// version returns the version stored in the schema_migrations table,
+// which must exist, or the version of an unversioned database if none
+// is stored.
+func version(ctx context.Context, q queryer) (int, error) {
+	var v int
+	err := q.QueryRowContext(ctx, "SELECT version FROM schema_migrations").Scan(&v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return unversioned(ctx, q)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query schema version: %w", err)
+	}
+
+	return v, nil
+}
+
+// unversioned returns the version of a database without a version
+// recorded: baseline if it contains the schema created by releases
+// before versioned migrations, 0 if it's empty.
+func unversioned(ctx context.Context, q queryer) (int, error) {
+	exists, err := tableExists(ctx, q, "pkgs")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query schema version: %w", err)
+	}
+	if exists {
+		return baseline, nil
+	}
+
+	return 0, nil
+}
+
+// tableExists returns true if the provided table exists in the current
+// schema.
+func tableExists(ctx context.Context, q queryer, table string) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,211 @@
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/migrations"
+	"github.com/jaredallard/binhost/internal/parser"
+	"gotest.tools/v3/assert"
+)
+
+func TestAllReturnsOrderedMigrations(t *testing.T) {
+	all, err := migrations.All()
+	assert.NilError(t, err)
+	assert.Assert(t, len(all) > 0)
+
+	for i, mig := range all {
+		// Versions are generated sequentially.
+		assert.Equal(t, i+1, mig.Version)
+		assert.Assert(t, strings.TrimSpace(mig.Up) != "", "migration %d has an empty up file", mig.Version)
+		assert.Assert(t, strings.TrimSpace(mig.Down) != "", "migration %d has an empty down file", mig.Version)
+	}
+}
+
+func TestMigrationsCreateTables(t *testing.T) {
+	all, err := migrations.All()
+	assert.NilError(t, err)
+	assert.Assert(t, len(all) >= 2)
+
+	tables := map[int][]string{
+		// The first migration is the schema of releases that created it
+		// automatically, so it must not contain anything newer.
+		0: {"targets", "pkgs"},
+		1: {"tokens", "token_targets"},
+	}
+	for i, names := range tables {
+		for _, table := range names {
+			assert.Assert(t, strings.Contains(all[i].Up, `CREATE TABLE "`+table+`"`), "migration %d: missing table %s", all[i].Version, table)
+			assert.Assert(t, strings.Contains(all[i].Down, `DROP TABLE "`+table+`"`), "migration %d: missing table %s", all[i].Version, table)
+		}
+	}
+	assert.Assert(t, !strings.Contains(all[0].Up, `"tokens"`))
+	assert.Assert(t, !strings.Contains(all[0].Up, `"object_key"`))
+}
+
+// newMigrator returns a migrator for a new, empty Postgres schema,
+// skipping the test if no Postgres server is configured.
+func newMigrator(t *testing.T) (*migrations.Migrator, *sql.DB) {
+	t.Helper()
+
+	db := dbtest.Postgres(t)
+	m, err := migrations.New(db)
+	assert.NilError(t, err)
+	return m, db
+}
+
+// assertVersion asserts that the database is at version v.
+func assertVersion(t *testing.T, m *migrations.Migrator, v int) {
+	t.Helper()
+
+	got, err := m.Version(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, got, v)
+}
+
+// count returns the number of rows in the provided table.
+func count(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+
+	var n int
+	assert.NilError(t, db.QueryRow(`SELECT count(*) FROM "`+table+`"`).Scan(&n))
+	return n
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	m, db := newMigrator(t)
+
+	assertVersion(t, m, 0)
+	assert.ErrorIs(t, m.Check(ctx), migrations.ErrVersionMismatch)
+
+	applied, err := m.Up(ctx, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, len(applied), 1)
+	assertVersion(t, m, 1)
+
+	applied, err = m.Up(ctx, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, len(applied), len(m.Migrations())-1)
+	assertVersion(t, m, m.Latest())
+	assert.NilError(t, m.Check(ctx))
+
+	applied, err = m.Up(ctx, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, len(applied), 0)
+
+	// The schema matches what ent expects.
+	client := ent.NewClient(ent.Driver(entsql.OpenDB(dialect.Postgres, db)))
+	target, err := client.Target.Create().SetName("x86_64-pc-linux-gnu").Save(ctx)
+	assert.NilError(t, err)
+	_, err = client.Pkg.Create().
+		SetRepository("gentoo").SetCategory("app-misc").SetName("foo").SetVersion("1.0").
+		SetPackageFields(&parser.PackageCommon{}).
+		SetPath("app-misc/foo/foo-1.0-1.gpkg.tar").SetObjectKey("app-misc/foo/foo-1.0-1.gpkg.tar").
+		SetTarget(target).
+		Save(ctx)
+	assert.NilError(t, err)
+	_, err = client.Token.Create().SetName("ci").SetHash("hash").SetScopes([]string{"upload"}).AddTargets(target).Save(ctx)
+	assert.NilError(t, err)
+
+	reverted, err := m.Down(ctx, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, len(reverted), 1)
+	assert.Equal(t, reverted[0].Version, m.Latest())
+	assertVersion(t, m, m.Latest()-1)
+	assert.ErrorIs(t, m.Check(ctx), migrations.ErrVersionMismatch)
+
+	reverted, err = m.Down(ctx, len(m.Migrations()))
+	assert.NilError(t, err)
+	assert.Equal(t, len(reverted), len(m.Migrations())-1)
+	assertVersion(t, m, 0)
+
+	reverted, err = m.Down(ctx, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, len(reverted), 0)
+}
+
+func TestMigratorForce(t *testing.T) {
+	ctx := context.Background()
+	m, db := newMigrator(t)
+
+	assert.NilError(t, m.Force(ctx, m.Latest()))
+	assertVersion(t, m, m.Latest())
+	assert.NilError(t, m.Check(ctx))
+
+	// No migrations were run.
+	var exists bool
+	assert.NilError(t, db.QueryRow("SELECT to_regclass('targets') IS NOT NULL").Scan(&exists))
+	assert.Assert(t, !exists)
+
+	assert.ErrorContains(t, m.Force(ctx, m.Latest()+1), "unknown version")
+	assertVersion(t, m, m.Latest())
+
+	assert.NilError(t, m.Force(ctx, 0))
+	assertVersion(t, m, 0)
+}
+
+// createUnversioned creates the schema of releases before versioned
+// migrations, with n packages.
+func createUnversioned(t *testing.T, db *sql.DB, n int) {
+	t.Helper()
+
+	all, err := migrations.All()
+	assert.NilError(t, err)
+	_, err = db.Exec(all[0].Up)
+	assert.NilError(t, err)
+
+	target := uuid.New()
+	_, err = db.Exec(`INSERT INTO "targets" ("id", "name") VALUES ($1, 'x86_64-pc-linux-gnu')`, target)
+	assert.NilError(t, err)
+	for i := range n {
+		_, err = db.Exec(`INSERT INTO "pkgs" ("id", "repository", "category", "name", "version", "package_fields", "target_id") `+
+			`VALUES ($1, 'gentoo', 'app-misc', 'foo', $2, '{}', $3)`, uuid.New(), fmt.Sprintf("1.%d", i), target)
+		assert.NilError(t, err)
+	}
+}
+
+func TestMigratorAdoptsUnversionedDatabase(t *testing.T) {
+	ctx := context.Background()
+	m, db := newMigrator(t)
+	createUnversioned(t, db, 0)
+
+	assertVersion(t, m, 1)
+	assert.ErrorIs(t, m.Check(ctx), migrations.ErrVersionMismatch)
+
+	applied, err := m.Up(ctx, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, len(applied), len(m.Migrations())-1)
+	assert.Equal(t, applied[0].Version, 2)
+	assert.NilError(t, m.Check(ctx))
+	assert.Equal(t, count(t, db, "targets"), 1)
+}
+
+func TestMigratorRefusesToDeletePackagesWithoutConfirmation(t *testing.T) {
+	ctx := context.Background()
+	m, db := newMigrator(t)
+	createUnversioned(t, db, 2)
+
+	applied, err := m.Up(ctx, 0)
+	assert.ErrorIs(t, err, migrations.ErrDataLoss)
+	assert.ErrorContains(t, err, "2 package records")
+	assert.Equal(t, len(applied), 0)
+	assertVersion(t, m, 1)
+	assert.Equal(t, count(t, db, "pkgs"), 2)
+
+	m.ConfirmDataLoss()
+	applied, err = m.Up(ctx, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, len(applied), len(m.Migrations())-1)
+	assert.NilError(t, m.Check(ctx))
+	assert.Equal(t, count(t, db, "pkgs"), 0)
+	assert.Equal(t, count(t, db, "targets"), 1)
+}
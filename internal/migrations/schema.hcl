@@ -0,0 +1,200 @@
+table "pkgs" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = uuid
+  }
+  column "repository" {
+    null = false
+    type = varchar
+  }
+  column "category" {
+    null = false
+    type = varchar
+  }
+  column "name" {
+    null = false
+    type = varchar
+  }
+  column "version" {
+    null = false
+    type = varchar
+  }
+  column "revision" {
+    null    = false
+    type    = varchar
+    default = sql("''")
+  }
+  column "build_id" {
+    null    = false
+    type    = bigint
+    default = sql("1")
+  }
+  column "package_fields" {
+    null = false
+    type = jsonb
+  }
+  column "format" {
+    null    = false
+    type    = varchar
+    default = sql("'gpkg'")
+  }
+  column "path" {
+    null = false
+    type = varchar
+  }
+  column "object_key" {
+    null = false
+    type = varchar
+  }
+  column "size" {
+    null    = false
+    type    = bigint
+    default = sql("0")
+  }
+  column "sha1" {
+    null    = false
+    type    = varchar
+    default = sql("''")
+  }
+  column "md5" {
+    null    = false
+    type    = varchar
+    default = sql("''")
+  }
+  column "blake2b" {
+    null    = false
+    type    = varchar
+    default = sql("''")
+  }
+  column "sha512" {
+    null    = false
+    type    = varchar
+    default = sql("''")
+  }
+  column "mtime" {
+    null = false
+    type = timestamptz
+  }
+  column "target_id" {
+    null = false
+    type = uuid
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "pkgs_targets_target" {
+    columns     = [column.target_id]
+    ref_columns = [table.targets.column.id]
+    on_delete   = NO_ACTION
+  }
+  index "pkg_repository_category_name_v_e8275bfb1e4f19d42f902830e154ca0c" {
+    unique  = true
+    columns = [column.repository, column.category, column.name, column.version, column.revision, column.build_id, column.target_id]
+  }
+  index "pkg_target_id_path" {
+    unique  = true
+    columns = [column.target_id, column.path]
+  }
+}
+table "targets" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = uuid
+  }
+  column "name" {
+    null = false
+    type = varchar
+  }
+  column "description" {
+    null    = false
+    type    = varchar
+    default = sql("''")
+  }
+  column "owner" {
+    null    = false
+    type    = varchar
+    default = sql("''")
+  }
+  column "private" {
+    null    = false
+    type    = boolean
+    default = sql("false")
+  }
+  column "profile" {
+    null = true
+    type = jsonb
+  }
+  column "index_updated_at" {
+    null = false
+    type = timestamptz
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "targets_name_key" {
+    unique  = true
+    columns = [column.name]
+  }
+}
+table "tokens" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = uuid
+  }
+  column "name" {
+    null = false
+    type = varchar
+  }
+  column "hash" {
+    null = false
+    type = varchar
+  }
+  column "scopes" {
+    null = false
+    type = jsonb
+  }
+  column "created_at" {
+    null = false
+    type = timestamptz
+  }
+  column "expires_at" {
+    null = true
+    type = timestamptz
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "tokens_hash_key" {
+    unique  = true
+    columns = [column.hash]
+  }
+}
+table "token_targets" {
+  schema = schema.public
+  column "token_id" {
+    null = false
+    type = uuid
+  }
+  column "target_id" {
+    null = false
+    type = uuid
+  }
+  primary_key {
+    columns = [column.token_id, column.target_id]
+  }
+  foreign_key "token_targets_token_id" {
+    columns     = [column.token_id]
+    ref_columns = [table.tokens.column.id]
+    on_delete   = CASCADE
+  }
+  foreign_key "token_targets_target_id" {
+    columns     = [column.target_id]
+    ref_columns = [table.targets.column.id]
+    on_delete   = CASCADE
+  }
+}
+schema "public" {
+}
@@ -0,0 +1,4 @@
+-- Drop "pkgs" table
+DROP TABLE "pkgs";
+-- Drop "targets" table
+DROP TABLE "targets";
@@ -0,0 +1,8 @@
+-- Create "targets" table
+CREATE TABLE "targets" ("id" uuid NOT NULL, "name" character varying NOT NULL, PRIMARY KEY ("id"));
+-- Create index "targets_name_key" to table: "targets"
+CREATE UNIQUE INDEX "targets_name_key" ON "targets" ("name");
+-- Create "pkgs" table
+CREATE TABLE "pkgs" ("id" uuid NOT NULL, "repository" character varying NOT NULL, "category" character varying NOT NULL, "name" character varying NOT NULL, "version" character varying NOT NULL, "package_fields" jsonb NOT NULL, "target_id" uuid NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "pkgs_targets_target" FOREIGN KEY ("target_id") REFERENCES "targets" ("id") ON DELETE NO ACTION);
+-- Create index "pkg_repository_category_name_version_target_id" to table: "pkgs"
+CREATE UNIQUE INDEX "pkg_repository_category_name_version_target_id" ON "pkgs" ("repository", "category", "name", "version", "target_id");
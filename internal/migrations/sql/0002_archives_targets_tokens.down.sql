@@ -0,0 +1,10 @@
+-- Modify "pkgs" table
+ALTER TABLE "pkgs" DROP COLUMN "revision", DROP COLUMN "build_id", DROP COLUMN "format", DROP COLUMN "path", DROP COLUMN "object_key", DROP COLUMN "size", DROP COLUMN "sha1", DROP COLUMN "md5", DROP COLUMN "blake2b", DROP COLUMN "sha512", DROP COLUMN "mtime";
+-- Create index "pkg_repository_category_name_version_target_id" to table: "pkgs"
+CREATE UNIQUE INDEX "pkg_repository_category_name_version_target_id" ON "pkgs" ("repository", "category", "name", "version", "target_id");
+-- Modify "targets" table
+ALTER TABLE "targets" DROP COLUMN "description", DROP COLUMN "owner", DROP COLUMN "private", DROP COLUMN "profile", DROP COLUMN "index_updated_at";
+-- Drop "token_targets" table
+DROP TABLE "token_targets";
+-- Drop "tokens" table
+DROP TABLE "tokens";
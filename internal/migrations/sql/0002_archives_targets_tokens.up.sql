@@ -0,0 +1,28 @@
+-- Releases before this migration recorded package metadata without storing
+-- the archive, so none of the existing rows can be served. Drop them once the
+-- operator confirmed it; clients upload their packages again.
+DO $$
+BEGIN
+  IF current_setting('binhost.confirm_data_loss', true) IS DISTINCT FROM 'on' AND EXISTS (SELECT 1 FROM "pkgs") THEN
+    RAISE EXCEPTION USING ERRCODE = 'BH001', MESSAGE = format('%s package records were uploaded without their archive and would be deleted', (SELECT count(*) FROM "pkgs"));
+  END IF;
+END $$;
+DELETE FROM "pkgs";
+-- Drop index "pkg_repository_category_name_version_target_id" from table: "pkgs"
+DROP INDEX "pkg_repository_category_name_version_target_id";
+-- Modify "pkgs" table
+ALTER TABLE "pkgs" ADD COLUMN "revision" character varying NOT NULL DEFAULT '', ADD COLUMN "build_id" bigint NOT NULL DEFAULT 1, ADD COLUMN "format" character varying NOT NULL DEFAULT 'gpkg', ADD COLUMN "path" character varying NOT NULL, ADD COLUMN "object_key" character varying NOT NULL, ADD COLUMN "size" bigint NOT NULL DEFAULT 0, ADD COLUMN "sha1" character varying NOT NULL DEFAULT '', ADD COLUMN "md5" character varying NOT NULL DEFAULT '', ADD COLUMN "blake2b" character varying NOT NULL DEFAULT '', ADD COLUMN "sha512" character varying NOT NULL DEFAULT '', ADD COLUMN "mtime" timestamptz NOT NULL;
+-- Create index "pkg_repository_category_name_v_e8275bfb1e4f19d42f902830e154ca0c" to table: "pkgs"
+CREATE UNIQUE INDEX "pkg_repository_category_name_v_e8275bfb1e4f19d42f902830e154ca0c" ON "pkgs" ("repository", "category", "name", "version", "revision", "build_id", "target_id");
+-- Create index "pkg_target_id_path" to table: "pkgs"
+CREATE UNIQUE INDEX "pkg_target_id_path" ON "pkgs" ("target_id", "path");
+-- Create "tokens" table
+CREATE TABLE "tokens" ("id" uuid NOT NULL, "name" character varying NOT NULL, "hash" character varying NOT NULL, "scopes" jsonb NOT NULL, "created_at" timestamptz NOT NULL, "expires_at" timestamptz NULL, PRIMARY KEY ("id"));
+-- Create index "tokens_hash_key" to table: "tokens"
+CREATE UNIQUE INDEX "tokens_hash_key" ON "tokens" ("hash");
+-- Modify "targets" table
+ALTER TABLE "targets" ADD COLUMN "description" character varying NOT NULL DEFAULT '', ADD COLUMN "owner" character varying NOT NULL DEFAULT '', ADD COLUMN "private" boolean NOT NULL DEFAULT false, ADD COLUMN "profile" jsonb NULL, ADD COLUMN "index_updated_at" timestamptz NOT NULL DEFAULT now();
+-- Existing targets are backfilled above; new ones always set the column.
+ALTER TABLE "targets" ALTER COLUMN "index_updated_at" DROP DEFAULT;
+-- Create "token_targets" table
+CREATE TABLE "token_targets" ("token_id" uuid NOT NULL, "target_id" uuid NOT NULL, PRIMARY KEY ("token_id", "target_id"), CONSTRAINT "token_targets_token_id" FOREIGN KEY ("token_id") REFERENCES "tokens" ("id") ON DELETE CASCADE, CONSTRAINT "token_targets_target_id" FOREIGN KEY ("target_id") REFERENCES "targets" ("id") ON DELETE CASCADE);
@@ -0,0 +1,92 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package packages
+
+import (
+	"crypto/md5"  //nolint:gosec // Why: Required by Portage, not used for security.
+	"crypto/sha1" //nolint:gosec // Why: Required by Portage, not used for security.
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Checksums contains the size and checksums of a package archive.
+type Checksums struct {
+	// Size is the size of the archive in bytes.
+	Size int64
+
+	SHA1    string
+	MD5     string
+	BLAKE2B string
+	SHA512  string
+}
+
+// checksummer is an [io.Writer] that calculates [Checksums] of the data
+// written to it. Create using newChecksummer.
+type checksummer struct {
+	size int64
+
+	sha1    hash.Hash
+	md5     hash.Hash
+	blake2b hash.Hash
+	sha512  hash.Hash
+
+	w io.Writer
+}
+
+// newChecksummer creates a new checksummer.
+func newChecksummer() *checksummer {
+	c := &checksummer{
+		sha1:    sha1.New(), //nolint:gosec // Why: See import.
+		md5:     md5.New(),  //nolint:gosec // Why: See import.
+		blake2b: NewBlake2b(),
+		sha512:  sha512.New(),
+	}
+	c.w = io.MultiWriter(c.sha1, c.md5, c.blake2b, c.sha512)
+	return c
+}
+
+// Write implements the io.Writer interface.
+func (c *checksummer) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.size += int64(n)
+	return n, err
+}
+
+// Checksums returns the checksums of all data written so far.
+func (c *checksummer) Checksums() Checksums {
+	return Checksums{
+		Size:    c.size,
+		SHA1:    hex.EncodeToString(c.sha1.Sum(nil)),
+		MD5:     hex.EncodeToString(c.md5.Sum(nil)),
+		BLAKE2B: hex.EncodeToString(c.blake2b.Sum(nil)),
+		SHA512:  hex.EncodeToString(c.sha512.Sum(nil)),
+	}
+}
+
+// NewBlake2b returns a new BLAKE2b-512 hash, as used for BLAKE2B in
+// Manifests and the Packages index.
+func NewBlake2b() hash.Hash {
+	h, err := blake2b.New512(nil)
+	if err != nil {
+		// Only happens when a key is provided.
+		panic(err)
+	}
+	return h
+}
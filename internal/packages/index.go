@@ -0,0 +1,123 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package packages
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jaredallard/binhost/internal/atom"
+	"github.com/jaredallard/binhost/internal/parser"
+)
+
+// FromIndex creates a Package for the package archive at path using
+// its entry in a Packages index as the metadata, instead of reading it
+// from the archive. This is intended for packages whose metadata can't
+// be read by New, e.g., because they predate Manifest files.
+//
+// The archive must match the size and checksums in the entry, so that
+// a corrupted archive isn't mistaken for a valid one. At least one
+// checksum must be present.
+//
+// The archive is not copied, so Delete doesn't remove it.
+func FromIndex(path string, entry *parser.Package) (*Package, error) {
+	category, name, v, err := atom.ParseCPV(entry.CPV)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CPV: %w", err)
+	}
+	_, pf, _ := strings.Cut(entry.CPV, "/")
+
+	format := Format(entry.Format)
+	if format == "" {
+		// BINPKG_FORMAT predates gpkg, so it's XPAK unless the extension
+		// says otherwise.
+		format = FormatXpak
+		if strings.HasSuffix(path, ".gpkg.tar") {
+			format = FormatGpkg
+		}
+	}
+	if format != FormatGpkg && format != FormatXpak {
+		return nil, fmt.Errorf("unsupported BINPKG_FORMAT %q", entry.Format)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open package: %w", err)
+	}
+	defer f.Close()
+
+	sums := newChecksummer()
+	if _, err := io.Copy(sums, f); err != nil {
+		return nil, fmt.Errorf("failed to read package: %w", err)
+	}
+	if err := verifyIndexEntry(entry, sums.Checksums()); err != nil {
+		return nil, err
+	}
+
+	md := Metadata{
+		PackageCommon: entry.PackageCommon,
+		Name:          name,
+		Version:       v.PV(),
+		Revision:      v.PR(),
+		Category:      category,
+		PF:            pf,
+	}
+	// SIZE in an index is the size of the archive, not the installed
+	// size.
+	md.Size = 0
+
+	return &Package{
+		Metadata:    md,
+		Format:      format,
+		Checksums:   sums.Checksums(),
+		archivePath: path,
+		borrowed:    true,
+	}, nil
+}
+
+// verifyIndexEntry checks that the provided checksums of an archive
+// match its entry in a Packages index.
+func verifyIndexEntry(entry *parser.Package, sums Checksums) error {
+	if entry.Size != 0 && int64(entry.Size) != sums.Size {
+		return fmt.Errorf("size mismatch with Packages index (expected %d, got %d)", entry.Size, sums.Size)
+	}
+
+	var verified bool
+	for _, c := range []struct {
+		name             string
+		expected, actual string
+	}{
+		{"BLAKE2B", entry.BLAKE2B, sums.BLAKE2B},
+		{"SHA512", entry.SHA512, sums.SHA512},
+		{"SHA1", entry.SHA1, sums.SHA1},
+		{"MD5", entry.MD5, sums.MD5},
+	} {
+		if c.expected == "" {
+			continue
+		}
+		if !strings.EqualFold(c.expected, c.actual) {
+			return fmt.Errorf("%s mismatch with Packages index", c.name)
+		}
+		verified = true
+	}
+	if !verified {
+		return fmt.Errorf("no checksums in Packages index entry")
+	}
+
+	return nil
+}
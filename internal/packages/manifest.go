@@ -0,0 +1,186 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package packages
+
+import (
+	"bufio"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Hash algorithms, by their name in the Manifest, that members of a
+// gpkg are verified with. Portage always includes BLAKE2B in gpkg
+// Manifests, so it is required. SHA512 is verified when present.
+const (
+	manifestHash       = "BLAKE2B"
+	manifestSecondHash = "SHA512"
+)
+
+// ManifestError is returned when a file in a gpkg does not match the
+// gpkg's Manifest.
+type ManifestError struct {
+	// File is the name of the file that failed verification.
+	File string
+
+	// Reason is a human readable reason for the failure.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("manifest verification failed for %s: %s", e.File, e.Reason)
+}
+
+// manifestEntry is a DATA entry in a gpkg Manifest.
+type manifestEntry struct {
+	// Name is the name of the file in the gpkg.
+	Name string
+
+	// Size is the expected size of the file in bytes.
+	Size int64
+
+	// Hashes contains the expected hex encoded hashes of the file keyed
+	// by the hash algorithm (e.g., BLAKE2B).
+	Hashes map[string]string
+}
+
+// parseManifest parses the DATA entries out of a gpkg Manifest. The
+// Manifest may be clearsigned, in which case the signature is ignored.
+func parseManifest(r io.Reader) ([]manifestEntry, error) {
+	var entries []manifestEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "-----BEGIN PGP SIGNATURE-----" {
+			break
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "DATA" {
+			continue
+		}
+
+		// DATA <name> <size> [<hash name> <hash>]...
+		if len(fields) < 3 || len(fields)%2 != 1 {
+			return nil, fmt.Errorf("invalid manifest line: %s", line)
+		}
+
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size in manifest line: %s", line)
+		}
+
+		e := manifestEntry{Name: fields[1], Size: size, Hashes: make(map[string]string)}
+		for i := 3; i < len(fields); i += 2 {
+			e.Hashes[fields[i]] = strings.ToLower(fields[i+1])
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// memberHasher is an [io.Writer] that calculates the size and the
+// hashes of a gpkg member written to it that are verified against the
+// Manifest.
+type memberHasher struct {
+	size   int64
+	hashes map[string]hash.Hash
+	w      io.Writer
+}
+
+// newMemberHasher creates a new memberHasher.
+func newMemberHasher() *memberHasher {
+	m := &memberHasher{hashes: map[string]hash.Hash{
+		manifestHash:       NewBlake2b(),
+		manifestSecondHash: sha512.New(),
+	}}
+	m.w = io.MultiWriter(m.hashes[manifestHash], m.hashes[manifestSecondHash])
+	return m
+}
+
+// Write implements the io.Writer interface.
+func (m *memberHasher) Write(p []byte) (int, error) {
+	m.w.Write(p) //nolint:errcheck // Why: hash.Hash never returns an error.
+	m.size += int64(len(p))
+	return len(p), nil
+}
+
+// verify checks that the member described by the entry matches the
+// provided hasher. A [ManifestError] is returned if it does not.
+func (e *manifestEntry) verify(m *memberHasher) error {
+	if e.Size != m.size {
+		return &ManifestError{
+			File:   e.Name,
+			Reason: fmt.Sprintf("size mismatch (expected %d, got %d)", e.Size, m.size),
+		}
+	}
+
+	if _, ok := e.Hashes[manifestHash]; !ok {
+		return &ManifestError{File: e.Name, Reason: "no " + manifestHash + " hash"}
+	}
+	for name, h := range m.hashes {
+		expected, ok := e.Hashes[name]
+		if !ok {
+			continue
+		}
+		if hex.EncodeToString(h.Sum(nil)) != expected {
+			return &ManifestError{File: e.Name, Reason: name + " mismatch"}
+		}
+	}
+
+	return nil
+}
+
+// verifyManifest validates the members of a gpkg, keyed by their name,
+// against the provided Manifest. Every member other than the Manifest
+// itself must be listed in it.
+func verifyManifest(manifest io.Reader, members map[string]*memberHasher) error {
+	entries, err := parseManifest(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to parse Manifest: %w", err)
+	}
+
+	listed := make(map[string]bool, len(entries))
+	for i := range entries {
+		m, ok := members[entries[i].Name]
+		if !ok {
+			return &ManifestError{File: entries[i].Name, Reason: "file is missing"}
+		}
+
+		if err := entries[i].verify(m); err != nil {
+			return err
+		}
+		listed[entries[i].Name] = true
+	}
+
+	for name := range members {
+		if name != "Manifest" && !listed[name] {
+			return &ManifestError{File: name, Reason: "not listed in Manifest"}
+		}
+	}
+
+	return nil
+}
@@ -17,35 +17,78 @@
 package packages
 
 import (
+	"archive/tar"
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/jaredallard/binhost/internal/archive"
+	"github.com/jaredallard/binhost/internal/atom"
 	"github.com/jaredallard/binhost/internal/parser"
 )
 
 // supportedCompressionExtensions is a list of supported compression
 // extensions that this package can handle.
-var supportedCompressionExtensions = []string{"xz", "gz", "bz2"}
+var supportedCompressionExtensions = []string{"xz", "gz", "bz2", "zst", "lz4", "lz", "lzma"}
 
-// Package represents a Gentoo gpkg (xpkg is not supported).
+// maxMetadataSize is the maximum size of the Manifest, the metadata
+// archive and each file inside of it. These are read into memory.
+const maxMetadataSize = 64 * 1024 * 1024
+
+// Format is the format of a binary package.
+type Format string
+
+// Supported binary package formats.
+const (
+	// FormatGpkg is the tar based gpkg format (BINPKG_FORMAT=gpkg).
+	FormatGpkg Format = "gpkg"
+
+	// FormatXpak is the legacy tbz2 format with XPAK metadata appended
+	// to the image (BINPKG_FORMAT=xpak).
+	FormatXpak Format = "xpak"
+)
+
+// Package represents a Gentoo binary package, either a gpkg or a
+// legacy XPAK (tbz2) package.
 type Package struct {
-	// Metadata contains package fields from a metadata.tar of a gpkg.
+	// Metadata contains package fields from a metadata.tar of a gpkg or
+	// the XPAK segment of a tbz2.
 	Metadata
 
-	// Fields below are populated from the extracted contents of the gpkg.
+	// Format is the format of the package.
+	Format Format
+
+	// Checksums contains the size and checksums of the original package.
+	Checksums Checksums
 
-	// path is the path to the extracted gpkg on disk.
-	path string
+	// archivePath is the path to the original package on disk.
+	archivePath string
+
+	// borrowed is true if archivePath isn't a copy owned by the
+	// Package, see FromIndex.
+	borrowed bool
 }
 
-// Delete removes the package from disk.
+// Delete removes the package from disk. It is a no-op for packages
+// created using FromIndex.
 func (p *Package) Delete() error {
-	return os.RemoveAll(p.path)
+	if p.borrowed {
+		return nil
+	}
+	return os.Remove(p.archivePath)
+}
+
+// Archive opens the original package that this package was created
+// from.
+// The caller is responsible for closing the returned file.
+func (p *Package) Archive() (*os.File, error) {
+	return os.Open(p.archivePath)
 }
 
 // Metadata is the representation of a metadata.tar from a gpkg.
@@ -54,8 +97,12 @@ type Metadata struct {
 
 	// Name is the name of the package as calculated from the PF.
 	Name string
-	// Version is the version of the package as calculated from the PF.
+	// Version is the version of the package, without the revision, as
+	// calculated from the PF.
 	Version string
+	// Revision is the revision of the package (e.g., r1) as calculated
+	// from the PF. Empty if the package has no revision.
+	Revision string
 
 	CBuild        string
 	CFlags        string
@@ -73,77 +120,249 @@ type Metadata struct {
 
 // New creates a new Package from the provided [io.ReadCloser]. The
 // provided ReadCloser should be streaming the raw contents of a Gentoo
-// package (gpkg).
+// binary package, either a gpkg or a legacy XPAK package (tbz2). The
+// format is detected from the contents.
 //
-// The package will be stored on disk in a temporary directory due to
-// the nature of gpkgs being usually a large tarball.
+// A gpkg is read in a single pass. Only the metadata archive is
+// decompressed (in memory), every other member is only hashed in order
+// to validate it against the Manifest. A copy of the original package
+// is kept on disk so that it can be stored later, see
+// [Package.Archive]. Checksums of the package are calculated while it
+// is being read.
 func New(r io.Reader) (*Package, error) {
-	tmpDir, err := os.MkdirTemp("", "binhost-extract-")
+	// Keep a copy of the original package around so that it can be
+	// stored after we've parsed it.
+	archiveFile, err := os.CreateTemp("", "binhost-pkg-*")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
 	}
+	defer archiveFile.Close()
 
-	// Cleanup the temp directory if we fail.
-	var keepTempDir bool
+	// Cleanup the temp file if we fail.
+	var keepArchive bool
 	defer func() {
-		if !keepTempDir {
-			os.RemoveAll(tmpDir)
+		if !keepArchive {
+			os.Remove(archiveFile.Name())
 		}
 	}()
 
-	if err := archive.Extract(archive.ExtractOptions{
-		Reader:    r,
-		Extension: "tar", // gpkg files are tar archives.
-	}, tmpDir); err != nil {
-		return nil, fmt.Errorf("failed to extract gpkg: %w", err)
+	sums := newChecksummer()
+	br := bufio.NewReader(io.TeeReader(r, io.MultiWriter(archiveFile, sums)))
+
+	format := FormatXpak
+	if isTar(br) {
+		format = FormatGpkg
 	}
 
-	// Move te files out of the sub dir by finding the first dir in the
-	// temp dir.
-	contents, err := os.ReadDir(tmpDir)
+	var contents *gpkgContents
+	if format == FormatGpkg {
+		contents, err = readGpkg(br)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The tar reader stops at the end-of-archive marker, so make sure
+	// any trailing padding makes it into our copy too. For XPAK packages
+	// this reads the entire package.
+	if _, err := io.Copy(io.Discard, br); err != nil {
+		return nil, fmt.Errorf("failed to read package: %w", err)
+	}
+
+	if err := archiveFile.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to write package to disk: %w", err)
+	}
+
+	var md *Metadata
+	switch format {
+	case FormatGpkg:
+		md, err = contents.metadata()
+	case FormatXpak:
+		md, err = xpakMetadata(archiveFile, sums.Checksums().Size)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read temporary directory: %w", err)
+		return nil, err
 	}
 
-	var subDir string
-	for _, f := range contents {
-		if f.IsDir() {
-			subDir = f.Name()
-			break
-		}
+	keepArchive = true
+	return &Package{
+		Metadata:    *md,
+		Format:      format,
+		Checksums:   sums.Checksums(),
+		archivePath: archiveFile.Name(),
+	}, nil
+}
+
+// isTar returns true if the contents of br look like a tar archive
+// based on the ustar magic in the first header.
+func isTar(br *bufio.Reader) bool {
+	// Errors are ignored, we'll find out about them on the next read.
+	b, _ := br.Peek(262) //nolint:errcheck // Why: See above.
+	return len(b) == 262 && string(b[257:262]) == "ustar"
+}
+
+// xpakMetadata reads the metadata of the XPAK package stored in f.
+func xpakMetadata(f *os.File, size int64) (*Metadata, error) {
+	files, err := readXpak(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XPAK: %w", err)
+	}
+
+	md, err := metadataFromFiles(files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manifest from metadata: %w", err)
 	}
-	// If there's no sub dir, optimistically assume the contents are at
-	// the root.
-	if subDir != "" {
-		contents, err := os.ReadDir(filepath.Join(tmpDir, subDir))
+
+	return md, nil
+}
+
+// gpkgContents contains the parts of a gpkg that were read by readGpkg.
+type gpkgContents struct {
+	// manifest is the contents of the Manifest.
+	manifest []byte
+
+	// metadataExt is the compression extension of the metadata archive.
+	metadataExt string
+
+	// metadataArchive is the compressed metadata archive.
+	metadataArchive []byte
+
+	// members contains hashes of every member of the gpkg keyed by the
+	// name of the member.
+	members map[string]*memberHasher
+}
+
+// readGpkg reads the members of the gpkg tar read from r. Members other
+// than the Manifest and metadata archive are only hashed.
+func readGpkg(r io.Reader) (*gpkgContents, error) {
+	contents := &gpkgContents{members: make(map[string]*memberHasher)}
+
+	err := archive.Walk(archive.ExtractOptions{
+		Reader:    r,
+		Extension: "tar", // gpkg files are tar archives.
+	}, func(h *tar.Header, r io.Reader) error {
+		if h.Typeflag == tar.TypeDir {
+			return nil
+		}
+		if h.Typeflag != tar.TypeReg {
+			return fmt.Errorf("unsupported file type in gpkg (%s: %v)", h.Name, h.Typeflag)
+		}
+
+		// Members are stored in a directory named after the package, but
+		// optimistically allow them to be at the root too.
+		name := path.Base(h.Name)
+		if dir := path.Dir(h.Name); strings.Contains(strings.Trim(dir, "/"), "/") {
+			return fmt.Errorf("unexpected file in gpkg: %s", h.Name)
+		}
+		if _, ok := contents.members[name]; ok {
+			return fmt.Errorf("duplicate file in gpkg: %s", name)
+		}
+
+		m := newMemberHasher()
+		contents.members[name] = m
+		r = io.TeeReader(r, m)
+
+		ext, isMetadata := strings.CutPrefix(name, "metadata.tar.")
+		isMetadata = isMetadata && slices.Contains(supportedCompressionExtensions, ext)
+
+		var err error
+		switch {
+		case name == "Manifest":
+			contents.manifest, err = readAllLimit(r, maxMetadataSize)
+		case isMetadata:
+			contents.metadataExt = ext
+			contents.metadataArchive, err = readAllLimit(r, maxMetadataSize)
+		default:
+			// Hash everything else (e.g., the image) without keeping it.
+			_, err = io.Copy(io.Discard, r)
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to read temp directory sub dir: %w", err)
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gpkg: %w", err)
+	}
+
+	return contents, nil
+}
+
+// metadata validates the contents of the gpkg against the Manifest and
+// returns the decoded metadata.
+func (c *gpkgContents) metadata() (*Metadata, error) {
+	expectedFiles := []string{"Manifest", "gpkg-1"}
+	expectedArchives := []string{"image", "metadata"}
+
+	for _, name := range expectedFiles {
+		if _, ok := c.members[name]; !ok {
+			return nil, fmt.Errorf("package missing required file: %s", name)
 		}
+	}
 
-		for _, f := range contents {
-			if err := os.Rename(filepath.Join(tmpDir, subDir, f.Name()), filepath.Join(tmpDir, f.Name())); err != nil {
-				return nil, fmt.Errorf("failed to move file out of sub dir: %w", err)
+	for _, name := range expectedArchives {
+		var found bool
+		for _, ext := range supportedCompressionExtensions {
+			if _, ok := c.members[name+".tar."+ext]; ok {
+				found = true
+				break
 			}
 		}
+		if !found {
+			return nil, fmt.Errorf("package missing required archive: %s", name)
+		}
+	}
+
+	// Ensure the contents match the Manifest before we decompress
+	// anything.
+	if err := verifyManifest(bytes.NewReader(c.manifest), c.members); err != nil {
+		return nil, err
+	}
 
-		if err := os.Remove(filepath.Join(tmpDir, subDir)); err != nil {
-			return nil, fmt.Errorf("failed to remove sub dir: %w", err)
+	files := make(map[string][]byte)
+	if err := archive.Walk(archive.ExtractOptions{
+		Reader:    bytes.NewReader(c.metadataArchive),
+		Extension: c.metadataExt,
+	}, func(h *tar.Header, r io.Reader) error {
+		if h.Typeflag != tar.TypeReg || path.Dir(path.Clean(h.Name)) != "metadata" {
+			return nil
+		}
+
+		b, err := readAllLimit(r, maxMetadataSize)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", h.Name, err)
 		}
+		files[path.Base(h.Name)] = b
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read metadata archive: %w", err)
 	}
 
-	p, err := packageFromDir(tmpDir)
+	md, err := metadataFromFiles(files)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create package from extracted contents: %w", err)
+		return nil, fmt.Errorf("failed to create manifest from metadata: %w", err)
 	}
-	p.path = tmpDir
 
-	keepTempDir = true
-	return p, nil
+	return md, nil
 }
 
-// metadataFromDir creates a package manifest out of the contents of an
-// extracted manifest.tar file.
-func metadataFromDir(dir string) (*Metadata, error) {
+// readAllLimit reads all of r into memory, returning an error if r
+// contains more than limit bytes.
+func readAllLimit(r io.Reader, limit int64) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return nil, fmt.Errorf("exceeds maximum size of %d bytes", limit)
+	}
+	return b, nil
+}
+
+// metadataFromFiles creates a package manifest out of the contents of
+// the files in a metadata.tar, keyed by their name.
+func metadataFromFiles(files map[string][]byte) (*Metadata, error) {
 	md := &Metadata{}
 
 	filesToFields := map[string]any{
@@ -173,9 +392,9 @@ func metadataFromDir(dir string) (*Metadata, error) {
 	}
 
 	for file, field := range filesToFields {
-		data, err := os.ReadFile(filepath.Join(dir, file))
-		if err != nil {
-			// Can't read? It's likely just not set.
+		data, ok := files[file]
+		if !ok {
+			// Not present? It's likely just not set.
 			continue
 		}
 
@@ -197,68 +416,18 @@ func metadataFromDir(dir string) (*Metadata, error) {
 		}
 	}
 
-	// Calculate name and version from PF. The version is the last part of
-	// the string split by the '-' character.
-	//
-	// TODO(jaredallard): This will break on packages with - in the
-	// version. I'm not sure how we're supposed to get the version then.
-	parts := strings.Split(md.PF, "-")
-	md.Name = strings.Join(parts[:len(parts)-1], "-")
-	md.Version = parts[len(parts)-1]
-	return md, nil
-}
-
-// packageFromDir creates a Package from the extracted contents of a
-// gpkg tar. The supplied directory's Manifest is used to validate the
-// contents of the package.
-//
-// TODO(jaredallard): We don't currently validate the Manifest.
-func packageFromDir(dir string) (*Package, error) {
-	expectedFiles := []string{"Manifest", "gpkg-1"}
-	expectedArchives := []string{"image", "metadata"}
-
-	for _, name := range expectedFiles {
-		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
-			return nil, fmt.Errorf("package missing required file: %s", name)
-		}
+	// The category and name end up in paths, so they must be valid.
+	if !atom.ValidCategory(md.Category) {
+		return nil, fmt.Errorf("invalid CATEGORY %q", md.Category)
 	}
 
-	for _, name := range expectedArchives {
-		var found bool
-		for _, ext := range supportedCompressionExtensions {
-			archiveName := name + ".tar." + ext
-			if _, err := os.Stat(filepath.Join(dir, archiveName)); err != nil {
-				continue
-			}
-			found = true
-
-			// Extract the archive
-			if err := archive.Extract(archive.ExtractOptions{
-				Path: filepath.Join(dir, archiveName)}, dir,
-			); err != nil {
-				return nil, fmt.Errorf("failed to extract archive %s: %w", archiveName, err)
-			}
-
-			// Ensure we extracted to a directory with the same name as the archive.
-			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
-				return nil, fmt.Errorf("failed to extract archive %s: %w", archiveName, err)
-			}
-
-			// We're done.
-			break
-		}
-		if !found {
-			return nil, fmt.Errorf("package missing required archive: %s", name)
-		}
-	}
-
-	mf, err := metadataFromDir(filepath.Join(dir, "metadata"))
+	// Calculate name, version and revision from PF.
+	name, v, err := atom.ParsePF(md.PF)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create manifest from directory: %w", err)
+		return nil, fmt.Errorf("failed to parse PF: %w", err)
 	}
-
-	// Create manifest from the extracted manifest.
-	return &Package{
-		Metadata: *mf,
-	}, nil
+	md.Name = name
+	md.Version = v.PV()
+	md.Revision = v.PR()
+	return md, nil
 }
@@ -1,11 +1,25 @@
 package packages_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/packages/packagestest"
+	"github.com/jaredallard/binhost/internal/parser"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/crypto/blake2b"
 	"gotest.tools/v3/assert"
 )
 
@@ -21,4 +35,339 @@ func TestCanParseGpkg(t *testing.T) {
 	// Check that one field is set. Maybe one day check them all (I'm
 	// lazy)
 	assert.Equal(t, "onepassword-cli-0", pkg.PF)
+	assert.Equal(t, packages.FormatGpkg, pkg.Format)
+	assert.Equal(t, "onepassword-cli", pkg.Name)
+	assert.Equal(t, "0", pkg.Version)
+	assert.Equal(t, "", pkg.Revision)
+}
+
+func TestKeepsOriginalArchive(t *testing.T) {
+	orig, err := os.ReadFile("testdata/onepassword-cli-0-1.gpkg.tar")
+	assert.NilError(t, err)
+
+	f, err := os.Open("testdata/onepassword-cli-0-1.gpkg.tar")
+	assert.NilError(t, err)
+	defer f.Close()
+
+	pkg, err := packages.New(f)
+	assert.NilError(t, err)
+	defer pkg.Delete()
+
+	af, err := pkg.Archive()
+	assert.NilError(t, err)
+	defer af.Close()
+
+	b, err := io.ReadAll(af)
+	assert.NilError(t, err)
+	assert.Assert(t, bytes.Equal(orig, b), "archive should match the original gpkg")
+}
+
+// rewriteGpkg returns a copy of the test gpkg with the contents of the
+// file with the provided base name replaced by the output of fn.
+func rewriteGpkg(t *testing.T, name string, fn func([]byte) []byte) io.Reader {
+	f, err := os.Open("testdata/onepassword-cli-0-1.gpkg.tar")
+	assert.NilError(t, err)
+	defer f.Close()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tr := tar.NewReader(f)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NilError(t, err)
+
+		b, err := io.ReadAll(tr)
+		assert.NilError(t, err)
+
+		if path.Base(h.Name) == name {
+			b = fn(b)
+			h.Size = int64(len(b))
+		}
+
+		assert.NilError(t, tw.WriteHeader(h))
+		_, err = tw.Write(b)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+
+	return &buf
+}
+
+func TestRejectsTamperedGpkg(t *testing.T) {
+	_, err := packages.New(rewriteGpkg(t, "image.tar.xz", func(b []byte) []byte {
+		b[len(b)-1] ^= 0xff
+		return b
+	}))
+
+	var merr *packages.ManifestError
+	assert.Assert(t, errors.As(err, &merr), "expected a ManifestError, got %v", err)
+	assert.Equal(t, "image.tar.xz", merr.File)
+}
+
+func TestRejectsTruncatedGpkg(t *testing.T) {
+	_, err := packages.New(rewriteGpkg(t, "image.tar.xz", func(b []byte) []byte {
+		return b[:len(b)/2]
+	}))
+	assert.ErrorContains(t, err, "manifest verification failed for image.tar.xz: size mismatch")
+}
+
+func TestRequiresBlake2bInManifest(t *testing.T) {
+	_, err := packages.New(rewriteGpkg(t, "Manifest", func(b []byte) []byte {
+		return regexp.MustCompile(` BLAKE2B [0-9a-f]+`).ReplaceAll(b, nil)
+	}))
+
+	var merr *packages.ManifestError
+	assert.Assert(t, errors.As(err, &merr), "expected a ManifestError, got %v", err)
+	assert.Equal(t, "no BLAKE2B hash", merr.Reason)
+}
+
+func TestVerifiesSHA512InManifest(t *testing.T) {
+	_, err := packages.New(rewriteGpkg(t, "Manifest", func(b []byte) []byte {
+		re := regexp.MustCompile(`(DATA image\.tar\.xz \d+ BLAKE2B [0-9a-f]+ SHA512 )[0-9a-f]+`)
+		return re.ReplaceAll(b, []byte("${1}"+strings.Repeat("0", 128)))
+	}))
+
+	var merr *packages.ManifestError
+	assert.Assert(t, errors.As(err, &merr), "expected a ManifestError, got %v", err)
+	assert.Equal(t, "image.tar.xz", merr.File)
+	assert.Equal(t, "SHA512 mismatch", merr.Reason)
+}
+
+func TestRejectsMembersNotInManifest(t *testing.T) {
+	_, err := packages.New(rewriteGpkg(t, "Manifest", func(b []byte) []byte {
+		return regexp.MustCompile(`DATA image\.tar\.xz\.sig .*\n`).ReplaceAll(b, nil)
+	}))
+
+	var merr *packages.ManifestError
+	assert.Assert(t, errors.As(err, &merr), "expected a ManifestError, got %v", err)
+	assert.Equal(t, "image.tar.xz.sig", merr.File)
+	assert.Equal(t, "not listed in Manifest", merr.Reason)
+}
+
+// zeros is an [io.Reader] that returns an endless stream of zeros.
+type zeros struct{}
+
+// Read implements the io.Reader interface.
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestRejectsOversizedMetadata(t *testing.T) {
+	const size = 64*1024*1024 + 1
+
+	// Stream the gpkg so that the test doesn't hold the oversized
+	// metadata archive in memory.
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := tw.WriteHeader(&tar.Header{
+			Name: "foo-1.0/metadata.tar.zst", Typeflag: tar.TypeReg, Mode: 0o644, Size: size,
+		})
+		if err == nil {
+			_, err = io.Copy(tw, io.LimitReader(zeros{}, size))
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	_, err := packages.New(pr)
+	assert.ErrorContains(t, err, "failed to read metadata.tar.zst: exceeds maximum size of 67108864 bytes")
+}
+
+func TestCalculatesChecksums(t *testing.T) {
+	orig, err := os.ReadFile("testdata/onepassword-cli-0-1.gpkg.tar")
+	assert.NilError(t, err)
+
+	pkg, err := packages.New(bytes.NewReader(orig))
+	assert.NilError(t, err)
+	defer pkg.Delete()
+
+	sha512sum := sha512.Sum512(orig)
+	assert.Equal(t, int64(len(orig)), pkg.Checksums.Size)
+	assert.Equal(t, hex.EncodeToString(sha512sum[:]), pkg.Checksums.SHA512)
+	assert.Equal(t, 40, len(pkg.Checksums.SHA1))
+	assert.Equal(t, 32, len(pkg.Checksums.MD5))
+	assert.Equal(t, 128, len(pkg.Checksums.BLAKE2B))
+}
+
+// buildGpkg creates a minimal, unsigned gpkg for the provided package
+// with its archives compressed using zstd.
+func buildGpkg(t *testing.T, category, pf string) []byte {
+	tarball := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		zw, err := zstd.NewWriter(&buf)
+		assert.NilError(t, err)
+		tw := tar.NewWriter(zw)
+		for name, body := range files {
+			assert.NilError(t, tw.WriteHeader(&tar.Header{
+				Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(body)),
+			}))
+			_, err := tw.Write([]byte(body))
+			assert.NilError(t, err)
+		}
+		assert.NilError(t, tw.Close())
+		assert.NilError(t, zw.Close())
+		return buf.Bytes()
+	}
+
+	members := []struct {
+		name string
+		body []byte
+	}{
+		{"gpkg-1", nil},
+		{"metadata.tar.zst", tarball(map[string]string{
+			"metadata/CATEGORY": category + "\n",
+			"metadata/PF":       pf + "\n",
+		})},
+		{"image.tar.zst", tarball(map[string]string{"image/usr/bin/foo": "foo"})},
+	}
+
+	var manifest strings.Builder
+	for _, m := range members {
+		b2 := blake2b.Sum512(m.body)
+		s512 := sha512.Sum512(m.body)
+		fmt.Fprintf(&manifest, "DATA %s %d BLAKE2B %s SHA512 %s\n",
+			m.name, len(m.body), hex.EncodeToString(b2[:]), hex.EncodeToString(s512[:]))
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	write := func(name string, body []byte) {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{
+			Name: pf + "/" + name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(body)),
+		}))
+		_, err := tw.Write(body)
+		assert.NilError(t, err)
+	}
+	for _, m := range members {
+		write(m.name, m.body)
+	}
+	write("Manifest", []byte(manifest.String()))
+	assert.NilError(t, tw.Close())
+
+	return buf.Bytes()
+}
+
+func TestCanParseZstdGpkg(t *testing.T) {
+	pkg, err := packages.New(bytes.NewReader(buildGpkg(t, "dev-lang", "go-1.22.0-r1")))
+	assert.NilError(t, err)
+	defer pkg.Delete()
+
+	assert.Equal(t, "dev-lang", pkg.Category)
+	assert.Equal(t, "go", pkg.Name)
+	assert.Equal(t, "1.22.0", pkg.Version)
+	assert.Equal(t, "r1", pkg.Revision)
+}
+
+func TestCanParseXpak(t *testing.T) {
+	b := packagestest.BuildXpak([]byte("BZh91AY&SY not really a bzip2 image"), map[string]string{
+		"CATEGORY": "app-misc\n",
+		"PF":       "foo-bar-1.0_p2-r3\n",
+		"BUILD_ID": "4\n",
+		"SLOT":     "0\n",
+	})
+
+	pkg, err := packages.New(bytes.NewReader(b))
+	assert.NilError(t, err)
+	defer pkg.Delete()
+
+	assert.Equal(t, packages.FormatXpak, pkg.Format)
+	assert.Equal(t, "app-misc", pkg.Category)
+	assert.Equal(t, "foo-bar", pkg.Name)
+	assert.Equal(t, "1.0_p2", pkg.Version)
+	assert.Equal(t, "r3", pkg.Revision)
+	assert.Equal(t, "4", pkg.BuildID)
+	assert.Equal(t, "0", pkg.Slot)
+	assert.Equal(t, int64(len(b)), pkg.Checksums.Size)
+}
+
+func TestRejectsInvalidXpak(t *testing.T) {
+	_, err := packages.New(strings.NewReader("definitely not a binary package"))
+	assert.ErrorContains(t, err, "does not contain XPAK data")
+
+	b := packagestest.BuildXpak(nil, map[string]string{"PF": "foo-1.0"})
+	b[len(b)-9] = 'X' // Corrupt XPAKSTOP.
+	_, err = packages.New(bytes.NewReader(b))
+	assert.ErrorContains(t, err, "invalid XPAK segment markers")
+}
+
+func TestRejectsInvalidCategory(t *testing.T) {
+	for _, category := range []string{"", "../00000000-0000-0000-0000-000000000000/app-misc", "app-misc/foo", ".hidden"} {
+		b := packagestest.BuildXpak(nil, map[string]string{"CATEGORY": category + "\n", "PF": "foo-1.0\n"})
+		_, err := packages.New(bytes.NewReader(b))
+		assert.ErrorContains(t, err, "invalid CATEGORY", "category %q", category)
+	}
+}
+
+// indexEntry returns a Packages index entry for the package archive at
+// path with its size and BLAKE2B checksum.
+func indexEntry(t *testing.T, path, cpv string) *parser.Package {
+	b, err := os.ReadFile(path)
+	assert.NilError(t, err)
+
+	sum := blake2b.Sum512(b)
+	entry := &parser.Package{CPV: cpv, Format: "gpkg", BLAKE2B: hex.EncodeToString(sum[:])}
+	entry.Size = len(b)
+	return entry
+}
+
+func TestCanCreatePackageFromIndex(t *testing.T) {
+	entry := indexEntry(t, "testdata/onepassword-cli-0-1.gpkg.tar", "app-misc/foo-bar-1.2.3-r1")
+	entry.BuildID = "2"
+	entry.Slot = "0"
+
+	pkg, err := packages.FromIndex("testdata/onepassword-cli-0-1.gpkg.tar", entry)
+	assert.NilError(t, err)
+	assert.Equal(t, "app-misc", pkg.Category)
+	assert.Equal(t, "foo-bar", pkg.Name)
+	assert.Equal(t, "1.2.3", pkg.Version)
+	assert.Equal(t, "r1", pkg.Revision)
+	assert.Equal(t, "2", pkg.BuildID)
+	assert.Equal(t, "0", pkg.Slot)
+	assert.Equal(t, 0, pkg.Size)
+	assert.Equal(t, packages.FormatGpkg, pkg.Format)
+
+	info, err := os.Stat("testdata/onepassword-cli-0-1.gpkg.tar")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Size(), pkg.Checksums.Size)
+
+	// The archive isn't a copy, so it must not be deleted.
+	assert.NilError(t, pkg.Delete())
+	_, err = os.Stat("testdata/onepassword-cli-0-1.gpkg.tar")
+	assert.NilError(t, err)
+}
+
+func TestFromIndexVerifiesChecksums(t *testing.T) {
+	const path = "testdata/onepassword-cli-0-1.gpkg.tar"
+
+	entry := indexEntry(t, path, "app-misc/foo-1.0")
+	entry.Size++
+	_, err := packages.FromIndex(path, entry)
+	assert.ErrorContains(t, err, "size mismatch with Packages index")
+
+	entry = indexEntry(t, path, "app-misc/foo-1.0")
+	entry.BLAKE2B = strings.Repeat("0", 128)
+	_, err = packages.FromIndex(path, entry)
+	assert.ErrorContains(t, err, "BLAKE2B mismatch with Packages index")
+
+	entry = indexEntry(t, path, "app-misc/foo-1.0")
+	entry.MD5 = strings.Repeat("0", 32)
+	_, err = packages.FromIndex(path, entry)
+	assert.ErrorContains(t, err, "MD5 mismatch with Packages index")
+
+	entry = indexEntry(t, path, "app-misc/foo-1.0")
+	entry.BLAKE2B = ""
+	_, err = packages.FromIndex(path, entry)
+	assert.ErrorContains(t, err, "no checksums in Packages index entry")
+
+	entry = indexEntry(t, path, "../x/foo-1.0")
+	_, err = packages.FromIndex(path, entry)
+	assert.ErrorContains(t, err, "invalid category")
 }
@@ -0,0 +1,54 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package packagestest provides helpers for creating binary packages
+// in tests.
+package packagestest
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// BuildXpak creates a legacy XPAK package with the provided metadata
+// appended to image.
+func BuildXpak(image []byte, metadata map[string]string) []byte {
+	encodeInt := func(i int) []byte {
+		return binary.BigEndian.AppendUint32(nil, uint32(i))
+	}
+
+	var index, data bytes.Buffer
+	for name, value := range metadata {
+		index.Write(encodeInt(len(name)))
+		index.WriteString(name)
+		index.Write(encodeInt(data.Len()))
+		index.Write(encodeInt(len(value)))
+		data.WriteString(value)
+	}
+
+	var segment bytes.Buffer
+	segment.WriteString("XPAKPACK")
+	segment.Write(encodeInt(index.Len()))
+	segment.Write(encodeInt(data.Len()))
+	segment.Write(index.Bytes())
+	segment.Write(data.Bytes())
+	segment.WriteString("XPAKSTOP")
+
+	out := bytes.NewBuffer(image)
+	out.Write(segment.Bytes())
+	out.Write(encodeInt(segment.Len()))
+	out.WriteString("STOP")
+	return out.Bytes()
+}
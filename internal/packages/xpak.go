@@ -0,0 +1,110 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package packages
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// XPAK markers, see xpak(5).
+const (
+	xpakStart = "XPAKPACK"
+	xpakStop  = "XPAKSTOP"
+	xpakTail  = "STOP"
+)
+
+// readXpak reads the XPAK segment appended to the end of a legacy binary
+// package (tbz2) and returns the metadata stored in it keyed by name.
+//
+// The layout of the end of a tbz2 is:
+//
+//	XPAKPACK <index len> <data len> <index> <data> XPAKSTOP <xpak len> STOP
+//
+// where every length is a 4-byte big-endian integer and each index
+// entry is <name len> <name> <data offset> <data len>.
+func readXpak(r io.ReaderAt, size int64) (map[string][]byte, error) {
+	// <xpak len> STOP
+	if size < 8 {
+		return nil, fmt.Errorf("package is too small to contain XPAK data")
+	}
+
+	tail := make([]byte, 8)
+	if _, err := r.ReadAt(tail, size-8); err != nil {
+		return nil, fmt.Errorf("failed to read XPAK tail: %w", err)
+	}
+	if string(tail[4:]) != xpakTail {
+		return nil, fmt.Errorf("package does not contain XPAK data")
+	}
+
+	xpakLen := int64(binary.BigEndian.Uint32(tail[:4]))
+	if xpakLen < int64(len(xpakStart)+8+len(xpakStop)) || xpakLen > size-8 || xpakLen > maxMetadataSize {
+		return nil, fmt.Errorf("invalid XPAK length: %d", xpakLen)
+	}
+
+	segment := make([]byte, xpakLen)
+	if _, err := r.ReadAt(segment, size-8-xpakLen); err != nil {
+		return nil, fmt.Errorf("failed to read XPAK segment: %w", err)
+	}
+
+	return parseXpakSegment(segment)
+}
+
+// parseXpakSegment parses an XPAK segment, starting at XPAKPACK and
+// ending at XPAKSTOP.
+func parseXpakSegment(segment []byte) (map[string][]byte, error) {
+	if !bytes.HasPrefix(segment, []byte(xpakStart)) || !bytes.HasSuffix(segment, []byte(xpakStop)) {
+		return nil, fmt.Errorf("invalid XPAK segment markers")
+	}
+	body := segment[len(xpakStart) : len(segment)-len(xpakStop)]
+	if len(body) < 8 {
+		return nil, fmt.Errorf("invalid XPAK segment: too short")
+	}
+
+	indexLen := uint64(binary.BigEndian.Uint32(body[0:4]))
+	dataLen := uint64(binary.BigEndian.Uint32(body[4:8]))
+	body = body[8:]
+	if indexLen+dataLen != uint64(len(body)) {
+		return nil, fmt.Errorf("invalid XPAK segment: index and data lengths don't match segment")
+	}
+	index, data := body[:indexLen], body[indexLen:]
+
+	out := make(map[string][]byte)
+	for len(index) > 0 {
+		if len(index) < 4 {
+			return nil, fmt.Errorf("invalid XPAK index: truncated entry")
+		}
+		nameLen := uint64(binary.BigEndian.Uint32(index[0:4]))
+		index = index[4:]
+
+		if uint64(len(index)) < nameLen+8 {
+			return nil, fmt.Errorf("invalid XPAK index: truncated entry")
+		}
+		name := string(index[:nameLen])
+		offset := uint64(binary.BigEndian.Uint32(index[nameLen : nameLen+4]))
+		length := uint64(binary.BigEndian.Uint32(index[nameLen+4 : nameLen+8]))
+		index = index[nameLen+8:]
+
+		if offset+length > uint64(len(data)) {
+			return nil, fmt.Errorf("invalid XPAK index: %s points outside of data", name)
+		}
+		out[name] = data[offset : offset+length]
+	}
+
+	return out, nil
+}
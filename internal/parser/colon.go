@@ -28,7 +28,6 @@ import (
 // the colon format. v must be a pointer to a struct.
 func encodeColonFormat(w io.Writer, v any) error {
 	var prve reflect.Value
-	var prt reflect.Type
 	{ // Make sure we don't accidentally use prv since it is unsafe.
 		prv := reflect.ValueOf(v)
 		if prv.Kind() != reflect.Ptr {
@@ -36,13 +35,36 @@ func encodeColonFormat(w io.Writer, v any) error {
 		}
 
 		prve = prv.Elem()
-		prt = prve.Type()
 	}
 
+	if err := encodeColonFields(w, prve); err != nil {
+		return err
+	}
+
+	// Write a newline to end the document.
+	if _, err := w.Write([]byte("\n")); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// encodeColonFields writes all fields of the provided struct value that
+// have a colon tag into the writer. Embedded structs are encoded as if
+// their fields were declared on the parent struct.
+func encodeColonFields(w io.Writer, prve reflect.Value) error {
+	prt := prve.Type()
 	for i := 0; i < prve.NumField(); i++ {
 		fv := prve.Field(i)
 		ft := prt.Field(i)
 
+		if ft.Anonymous && fv.Kind() == reflect.Struct {
+			if err := encodeColonFields(w, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
 		value := fv.Interface()
 		if value == nil || fv.IsZero() {
 			// Don't encode non-existent fields or fields with the zero value.
@@ -69,11 +91,6 @@ func encodeColonFormat(w io.Writer, v any) error {
 		}
 	}
 
-	// Write a newline to end the document.
-	if _, err := w.Write([]byte("\n")); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -132,15 +149,8 @@ func decodeColonFormat(doc map[string]string, v any) error {
 
 	// Create a map of the colon struct tags into the field index for
 	// iteration later.
-	tagToField := make(map[string]int)
-	for i := 0; i < vrt.NumField(); i++ {
-		tag := vrt.Field(i).Tag.Get("colon")
-		if tag == "" {
-			continue
-		}
-
-		tagToField[tag] = i
-	}
+	tagToField := make(map[string][]int)
+	colonFieldIndexes(vrt, nil, tagToField)
 
 	// Set the fields from the document
 	for k, v := range doc {
@@ -149,7 +159,7 @@ func decodeColonFormat(doc map[string]string, v any) error {
 			return fmt.Errorf("unknown field: %s", k)
 		}
 
-		field := vrv.Field(fieldIndex)
+		field := vrv.FieldByIndex(fieldIndex)
 		switch field.Kind() {
 		case reflect.String:
 			field.SetString(v)
@@ -177,3 +187,29 @@ func decodeColonFormat(doc map[string]string, v any) error {
 
 	return nil
 }
+
+// colonFieldIndexes populates out with the colon tag of every field in
+// the provided struct type, mapped to the index path of the field.
+// Fields of embedded structs are included as if they were declared on
+// the parent struct.
+func colonFieldIndexes(t reflect.Type, parent []int, out map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		index := make([]int, len(parent)+1)
+		copy(index, parent)
+		index[len(parent)] = i
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			colonFieldIndexes(f.Type, index, out)
+			continue
+		}
+
+		tag := f.Tag.Get("colon")
+		if tag == "" {
+			continue
+		}
+
+		out[tag] = index
+	}
+}
@@ -23,37 +23,46 @@ import (
 
 // Index is a binhost index file (Packages).
 type Index struct {
-	AcceptLicense         string   `colon:"ACCEPT_LICENSE"`
-	AcceptProperties      string   `colon:"ACCEPT_PROPERTIES"`
-	AcceptRestrict        string   `colon:"ACCEPT_RESTRICT"`
-	AcceptKeywords        []string `colon:"ACCEPT_KEYWORDS"`
-	Arch                  string   `colon:"ARCH"`
-	CBuild                string   `colon:"CBUILD"`
-	CHost                 string   `colon:"CHOST"`
-	ConfigProtect         []string `colon:"CONFIG_PROTECT"`
-	ConfigProtectMask     []string `colon:"CONFIG_PROTECT_MASK"`
-	ELibc                 string   `colon:"ELIBC"`
-	Features              []string `colon:"FEATURES"`
-	GentooMirrors         string   `colon:"GENTOO_MIRRORS"`
-	IUseImplicit          string   `colon:"IUSE_IMPLICIT"`
-	Kernel                string   `colon:"KERNEL"`
-	Packages              int      `colon:"PACKAGES"`
-	Profile               string   `colon:"PROFILE"`
-	Timestamp             int      `colon:"TIMESTAMP"`
-	Use                   string   `colon:"USE"`
-	UseExpand             []string `colon:"USE_EXPAND"`
-	UseExpandHidden       []string `colon:"USE_EXPAND_HIDDEN"`
-	UseExpandImplicit     []string `colon:"USE_EXPAND_IMPLICIT"`
-	UseExpandUnprefixed   []string `colon:"USE_EXPAND_UNPREFIXED"`
-	UseExpandValuesArch   []string `colon:"USE_EXPAND_VALUES_ARCH"`
-	UseExpandValuesELibc  []string `colon:"USE_EXPAND_VALUES_ELIBC"`
-	UseExpandValuesKernel []string `colon:"USE_EXPAND_VALUES_KERNEL"`
-	Version               int      `colon:"VERSION"`
+	Profile
+
+	Packages  int `colon:"PACKAGES"`
+	Timestamp int `colon:"TIMESTAMP"`
+	Version   int `colon:"VERSION"`
 
 	// PackageEntries is a slice of packages contained within the index.
 	PackageEntries []Package
 }
 
+// Profile is the configuration of the system that the packages in an
+// index were built for. It makes up most of the header of a Packages
+// index, which Portage uses to determine if the packages are
+// compatible with the system consuming them.
+type Profile struct {
+	AcceptLicense         string   `colon:"ACCEPT_LICENSE" json:"ACCEPT_LICENSE,omitempty"`
+	AcceptProperties      string   `colon:"ACCEPT_PROPERTIES" json:"ACCEPT_PROPERTIES,omitempty"`
+	AcceptRestrict        string   `colon:"ACCEPT_RESTRICT" json:"ACCEPT_RESTRICT,omitempty"`
+	AcceptKeywords        []string `colon:"ACCEPT_KEYWORDS" json:"ACCEPT_KEYWORDS,omitempty"`
+	Arch                  string   `colon:"ARCH" json:"ARCH,omitempty"`
+	CBuild                string   `colon:"CBUILD" json:"CBUILD,omitempty"`
+	CHost                 string   `colon:"CHOST" json:"CHOST,omitempty"`
+	ConfigProtect         []string `colon:"CONFIG_PROTECT" json:"CONFIG_PROTECT,omitempty"`
+	ConfigProtectMask     []string `colon:"CONFIG_PROTECT_MASK" json:"CONFIG_PROTECT_MASK,omitempty"`
+	ELibc                 string   `colon:"ELIBC" json:"ELIBC,omitempty"`
+	Features              []string `colon:"FEATURES" json:"FEATURES,omitempty"`
+	GentooMirrors         string   `colon:"GENTOO_MIRRORS" json:"GENTOO_MIRRORS,omitempty"`
+	IUseImplicit          string   `colon:"IUSE_IMPLICIT" json:"IUSE_IMPLICIT,omitempty"`
+	Kernel                string   `colon:"KERNEL" json:"KERNEL,omitempty"`
+	Profile               string   `colon:"PROFILE" json:"PROFILE,omitempty"`
+	Use                   string   `colon:"USE" json:"USE,omitempty"`
+	UseExpand             []string `colon:"USE_EXPAND" json:"USE_EXPAND,omitempty"`
+	UseExpandHidden       []string `colon:"USE_EXPAND_HIDDEN" json:"USE_EXPAND_HIDDEN,omitempty"`
+	UseExpandImplicit     []string `colon:"USE_EXPAND_IMPLICIT" json:"USE_EXPAND_IMPLICIT,omitempty"`
+	UseExpandUnprefixed   []string `colon:"USE_EXPAND_UNPREFIXED" json:"USE_EXPAND_UNPREFIXED,omitempty"`
+	UseExpandValuesArch   []string `colon:"USE_EXPAND_VALUES_ARCH" json:"USE_EXPAND_VALUES_ARCH,omitempty"`
+	UseExpandValuesELibc  []string `colon:"USE_EXPAND_VALUES_ELIBC" json:"USE_EXPAND_VALUES_ELIBC,omitempty"`
+	UseExpandValuesKernel []string `colon:"USE_EXPAND_VALUES_KERNEL" json:"USE_EXPAND_VALUES_KERNEL,omitempty"`
+}
+
 // EncodeInto serializes the packages into the given writer using the
 // standard Packages format.
 //
@@ -103,9 +112,12 @@ type Package struct {
 	PackageCommon
 
 	CPV          string `colon:"CPV"`
+	Format       string `colon:"BINPKG_FORMAT"`
 	Path         string `colon:"PATH"`
 	SHA1         string `colon:"SHA1"`
 	MD5          string `colon:"MD5"`
+	BLAKE2B      string `colon:"BLAKE2B"`
+	SHA512       string `colon:"SHA512"`
 	ModifiedTime int    `colon:"MTIME"`
 }
 
@@ -115,6 +127,21 @@ func (pkg *Package) EncodeInto(w io.Writer) error {
 	return encodeColonFormat(w, pkg)
 }
 
+// FilePath returns the path of the package archive, relative to the
+// PKGDIR the package is listed in. Portage omits PATH for packages
+// stored at the default location. Packages without a BINPKG_FORMAT
+// predate gpkg and are XPAK packages.
+func (pkg *Package) FilePath() string {
+	if pkg.Path != "" {
+		return pkg.Path
+	}
+
+	if pkg.Format == "gpkg" {
+		return pkg.CPV + ".gpkg.tar"
+	}
+	return pkg.CPV + ".tbz2"
+}
+
 // ParsePackages parses the provided reader into a the Index type.
 func ParsePackages(r io.Reader) (*Index, error) {
 	docs, err := parseColonDocuments(r)
@@ -31,7 +31,7 @@ func TestCanReadPackages(t *testing.T) {
 
 func TestCanEncodePackages(t *testing.T) {
 	index := parser.Index{
-		Arch: "arm64",
+		Profile: parser.Profile{Arch: "arm64"},
 		PackageEntries: []parser.Package{
 			{
 				CPV: "x11-terms/alacritty-0.12.3",
@@ -43,3 +43,49 @@ func TestCanEncodePackages(t *testing.T) {
 	assert.NilError(t, index.EncodeInto(&buf))
 	assert.Equal(t, "ARCH: arm64\n\nCPV: x11-terms/alacritty-0.12.3\n\n", buf.String())
 }
+
+func TestCanEncodeIndexProfile(t *testing.T) {
+	index := parser.Index{
+		Profile: parser.Profile{
+			Arch:           "amd64",
+			CHost:          "x86_64-pc-linux-gnu",
+			AcceptKeywords: []string{"amd64", "~amd64"},
+		},
+		Packages:  0,
+		Timestamp: 1700000000,
+	}
+
+	var buf bytes.Buffer
+	assert.NilError(t, index.EncodeInto(&buf))
+	assert.Equal(t, "ACCEPT_KEYWORDS: amd64 ~amd64\nARCH: amd64\nCHOST: x86_64-pc-linux-gnu\nTIMESTAMP: 1700000000\n\n", buf.String())
+
+	decoded, err := parser.ParsePackages(&buf)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, index.Profile, decoded.Profile)
+}
+
+func TestCanRoundTripPackageCommonFields(t *testing.T) {
+	pkg := parser.Package{
+		PackageCommon: parser.PackageCommon{
+			BuildID: "1",
+			Slot:    "0",
+		},
+		CPV: "x11-terms/alacritty-0.12.3",
+	}
+
+	var buf bytes.Buffer
+	assert.NilError(t, pkg.EncodeInto(&buf))
+	assert.Equal(t, "BUILD_ID: 1\nSLOT: 0\nCPV: x11-terms/alacritty-0.12.3\n\n", buf.String())
+
+	index, err := parser.ParsePackages(strings.NewReader("\n" + buf.String()))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(index.PackageEntries))
+	assert.DeepEqual(t, pkg, index.PackageEntries[0])
+}
+
+func TestPackageFilePath(t *testing.T) {
+	assert.Equal(t, "app-misc/foo/foo-1.0-1.gpkg.tar", (&parser.Package{CPV: "app-misc/foo-1.0", Path: "app-misc/foo/foo-1.0-1.gpkg.tar"}).FilePath())
+	assert.Equal(t, "app-misc/foo-1.0.gpkg.tar", (&parser.Package{CPV: "app-misc/foo-1.0", Format: "gpkg"}).FilePath())
+	assert.Equal(t, "app-misc/foo-1.0.tbz2", (&parser.Package{CPV: "app-misc/foo-1.0", Format: "xpak"}).FilePath())
+	assert.Equal(t, "app-misc/foo-1.0.tbz2", (&parser.Package{CPV: "app-misc/foo-1.0"}).FilePath())
+}
@@ -0,0 +1,293 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package push uploads the packages in a local PKGDIR that a binhost
+// server doesn't have yet.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/client"
+	"github.com/jaredallard/binhost/internal/parser"
+)
+
+// Action is what is done with a local package.
+type Action int
+
+const (
+	// ActionUpload means the package is missing on the server and is
+	// uploaded.
+	ActionUpload Action = iota
+
+	// ActionSkip means the server already has the package.
+	ActionSkip
+
+	// ActionConflict means the server has a different package with the
+	// same CPV and BUILD_ID, so the package can't be uploaded.
+	ActionConflict
+)
+
+// Item is a package in the local PKGDIR and what is done with it.
+type Item struct {
+	parser.Package
+	Action Action
+}
+
+// Plan decides what to do with each of the local packages based on the
+// packages the server already has.
+//
+// A package is considered present on the server if the server has a
+// package with the same CPV and checksum, regardless of its BUILD_ID,
+// since the server assigns BUILD_IDs to packages that don't have one.
+func Plan(local, remote []parser.Package) []Item {
+	byCPV := make(map[string][]parser.Package, len(remote))
+	for _, p := range remote {
+		byCPV[p.CPV] = append(byCPV[p.CPV], p)
+	}
+
+	items := make([]Item, 0, len(local))
+	for _, p := range local {
+		action := ActionUpload
+		for _, r := range byCPV[p.CPV] {
+			if sameFile(p, r) {
+				action = ActionSkip
+				break
+			}
+			if p.BuildID != "" && p.BuildID == r.BuildID {
+				action = ActionConflict
+			}
+		}
+		items = append(items, Item{p, action})
+	}
+	return items
+}
+
+// sameFile returns true if a and b describe the same package archive,
+// comparing the strongest checksum both of them have. Packages without
+// a common checksum are compared by size and BUILD_ID.
+func sameFile(a, b parser.Package) bool {
+	for _, sums := range [][2]string{
+		{a.BLAKE2B, b.BLAKE2B},
+		{a.SHA512, b.SHA512},
+		{a.SHA1, b.SHA1},
+		{a.MD5, b.MD5},
+	} {
+		if sums[0] != "" && sums[1] != "" {
+			return strings.EqualFold(sums[0], sums[1])
+		}
+	}
+
+	return a.Size == b.Size && a.BuildID == b.BuildID
+}
+
+// Options configures Push.
+type Options struct {
+	// Target is the target to push to. If empty, the CHOST of the local
+	// Packages index is used as the target and packages are uploaded to
+	// the target named after their CHOST (POST /v1/upload), so that the
+	// server can create it.
+	Target string
+
+	// Jobs is the number of packages uploaded concurrently.
+	Jobs int
+
+	// Retries is the number of times a failed upload is retried.
+	Retries int
+
+	// RetryDelay is the delay before the first retry. It doubles after
+	// every retry.
+	RetryDelay time.Duration
+
+	// DryRun only plans the push without uploading anything.
+	DryRun bool
+
+	// Log is the logger to log progress to. Defaults to
+	// [slog.Default].
+	Log *slog.Logger
+}
+
+// Result is the result of a push.
+type Result struct {
+	// Target is the target that was pushed to.
+	Target string
+
+	// Items are the local packages and what was done with them. In dry
+	// run mode, packages to upload are not uploaded.
+	Items []Item
+
+	// Uploaded are the responses of the uploaded packages.
+	Uploaded []*api.UploadResponse
+
+	// Failed contains the errors of packages that failed to upload or
+	// conflict with packages on the server, by their local path.
+	Failed map[string]error
+}
+
+// Push uploads the packages listed in the Packages file of the
+// provided PKGDIR that the server doesn't have yet.
+func Push(ctx context.Context, c *client.Client, pkgdir string, opts Options) (*Result, error) {
+	if opts.Log == nil {
+		opts.Log = slog.Default()
+	}
+
+	f, err := os.Open(filepath.Join(pkgdir, "Packages"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Packages: %w", err)
+	}
+	defer f.Close()
+
+	local, err := parser.ParsePackages(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Packages: %w", err)
+	}
+	if local == nil {
+		local = &parser.Index{}
+	}
+
+	res := &Result{Target: opts.Target, Failed: make(map[string]error)}
+	if res.Target == "" {
+		if local.CHost == "" {
+			return nil, errors.New("no target provided and Packages has no CHOST")
+		}
+		res.Target = local.CHost
+	}
+
+	remote, err := c.Index(ctx, res.Target)
+	if err != nil {
+		if !client.IsCode(err, "target_not_found") || opts.Target != "" {
+			return nil, fmt.Errorf("failed to get index of target %s: %w", res.Target, err)
+		}
+
+		// The target may be created by the upload.
+		remote = &parser.Index{}
+	}
+
+	res.Items = Plan(local.PackageEntries, remote.PackageEntries)
+
+	var uploads []*Item
+	for i := range res.Items {
+		item := &res.Items[i]
+		switch item.Action {
+		case ActionUpload:
+			uploads = append(uploads, item)
+		case ActionConflict:
+			res.Failed[item.FilePath()] = fmt.Errorf("target has a different package with BUILD_ID %s", item.BuildID)
+		}
+	}
+	if opts.DryRun || len(uploads) == 0 {
+		return res, nil
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		work = make(chan *Item)
+	)
+	for range max(opts.Jobs, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				path := item.FilePath()
+				resp, err := upload(ctx, c, filepath.Join(pkgdir, path), opts)
+
+				mu.Lock()
+				if err != nil {
+					opts.Log.Error("failed to upload package", "path", path, "error", err)
+					res.Failed[path] = err
+				} else {
+					opts.Log.Info("uploaded package", "cpv", resp.CPV, "build_id", resp.BuildID, "target", resp.Target)
+					res.Uploaded = append(res.Uploaded, resp)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, item := range uploads {
+		select {
+		case work <- item:
+		case <-ctx.Done():
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return res, ctx.Err()
+}
+
+// upload uploads the package at path to the server, retrying failures
+// that may be transient.
+func upload(ctx context.Context, c *client.Client, path string, opts Options) (*api.UploadResponse, error) {
+	target := opts.Target
+	delay := opts.RetryDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := uploadFile(ctx, c, target, path)
+		if err == nil || attempt >= opts.Retries || !retryable(err) {
+			return resp, err
+		}
+
+		opts.Log.Warn("retrying upload", "path", path, "error", err, "attempt", attempt+1)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// uploadFile uploads the package at path to the server.
+func uploadFile(ctx context.Context, c *client.Client, target, path string) (*api.UploadResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open package: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat package: %w", err)
+	}
+
+	return c.Upload(ctx, target, f, info.Size())
+}
+
+// retryable returns true if the provided upload error may be transient,
+// i.e., it is a server or network error.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var e *client.Error
+	if errors.As(err, &e) {
+		return e.Status >= http.StatusInternalServerError || e.Status == http.StatusTooManyRequests
+	}
+
+	// Errors opening the package aren't transient either.
+	return !errors.Is(err, os.ErrNotExist) && !errors.Is(err, os.ErrPermission)
+}
@@ -0,0 +1,124 @@
+package push_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jaredallard/binhost/internal/client"
+	"github.com/jaredallard/binhost/internal/parser"
+	"github.com/jaredallard/binhost/internal/push"
+	"gotest.tools/v3/assert"
+)
+
+func TestPlan(t *testing.T) {
+	pkg := func(cpv, buildID, sha1 string) parser.Package {
+		p := parser.Package{CPV: cpv, SHA1: sha1}
+		p.BuildID = buildID
+		return p
+	}
+
+	local := []parser.Package{
+		pkg("app-misc/present-1.0", "", "aaaa"),
+		pkg("app-misc/rebuilt-1.0", "", "bbbb"),
+		pkg("app-misc/conflict-1.0", "2", "cccc"),
+		pkg("app-misc/missing-1.0", "1", "dddd"),
+	}
+	remote := []parser.Package{
+		pkg("app-misc/present-1.0", "3", "AAAA"),
+		pkg("app-misc/rebuilt-1.0", "1", "ffff"),
+		pkg("app-misc/conflict-1.0", "2", "eeee"),
+	}
+
+	var actions []push.Action
+	for _, item := range push.Plan(local, remote) {
+		actions = append(actions, item.Action)
+	}
+	assert.DeepEqual(t, []push.Action{push.ActionSkip, push.ActionUpload, push.ActionConflict, push.ActionUpload}, actions)
+}
+
+// newPKGDIR creates a PKGDIR containing the provided packages, whose
+// archives contain their CPV.
+func newPKGDIR(t *testing.T, pkgs ...parser.Package) string {
+	dir := t.TempDir()
+
+	index := parser.Index{Profile: parser.Profile{CHost: "x86_64-pc-linux-gnu"}, PackageEntries: pkgs}
+	f, err := os.Create(filepath.Join(dir, "Packages"))
+	assert.NilError(t, err)
+	defer f.Close()
+	assert.NilError(t, index.EncodeInto(f))
+
+	for _, p := range pkgs {
+		path := filepath.Join(dir, p.FilePath())
+		assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NilError(t, os.WriteFile(path, []byte(p.CPV), 0o644))
+	}
+	return dir
+}
+
+func TestPushUploadsMissingPackages(t *testing.T) {
+	present := parser.Package{CPV: "app-misc/present-1.0", SHA1: "aaaa"}
+	missing := parser.Package{CPV: "app-misc/missing-1.0", SHA1: "bbbb"}
+	pkgdir := newPKGDIR(t, present, missing)
+
+	var attempts atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /t/x86_64-pc-linux-gnu/Packages", func(w http.ResponseWriter, _ *http.Request) {
+		index := parser.Index{PackageEntries: []parser.Package{present}}
+		index.EncodeInto(w) //nolint:errcheck // Why: Test server.
+	})
+	mux.HandleFunc("POST /v1/upload", func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt to test retries.
+		if attempts.Add(1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		b, err := io.ReadAll(r.Body)
+		assert.NilError(t, err)
+		assert.Equal(t, missing.CPV, string(b))
+
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"target":"x86_64-pc-linux-gnu","cpv":"app-misc/missing-1.0","build_id":1}`) //nolint:errcheck // Why: Test server.
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := client.New(srv.URL, "")
+	assert.NilError(t, err)
+
+	opts := push.Options{Jobs: 2, Retries: 1, RetryDelay: time.Millisecond, Log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	res, err := push.Push(context.Background(), c, pkgdir, opts)
+	assert.NilError(t, err)
+	assert.Equal(t, "x86_64-pc-linux-gnu", res.Target)
+	assert.Equal(t, 0, len(res.Failed))
+	assert.Equal(t, 1, len(res.Uploaded))
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestPushDryRunDoesNotUpload(t *testing.T) {
+	pkgdir := newPKGDIR(t, parser.Package{CPV: "app-misc/missing-1.0", SHA1: "bbbb"})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		http.Error(w, `{"error":{"code":"target_not_found","message":"target not found"}}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c, err := client.New(srv.URL, "")
+	assert.NilError(t, err)
+
+	res, err := push.Push(context.Background(), c, pkgdir, push.Options{DryRun: true})
+	assert.NilError(t, err)
+	assert.Equal(t, push.ActionUpload, res.Items[0].Action)
+	assert.Equal(t, 0, len(res.Uploaded))
+}
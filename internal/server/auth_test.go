@@ -0,0 +1,248 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/auth"
+	"github.com/jaredallard/binhost/internal/ent"
+	"gotest.tools/v3/assert"
+)
+
+// newTestToken creates a token with the provided scopes, limited to the
+// provided targets unless none are provided.
+func newTestToken(t *testing.T, s *Server, scopes []auth.Scope, targets ...*ent.Target) string {
+	tok, _, err := auth.CreateToken(context.Background(), s.deps.DB, "test", scopes, targets, nil)
+	assert.NilError(t, err)
+	return tok
+}
+
+// basicAuth returns an Authorization header value for HTTP Basic
+// authentication.
+func basicAuth(user, pass string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+}
+
+func TestRequireTargetAccess(t *testing.T) {
+	s, app := newTestServer(t)
+	private := newTestTarget(t, s, "private", true)
+	other := newTestTarget(t, s, "other", true)
+	newTestTarget(t, s, "public", false)
+	p := addTestPackage(t, s, private, newTestXpak("app-misc", "foo-1.0", nil))
+
+	readToken := newTestToken(t, s, []auth.Scope{auth.ScopeTargetRead}, private)
+	otherToken := newTestToken(t, s, []auth.Scope{auth.ScopeTargetRead}, other)
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		status        int
+	}{
+		{"public without credentials", "/t/public/Packages", "", fiber.StatusOK},
+		{"missing target", "/t/missing/Packages", "", fiber.StatusNotFound},
+		{"private without credentials", "/t/private/Packages", "", fiber.StatusUnauthorized},
+		{"private with bearer token", "/t/private/Packages", "Bearer " + readToken, fiber.StatusUnauthorized},
+		{"private with wrong token", "/t/private/Packages", basicAuth("binhost", "wrong"), fiber.StatusUnauthorized},
+		{"private with token for another target", "/t/private/Packages", basicAuth("binhost", otherToken), fiber.StatusNotFound},
+		{"private with token", "/t/private/Packages", basicAuth("binhost", readToken), fiber.StatusOK},
+		{"private with token as username", "/t/private/Packages", basicAuth(readToken, ""), fiber.StatusOK},
+		{"private package without credentials", "/t/private/" + p.Path, "", fiber.StatusUnauthorized},
+		{"private package with token", "/t/private/" + p.Path, basicAuth("binhost", readToken), fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.authorization)
+			}
+
+			resp := doRequest(t, app, req, "")
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status == fiber.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="binhost", charset="UTF-8"`, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+			}
+		})
+	}
+}
+
+func TestRequireTargetAccessFailsClosed(t *testing.T) {
+	s, app := newTestServer(t)
+	newTestTarget(t, s, "private", true)
+
+	// Fail the first target query, which is made by the middleware.
+	var queries int
+	s.deps.DB.Target.Intercept(ent.InterceptFunc(func(next ent.Querier) ent.Querier {
+		return ent.QuerierFunc(func(ctx context.Context, q ent.Query) (ent.Value, error) {
+			queries++
+			if queries == 1 {
+				return nil, errors.New("connection reset")
+			}
+			return next.Query(ctx, q)
+		})
+	}))
+
+	resp := doRequest(t, app, httptest.NewRequest(fiber.MethodGet, "/t/private/Packages", nil), "")
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestRequireScope(t *testing.T) {
+	s, app := newTestServer(t)
+	amd64 := newTestTarget(t, s, "amd64", false)
+	newTestTarget(t, s, "arm64", false)
+
+	readToken := newTestToken(t, s, []auth.Scope{auth.ScopeTargetRead})
+	writeToken := newTestToken(t, s, []auth.Scope{auth.ScopeTargetWrite})
+	amd64Token := newTestToken(t, s, []auth.Scope{auth.ScopeTargetWrite}, amd64)
+
+	expired := time.Now().Add(-time.Hour)
+	expiredToken, _, err := auth.CreateToken(context.Background(), s.deps.DB, "expired", []auth.Scope{auth.ScopeAdmin}, nil, &expired)
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{"without token", fiber.MethodGet, "/v1/targets/amd64/packages", "", fiber.StatusUnauthorized},
+		{"with invalid token", fiber.MethodGet, "/v1/targets/amd64/packages", "bh_invalid", fiber.StatusUnauthorized},
+		{"with expired token", fiber.MethodGet, "/v1/targets/amd64/packages", expiredToken, fiber.StatusUnauthorized},
+		{"read with read token", fiber.MethodGet, "/v1/targets/amd64/packages", readToken, fiber.StatusOK},
+		{"read with write token", fiber.MethodGet, "/v1/targets/arm64/packages", writeToken, fiber.StatusOK},
+		{"read with scoped token", fiber.MethodGet, "/v1/targets/amd64/packages", amd64Token, fiber.StatusOK},
+		{"read other target with scoped token", fiber.MethodGet, "/v1/targets/arm64/packages", amd64Token, fiber.StatusForbidden},
+		{"read missing target", fiber.MethodGet, "/v1/targets/missing/packages", readToken, fiber.StatusNotFound},
+		{"read missing target with scoped token", fiber.MethodGet, "/v1/targets/missing/packages", amd64Token, fiber.StatusForbidden},
+		{"list packages with scoped token", fiber.MethodGet, "/v1/packages", amd64Token, fiber.StatusOK},
+		{"delete with read token", fiber.MethodDelete, "/v1/targets/amd64/packages?pattern=app-misc/*", readToken, fiber.StatusForbidden},
+		{"admin with write token", fiber.MethodPost, "/v1/targets/new", writeToken, fiber.StatusForbidden},
+		{"list tokens with write token", fiber.MethodGet, "/v1/tokens", writeToken, fiber.StatusForbidden},
+		{"admin with admin token", fiber.MethodPost, "/v1/targets/new", testAdminToken, fiber.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doRequest(t, app, httptest.NewRequest(tt.method, tt.path, nil), tt.token)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status == fiber.StatusUnauthorized {
+				assert.Equal(t, "Bearer", resp.Header.Get(fiber.HeaderWWWAuthenticate))
+			}
+		})
+	}
+}
+
+func TestRequireScopeFailsClosed(t *testing.T) {
+	s, app := newTestServer(t)
+	newTestTarget(t, s, "amd64", false)
+
+	// Fail the first target query, which is made by the middleware. The
+	// admin token is used since authenticating it doesn't query the
+	// database.
+	var queries int
+	s.deps.DB.Target.Intercept(ent.InterceptFunc(func(next ent.Querier) ent.Querier {
+		return ent.QuerierFunc(func(ctx context.Context, q ent.Query) (ent.Value, error) {
+			queries++
+			if queries == 1 {
+				return nil, errors.New("connection reset")
+			}
+			return next.Query(ctx, q)
+		})
+	}))
+
+	resp := doRequest(t, app, httptest.NewRequest(fiber.MethodGet, "/v1/targets/amd64/packages", nil), testAdminToken)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+// createTestToken creates a token using the API.
+func createTestToken(t *testing.T, app *fiber.App, req *api.CreateTokenRequest) *http.Response {
+	b, err := json.Marshal(req)
+	assert.NilError(t, err)
+
+	r := httptest.NewRequest(fiber.MethodPost, "/v1/tokens", bytes.NewReader(b))
+	r.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return doRequest(t, app, r, testAdminToken)
+}
+
+func TestTokenLifecycle(t *testing.T) {
+	s, app := newTestServer(t)
+	newTestTarget(t, s, "amd64", false)
+	newTestTarget(t, s, "arm64", false)
+
+	// Targets listed more than once are only added once.
+	resp := createTestToken(t, app, &api.CreateTokenRequest{
+		Name: "ci", Scopes: []string{"target:write"}, Targets: []string{"amd64", "amd64"},
+	})
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	var created api.Token
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.Equal(t, "ci", created.Name)
+	assert.DeepEqual(t, []string{"target:write"}, created.Scopes)
+	assert.DeepEqual(t, []string{"amd64"}, created.Targets)
+	assert.Assert(t, created.Token != "")
+
+	// The token is limited to its target.
+	resp = doRequest(t, app, httptest.NewRequest(fiber.MethodGet, "/v1/targets/amd64/packages", nil), created.Token)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	resp = doRequest(t, app, httptest.NewRequest(fiber.MethodGet, "/v1/targets/arm64/packages", nil), created.Token)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	resp = doRequest(t, app, httptest.NewRequest(fiber.MethodGet, "/v1/tokens", nil), testAdminToken)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var tokens []api.Token
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&tokens))
+	assert.Equal(t, 1, len(tokens))
+	assert.Equal(t, created.ID, tokens[0].ID)
+	assert.DeepEqual(t, []string{"amd64"}, tokens[0].Targets)
+	assert.Equal(t, "", tokens[0].Token)
+
+	resp = doRequest(t, app, httptest.NewRequest(fiber.MethodDelete, "/v1/tokens/"+created.ID.String(), nil), testAdminToken)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp = doRequest(t, app, httptest.NewRequest(fiber.MethodGet, "/v1/targets/amd64/packages", nil), created.Token)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = doRequest(t, app, httptest.NewRequest(fiber.MethodDelete, "/v1/tokens/"+created.ID.String(), nil), testAdminToken)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "token_not_found", decodeError(t, resp).Code)
+}
+
+func TestCreateTokenRejectsInvalidRequests(t *testing.T) {
+	s, app := newTestServer(t)
+	newTestTarget(t, s, "amd64", false)
+
+	tests := []struct {
+		name   string
+		req    api.CreateTokenRequest
+		status int
+		code   string
+	}{
+		{"missing name", api.CreateTokenRequest{Scopes: []string{"admin"}}, fiber.StatusBadRequest, "invalid_request"},
+		{"missing scopes", api.CreateTokenRequest{Name: "ci"}, fiber.StatusBadRequest, "invalid_request"},
+		{"unknown scope", api.CreateTokenRequest{Name: "ci", Scopes: []string{"root"}}, fiber.StatusBadRequest, "invalid_request"},
+		{"unknown target", api.CreateTokenRequest{
+			Name: "ci", Scopes: []string{"target:read"}, Targets: []string{"amd64", "missing"},
+		}, fiber.StatusNotFound, "target_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := createTestToken(t, app, &tt.req)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.code, decodeError(t, resp).Code)
+		})
+	}
+
+	n, err := s.deps.DB.Token.Query().Count(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, 0, n)
+}
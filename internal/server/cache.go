@@ -0,0 +1,83 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// setValidators sets the ETag and Last-Modified headers of the
+// response to the provided values. etag must be a quoted strong entity
+// tag.
+func setValidators(c fiber.Ctx, etag string, lastModified time.Time) {
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+}
+
+// notModified returns true if the conditional headers of the request
+// (If-None-Match and If-Modified-Since) show that the client already
+// has the representation identified by etag and lastModified. See RFC
+// 9110, section 13.2.2.
+//
+// [fiber.Ctx.Fresh] isn't used since it considers any request with an
+// If-Modified-Since header fresh, regardless of its value.
+func notModified(c fiber.Ctx, etag string, lastModified time.Time) bool {
+	if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		return false
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since.
+	if inm := c.Get(fiber.HeaderIfNoneMatch); inm != "" {
+		return etagMatches(inm, etag, true)
+	}
+
+	if ims := c.Get(fiber.HeaderIfModifiedSince); ims != "" {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+
+		// HTTP dates only have a precision of seconds.
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
+
+// etagMatches returns true if the provided list of entity tags from an
+// If-None-Match or If-Match header contains etag. Weak comparison
+// ignores the W/ prefix of tags.
+func etagMatches(list, etag string, weak bool) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
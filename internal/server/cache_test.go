@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jaredallard/binhost/internal/catalog"
+	"gotest.tools/v3/assert"
+)
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		weak bool
+		want bool
+	}{
+		{"exact", `"abc"`, true, true},
+		{"different", `"abd"`, true, false},
+		{"any", "*", true, true},
+		{"any with whitespace", " * ", false, true},
+		{"list", `"foo", "abc"`, true, true},
+		{"list without whitespace", `"foo","abc","bar"`, true, true},
+		{"list without match", `"foo", "bar"`, true, false},
+		{"weak tag with weak comparison", `W/"abc"`, true, true},
+		{"weak tag in list", `"foo", W/"abc"`, true, true},
+		{"weak tag with strong comparison", `W/"abc"`, false, false},
+		{"unquoted", `abc`, true, false},
+		{"empty", "", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, etagMatches(tt.list, `"abc"`, tt.weak))
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 500, time.UTC)
+
+	app := fiber.New()
+	app.Add([]string{fiber.MethodGet, fiber.MethodHead, fiber.MethodPost}, "/", func(c fiber.Ctx) error {
+		if notModified(c, `"abc"`, lastModified) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	httpDate := func(t time.Time) string { return t.Format(http.TimeFormat) }
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    int
+	}{
+		{"unconditional", fiber.MethodGet, nil, fiber.StatusOK},
+		{"matching etag", fiber.MethodGet, map[string]string{"If-None-Match": `"abc"`}, fiber.StatusNotModified},
+		{"matching etag on HEAD", fiber.MethodHead, map[string]string{"If-None-Match": `"abc"`}, fiber.StatusNotModified},
+		{"matching etag on POST", fiber.MethodPost, map[string]string{"If-None-Match": `"abc"`}, fiber.StatusOK},
+		{"any etag", fiber.MethodGet, map[string]string{"If-None-Match": "*"}, fiber.StatusNotModified},
+		{"weak etag", fiber.MethodGet, map[string]string{"If-None-Match": `W/"abc"`}, fiber.StatusNotModified},
+		{"different etag", fiber.MethodGet, map[string]string{"If-None-Match": `"abd"`}, fiber.StatusOK},
+		{"modified since", fiber.MethodGet, map[string]string{"If-Modified-Since": httpDate(lastModified.Add(-time.Second))}, fiber.StatusOK},
+		{"not modified since", fiber.MethodGet, map[string]string{"If-Modified-Since": httpDate(lastModified)}, fiber.StatusNotModified},
+		{"not modified since later", fiber.MethodGet, map[string]string{"If-Modified-Since": httpDate(lastModified.Add(time.Hour))}, fiber.StatusNotModified},
+		{"invalid date", fiber.MethodGet, map[string]string{"If-Modified-Since": "yesterday"}, fiber.StatusOK},
+		{
+			"etag takes precedence over an old date", fiber.MethodGet,
+			map[string]string{"If-None-Match": `"abc"`, "If-Modified-Since": httpDate(lastModified.Add(-time.Hour))},
+			fiber.StatusNotModified,
+		},
+		{
+			"etag takes precedence over a current date", fiber.MethodGet,
+			map[string]string{"If-None-Match": `"abd"`, "If-Modified-Since": httpDate(lastModified)},
+			fiber.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp := doRequest(t, app, req, "")
+			assert.Equal(t, tt.want, resp.StatusCode)
+		})
+	}
+}
+
+func TestConditionalGetIndex(t *testing.T) {
+	s, app := newTestServer(t)
+	tgt := newTestTarget(t, s, "amd64", false)
+	addTestPackage(t, s, tgt, newTestXpak("app-misc", "foo-1.0", nil))
+
+	resp := doRequest(t, app, httptest.NewRequest(fiber.MethodGet, "/t/amd64/Packages", nil), "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	etag, lastModified := resp.Header.Get(fiber.HeaderETag), resp.Header.Get(fiber.HeaderLastModified)
+	assert.Assert(t, etag != "")
+	assert.Assert(t, lastModified != "")
+
+	for header, value := range map[string]string{"If-None-Match": etag, "If-Modified-Since": lastModified} {
+		req := httptest.NewRequest(fiber.MethodGet, "/t/amd64/Packages", nil)
+		req.Header.Set(header, value)
+		resp := doRequest(t, app, req, "")
+		assert.Equal(t, fiber.StatusNotModified, resp.StatusCode, header)
+		assert.Equal(t, etag, resp.Header.Get(fiber.HeaderETag), header)
+	}
+
+	// Changing the packages of the target changes the index.
+	assert.NilError(t, catalog.TouchIndex(context.Background(), s.deps.DB, tgt))
+	req := httptest.NewRequest(fiber.MethodGet, "/t/amd64/Packages", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp = doRequest(t, app, req, "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Assert(t, resp.Header.Get(fiber.HeaderETag) != etag)
+}
+
+func TestConditionalGetPackage(t *testing.T) {
+	s, app := newTestServer(t)
+	tgt := newTestTarget(t, s, "amd64", false)
+	p := addTestPackage(t, s, tgt, newTestXpak("app-misc", "foo-1.0", nil))
+	path := "/t/amd64/" + p.Path
+
+	resp := doRequest(t, app, httptest.NewRequest(fiber.MethodGet, path, nil), "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, `"`+p.Blake2b+`"`, resp.Header.Get(fiber.HeaderETag))
+	lastModified := resp.Header.Get(fiber.HeaderLastModified)
+
+	for header, value := range map[string]string{"If-None-Match": `"` + p.Blake2b + `"`, "If-Modified-Since": lastModified} {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		req.Header.Set(header, value)
+		resp := doRequest(t, app, req, "")
+		assert.Equal(t, fiber.StatusNotModified, resp.StatusCode, header)
+	}
+
+	req := httptest.NewRequest(fiber.MethodGet, path, nil)
+	req.Header.Set("If-None-Match", `"other"`)
+	resp = doRequest(t, app, req, "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
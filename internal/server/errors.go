@@ -0,0 +1,125 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/packages"
+)
+
+// Error is an error returned to clients of the API. It is rendered as
+// an [api.ErrorResponse] by errorHandler.
+type Error struct {
+	// Status is the HTTP status code of the response.
+	Status int
+
+	// Code is a stable, machine readable identifier of the error, e.g.,
+	// target_not_found.
+	Code string
+
+	// Message is a human readable description of the error.
+	Message string
+
+	// Details contains additional information about the error, e.g., the
+	// file that failed validation. Optional.
+	Details map[string]any
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// newError creates a new Error.
+func newError(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// withDetails returns a copy of the error with the provided details.
+func (e *Error) withDetails(details map[string]any) *Error {
+	cpy := *e
+	cpy.Details = details
+	return &cpy
+}
+
+// withMessage returns a copy of the error with the provided message.
+func (e *Error) withMessage(msg string) *Error {
+	cpy := *e
+	cpy.Message = msg
+	return &cpy
+}
+
+// Contains errors returned by multiple handlers.
+var (
+	errTargetNotFound  = newError(fiber.StatusNotFound, "target_not_found", "target not found")
+	errTargetExists    = newError(fiber.StatusConflict, "target_exists", "target already exists")
+	errPackageNotFound = newError(fiber.StatusNotFound, "package_not_found", "package not found")
+	errObjectNotFound  = newError(fiber.StatusNotFound, "object_not_found", "package archive not found")
+	errInvalidRequest  = newError(fiber.StatusBadRequest, "invalid_request", "invalid request")
+	errUnauthorized    = newError(fiber.StatusUnauthorized, "unauthorized", "missing or invalid token")
+	errForbidden       = newError(fiber.StatusForbidden, "forbidden", "token is missing the required scope")
+	errInvalidPackage  = newError(fiber.StatusUnprocessableEntity, "invalid_package", "invalid package")
+)
+
+// invalidPackageError returns the Error for a package that failed to
+// be parsed. Manifest verification failures include the failing file.
+func invalidPackageError(err error) *Error {
+	apiErr := errInvalidPackage.withMessage(err.Error())
+
+	var manifestErr *packages.ManifestError
+	if errors.As(err, &manifestErr) {
+		apiErr.Details = map[string]any{"file": manifestErr.File}
+	}
+
+	return apiErr
+}
+
+// errorHandler returns a fiber.ErrorHandler that responds with an
+// [api.ErrorResponse] for errors returned by handlers. Errors that aren't an
+// Error or a fiber.Error are logged and reported as internal errors,
+// since they may contain information that shouldn't be exposed.
+func errorHandler(log *slog.Logger) fiber.ErrorHandler {
+	return func(c fiber.Ctx, err error) error {
+		var apiErr *Error
+		var fiberErr *fiber.Error
+		switch {
+		case errors.As(err, &apiErr):
+		case errors.As(err, &fiberErr):
+			apiErr = newError(fiberErr.Code, statusCode(fiberErr.Code), fiberErr.Message)
+		default:
+			log.Error("request failed", "method", c.Method(), "path", c.Path(), "error", err)
+			apiErr = newError(fiber.StatusInternalServerError, "internal", "internal server error")
+		}
+
+		return c.Status(apiErr.Status).JSON(api.ErrorResponse{Error: api.ErrorBody{
+			Code:    apiErr.Code,
+			Message: apiErr.Message,
+			Details: apiErr.Details,
+		}})
+	}
+}
+
+// statusCode returns the error code for errors that only have a HTTP
+// status, e.g., method_not_allowed.
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jaredallard/binhost/internal/api"
+	"gotest.tools/v3/assert"
+)
+
+// doErrorRequest returns the status and body of a request to a route
+// that fails with the provided error.
+func doErrorRequest(t *testing.T, err error) (int, api.ErrorResponse) {
+	app := fiber.New(fiber.Config{ErrorHandler: errorHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))})
+	app.Get("/", func(c fiber.Ctx) error { return err })
+
+	resp, terr := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	assert.NilError(t, terr)
+	defer resp.Body.Close()
+
+	var body api.ErrorResponse
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestErrorHandlerRendersErrors(t *testing.T) {
+	status, body := doErrorRequest(t, errTargetNotFound)
+	assert.Equal(t, fiber.StatusNotFound, status)
+	assert.DeepEqual(t, api.ErrorResponse{Error: api.ErrorBody{Code: "target_not_found", Message: "target not found"}}, body)
+}
+
+func TestErrorHandlerRendersDetails(t *testing.T) {
+	status, body := doErrorRequest(t, errInvalidPackage.withDetails(map[string]any{"file": "image.tar.xz"}))
+	assert.Equal(t, fiber.StatusUnprocessableEntity, status)
+	assert.Equal(t, "invalid_package", body.Error.Code)
+	assert.DeepEqual(t, map[string]any{"file": "image.tar.xz"}, body.Error.Details)
+}
+
+func TestErrorHandlerRendersFiberErrors(t *testing.T) {
+	status, body := doErrorRequest(t, fiber.ErrMethodNotAllowed)
+	assert.Equal(t, fiber.StatusMethodNotAllowed, status)
+	assert.Equal(t, "method_not_allowed", body.Error.Code)
+}
+
+func TestErrorHandlerHidesInternalErrors(t *testing.T) {
+	status, body := doErrorRequest(t, errors.New("failed querying targets: connection refused"))
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.DeepEqual(t, api.ErrorResponse{Error: api.ErrorBody{Code: "internal", Message: "internal server error"}}, body)
+}
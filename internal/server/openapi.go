@@ -0,0 +1,293 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/jaredallard/binhost/internal/api"
+)
+
+// operation documents a route in the OpenAPI document.
+type operation struct {
+	// Summary is a short description of the route.
+	Summary string
+
+	// Description is a longer description of the route. Optional.
+	Description string
+
+	// Query are the query parameters accepted by the route.
+	Query []parameter
+
+	// Request is a value of the type of the JSON request body, if any.
+	Request any
+
+	// RawRequest is set when the request body is a binary file.
+	RawRequest bool
+
+	// Status is the status code of successful responses.
+	Status int
+
+	// Response is a value of the type of the JSON response body, if any.
+	Response any
+}
+
+// parameter is a query parameter of a route.
+type parameter struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// getOpenAPI responds with the OpenAPI document of the API.
+func (s *Server) getOpenAPI(c fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(openAPIDocument(s.apiRoutes()))
+}
+
+// openAPIDocument generates an OpenAPI 3.0 document describing the
+// provided routes. Schemas are generated from the request and response
+// types of the routes.
+func openAPIDocument(routes []route) map[string]any {
+	g := &schemaGenerator{schemas: map[string]any{}}
+
+	paths := map[string]map[string]any{}
+	for _, r := range routes {
+		path, params := openAPIPath(r.path)
+		for _, q := range r.doc.Query {
+			params = append(params, map[string]any{
+				"name":        q.Name,
+				"in":          "query",
+				"description": q.Description,
+				"schema":      map[string]any{"type": q.Type},
+			})
+		}
+
+		op := map[string]any{
+			"operationId": operationID(r.name),
+			"summary":     r.doc.Summary,
+			"responses":   g.responses(r.doc),
+		}
+		if r.doc.Description != "" {
+			op["description"] = r.doc.Description
+		}
+		if len(params) != 0 {
+			op["parameters"] = params
+		}
+		if r.scope != "" {
+			op["security"] = []any{map[string]any{"token": []string{}}}
+			op["description"] = strings.TrimSpace(r.doc.Description + " Requires the `" + string(r.scope) + "` scope.")
+		}
+		switch {
+		case r.doc.RawRequest:
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/octet-stream": map[string]any{
+						"schema": map[string]any{"type": "string", "format": "binary"},
+					},
+				},
+			}
+		case r.doc.Request != nil:
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(r.doc.Request))},
+				},
+			}
+		}
+
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(r.method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "binhost",
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]any{
+				"token": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+// responses returns the OpenAPI responses of the provided operation.
+// All operations can return an [api.ErrorResponse].
+func (g *schemaGenerator) responses(op operation) map[string]any {
+	success := map[string]any{"description": http.StatusText(op.Status)}
+	if op.Response != nil {
+		success["content"] = map[string]any{
+			"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(op.Response))},
+		}
+	}
+
+	return map[string]any{
+		strconv.Itoa(op.Status): success,
+		"default": map[string]any{
+			"description": "Error",
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(api.ErrorResponse{}))},
+			},
+		},
+	}
+}
+
+// openAPIPath converts a fiber route path into an OpenAPI path and its
+// path parameters, e.g., /v1/targets/:target to /v1/targets/{target}.
+func openAPIPath(path string) (string, []map[string]any) {
+	var params []map[string]any
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		name, ok := strings.CutPrefix(part, ":")
+		if !ok {
+			continue
+		}
+
+		parts[i] = "{" + name + "}"
+		params = append(params, map[string]any{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+
+	return strings.Join(parts, "/"), params
+}
+
+// operationID converts the name of a route into an operation ID, e.g.,
+// list targets to listTargets.
+func operationID(name string) string {
+	words := strings.Fields(name)
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	return strings.Join(words, "")
+}
+
+// schemaGenerator generates OpenAPI schemas from Go types. Named
+// structs are added to schemas and referenced.
+type schemaGenerator struct {
+	schemas map[string]any
+}
+
+// schema returns the OpenAPI schema of the provided type, following the
+// rules of encoding/json.
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]any{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(uuid.UUID{}):
+		return map[string]any{"type": "string", "format": "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := g.schemas[name]; !ok {
+			// Reserve the name first in case the type is recursive.
+			g.schemas[name] = nil
+			g.schemas[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		// Any value, e.g., an interface.
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the OpenAPI schema of the provided struct type.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	g.addFields(t, properties, &required)
+
+	s := map[string]any{"type": "object", "properties": properties}
+	if len(required) != 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// addFields adds the JSON encoded fields of the provided struct type to
+// properties. Fields that are always present are added to required.
+func (g *schemaGenerator) addFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// Embedded structs are flattened.
+				g.addFields(ft, properties, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		properties[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
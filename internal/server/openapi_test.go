@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jaredallard/binhost/internal/dpi"
+	"gotest.tools/v3/assert"
+)
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	s := &Server{&dpi.Dependencies{Log: slog.New(slog.NewTextHandler(io.Discard, nil))}}
+	app := s.newApp()
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/openapi.json", nil))
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			OperationID string         `json:"operationId"`
+			Responses   map[string]any `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&doc))
+
+	var documented int
+	for _, r := range app.GetRoutes(true) {
+		if !strings.HasPrefix(r.Path, "/v1/") || r.Method == fiber.MethodHead {
+			continue
+		}
+
+		path, _ := openAPIPath(r.Path)
+		op, ok := doc.Paths[path][strings.ToLower(r.Method)]
+		assert.Assert(t, ok, "%s %s is not documented", r.Method, r.Path)
+		assert.Assert(t, op.OperationID != "")
+		assert.Assert(t, op.Responses["default"] != nil)
+		documented++
+	}
+	assert.Equal(t, len(s.apiRoutes()), documented)
+
+	// Embedded structs are flattened into their parent.
+	target := doc.Components.Schemas["Target"]
+	assert.Assert(t, target.Properties["name"] != nil)
+	assert.Assert(t, target.Properties["packages"] != nil)
+	assert.Assert(t, doc.Components.Schemas["Profile"].Properties["CHOST"] != nil)
+	assert.Assert(t, doc.Components.Schemas["PackageMetadata"].Properties["build_time"] != nil)
+}
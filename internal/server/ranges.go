@@ -0,0 +1,149 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jaredallard/binhost/internal/httprange"
+	"github.com/jaredallard/binhost/internal/storage"
+)
+
+// sendObject responds with the object stored under key, which is size
+// bytes long. Range requests (including If-Range) are supported, with
+// ranges being read from storage rather than the whole object. etag and
+// lastModified must be the validators of the object, as set by
+// setValidators.
+func (s *Server) sendObject(c fiber.Ctx, key string, size int64, contentType, etag string, lastModified time.Time) error {
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+
+	var ranges []httprange.Range
+	if rangeHeader := c.Get(fiber.HeaderRange); rangeHeader != "" && ifRangeMatches(c, etag, lastModified) {
+		var err error
+		ranges, err = httprange.Parse(rangeHeader, size)
+		if err != nil {
+			c.Set(fiber.HeaderContentRange, "bytes */"+strconv.FormatInt(size, 10))
+			return newError(fiber.StatusRequestedRangeNotSatisfiable, "range_not_satisfiable", err.Error())
+		}
+	}
+
+	switch len(ranges) {
+	case 0:
+		obj, err := s.deps.Storage.Get(c.Context(), key)
+		if err != nil {
+			return objectError(c, err)
+		}
+
+		// Closed by fasthttp once the body has been sent.
+		c.Set(fiber.HeaderContentType, contentType)
+		return c.SendStream(obj, int(obj.Size))
+	case 1:
+		r := ranges[0]
+		obj, err := s.deps.Storage.GetRange(c.Context(), key, r.Start, r.Length)
+		if err != nil {
+			return objectError(c, err)
+		}
+
+		c.Set(fiber.HeaderContentType, contentType)
+		c.Set(fiber.HeaderContentRange, r.ContentRange(size))
+		return c.Status(fiber.StatusPartialContent).SendStream(obj, int(obj.Size))
+	}
+
+	// Multiple ranges are sent as a multipart/byteranges body, which is
+	// streamed from storage one range at a time.
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		// The request context can't be used once the handler has
+		// returned. Instead, the pipe is closed by fasthttp if the client
+		// goes away, which stops the writes.
+		pw.CloseWithError(writeRanges(context.Background(), s.deps.Storage, mw, key, size, contentType, ranges))
+	}()
+
+	c.Set(fiber.HeaderContentType, "multipart/byteranges; boundary="+mw.Boundary())
+	return c.Status(fiber.StatusPartialContent).SendStream(pr)
+}
+
+// writeRanges writes the provided ranges of the object stored under key
+// as parts of mw.
+func writeRanges(
+	ctx context.Context, store storage.Storage, mw *multipart.Writer, key string, size int64, contentType string, ranges []httprange.Range,
+) error {
+	for _, r := range ranges {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			fiber.HeaderContentType:  {contentType},
+			fiber.HeaderContentRange: {r.ContentRange(size)},
+		})
+		if err != nil {
+			return err
+		}
+
+		obj, err := store.GetRange(ctx, key, r.Start, r.Length)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(part, obj)
+		obj.Close() //nolint:errcheck // Why: Best effort close.
+		if err != nil {
+			return fmt.Errorf("failed to copy range of object %s: %w", key, err)
+		}
+	}
+
+	return mw.Close()
+}
+
+// ifRangeMatches returns true if the If-Range header of the request,
+// if any, matches the provided validators, meaning the Range header
+// should be honored. See RFC 9110, section 13.1.5.
+func ifRangeMatches(c fiber.Ctx, etag string, lastModified time.Time) bool {
+	ifRange := c.Get(fiber.HeaderIfRange)
+	if ifRange == "" {
+		return true
+	}
+
+	// Entity tags are compared using strong comparison, so weak tags
+	// never match.
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		return ifRange == etag
+	}
+
+	t, err := http.ParseTime(ifRange)
+	if err != nil {
+		return false
+	}
+	return lastModified.Truncate(time.Second).Equal(t)
+}
+
+// objectError responds with the appropriate error for an error
+// returned by storage when reading an object.
+func objectError(c fiber.Ctx, err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return errObjectNotFound
+	}
+
+	return fmt.Errorf("failed to get package archive: %w", err)
+}
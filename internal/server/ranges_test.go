@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jaredallard/binhost/internal/dpi"
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+const testObject = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+var testModified = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// newRangeTestApp returns an app serving testObject, stored using the
+// filesystem storage, at /object.
+func newRangeTestApp(t *testing.T) *fiber.App {
+	store := storage.NewFS(t.TempDir())
+	assert.NilError(t, store.Put(context.Background(), "object", strings.NewReader(testObject), int64(len(testObject)), ""))
+
+	s := &Server{&dpi.Dependencies{Storage: store}}
+	app := fiber.New(fiber.Config{ErrorHandler: errorHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))})
+	app.Get("/object", func(c fiber.Ctx) error {
+		setValidators(c, `"etag"`, testModified)
+		return s.sendObject(c, "object", int64(len(testObject)), "application/x-tar", `"etag"`, testModified)
+	})
+	return app
+}
+
+// doRangeRequest sends a request for /object with the provided headers.
+func doRangeRequest(t *testing.T, app *fiber.App, headers map[string]string) (*http.Response, string) {
+	req := httptest.NewRequest(fiber.MethodGet, "/object", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := app.Test(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	return resp, string(b)
+}
+
+func TestSendObjectWithoutRange(t *testing.T) {
+	resp, body := doRangeRequest(t, newRangeTestApp(t), nil)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "bytes", resp.Header.Get(fiber.HeaderAcceptRanges))
+	assert.Equal(t, testObject, body)
+}
+
+func TestSendObjectSingleRange(t *testing.T) {
+	resp, body := doRangeRequest(t, newRangeTestApp(t), map[string]string{fiber.HeaderRange: "bytes=10-15"})
+	assert.Equal(t, fiber.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "bytes 10-15/36", resp.Header.Get(fiber.HeaderContentRange))
+	assert.Equal(t, "abcdef", body)
+}
+
+func TestSendObjectMultipleRanges(t *testing.T) {
+	resp, body := doRangeRequest(t, newRangeTestApp(t), map[string]string{fiber.HeaderRange: "bytes=0-1,-2"})
+	assert.Equal(t, fiber.StatusPartialContent, resp.StatusCode)
+
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get(fiber.HeaderContentType))
+	assert.NilError(t, err)
+	assert.Equal(t, "multipart/byteranges", mediaType)
+
+	mr := multipart.NewReader(strings.NewReader(body), params["boundary"])
+	for _, want := range []struct{ contentRange, body string }{
+		{"bytes 0-1/36", "01"},
+		{"bytes 34-35/36", "yz"},
+	} {
+		part, err := mr.NextPart()
+		assert.NilError(t, err)
+		assert.Equal(t, want.contentRange, part.Header.Get(fiber.HeaderContentRange))
+		b, err := io.ReadAll(part)
+		assert.NilError(t, err)
+		assert.Equal(t, want.body, string(b))
+	}
+	_, err = mr.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestSendObjectUnsatisfiableRange(t *testing.T) {
+	resp, _ := doRangeRequest(t, newRangeTestApp(t), map[string]string{fiber.HeaderRange: "bytes=100-"})
+	assert.Equal(t, fiber.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+	assert.Equal(t, "bytes */36", resp.Header.Get(fiber.HeaderContentRange))
+}
+
+func TestSendObjectIfRange(t *testing.T) {
+	app := newRangeTestApp(t)
+
+	for _, tt := range []struct {
+		ifRange string
+		want    int
+	}{
+		{`"etag"`, fiber.StatusPartialContent},
+		{`"other"`, fiber.StatusOK},
+		{`W/"etag"`, fiber.StatusOK},
+		{testModified.Format(http.TimeFormat), fiber.StatusPartialContent},
+		{testModified.Add(-time.Hour).Format(http.TimeFormat), fiber.StatusOK},
+	} {
+		resp, _ := doRangeRequest(t, app, map[string]string{
+			fiber.HeaderRange:   "bytes=0-1",
+			fiber.HeaderIfRange: tt.ifRange,
+		})
+		assert.Equal(t, tt.want, resp.StatusCode, tt.ifRange)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/logger"
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/auth"
+)
+
+// route is a route of the /v1 API.
+type route struct {
+	// name is the name of the route, e.g., list targets.
+	name    string
+	method  string
+	path    string
+	handler fiber.Handler
+
+	// scope is the scope a token must be granted to call the route.
+	// Empty if the route doesn't require a token.
+	scope auth.Scope
+
+	// doc documents the route in the OpenAPI document.
+	doc operation
+}
+
+// apiRoutes returns all routes of the /v1 API.
+func (s *Server) apiRoutes() []route {
+	return []route{
+		{
+			name: "list packages", method: fiber.MethodGet, path: "/v1/packages",
+			handler: s.listPackages, scope: auth.ScopeTargetRead,
+			doc: operation{
+				Summary:     "Search packages across all targets the token can read",
+				Query:       searchParameters,
+				Status:      fiber.StatusOK,
+				Response:    api.ListPackagesResponse{},
+				Description: "Answers questions like which targets contain a package.",
+			},
+		},
+		{
+			name: "list targets", method: fiber.MethodGet, path: "/v1/targets",
+			handler: s.listTargets, scope: auth.ScopeTargetRead,
+			doc: operation{
+				Summary:  "List the targets the token can read",
+				Status:   fiber.StatusOK,
+				Response: []api.Target{},
+			},
+		},
+		{
+			name: "create target", method: fiber.MethodPost, path: "/v1/targets/:target",
+			handler: s.createTarget, scope: auth.ScopeAdmin,
+			doc: operation{
+				Summary: "Create a target",
+				Status:  fiber.StatusCreated,
+			},
+		},
+		{
+			name: "update target", method: fiber.MethodPatch, path: "/v1/targets/:target",
+			handler: s.updateTarget, scope: auth.ScopeAdmin,
+			doc: operation{
+				Summary:  "Rename a target or update its metadata",
+				Request:  api.UpdateTargetRequest{},
+				Status:   fiber.StatusOK,
+				Response: api.Target{},
+			},
+		},
+		{
+			name: "delete target", method: fiber.MethodDelete, path: "/v1/targets/:target",
+			handler: s.deleteTarget, scope: auth.ScopeAdmin,
+			doc: operation{
+				Summary: "Delete a target",
+				Query: []parameter{
+					{"force", "boolean", "Delete the target even if it contains packages, along with its packages."},
+				},
+				Status: fiber.StatusNoContent,
+			},
+		},
+		{
+			name: "upload package", method: fiber.MethodPost, path: "/v1/targets/:target/upload",
+			handler: s.uploadPackage, scope: auth.ScopeTargetWrite,
+			doc: operation{
+				Summary:    "Upload a gpkg or XPAK package to a target",
+				RawRequest: true,
+				Status:     fiber.StatusCreated,
+				Response:   api.UploadResponse{},
+			},
+		},
+		{
+			name: "upload package by chost", method: fiber.MethodPost, path: "/v1/upload",
+			handler: s.uploadPackageByCHost, scope: auth.ScopeTargetWrite,
+			doc: operation{
+				Summary: "Upload a gpkg or XPAK package to the target named after its CHOST",
+				Description: "Unknown targets are rejected, unless the server is configured to create them " +
+					"and the token isn't limited to specific targets.",
+				RawRequest: true,
+				Status:     fiber.StatusCreated,
+				Response:   api.UploadResponse{},
+			},
+		},
+		{
+			name: "list target packages", method: fiber.MethodGet, path: "/v1/targets/:target/packages",
+			handler: s.listTargetPackages, scope: auth.ScopeTargetRead,
+			doc: operation{
+				Summary:  "Search the packages in a target",
+				Query:    searchParameters,
+				Status:   fiber.StatusOK,
+				Response: api.ListPackagesResponse{},
+			},
+		},
+		{
+			name: "delete packages", method: fiber.MethodDelete, path: "/v1/targets/:target/packages",
+			handler: s.deletePackages, scope: auth.ScopeTargetWrite,
+			doc: operation{
+				Summary: "Delete all packages in a target matching an atom pattern",
+				Query: []parameter{
+					{"atom", "string", "Glob pattern matched against the CPV of packages, e.g., dev-lang/rust-*. Required."},
+				},
+				Status:   fiber.StatusOK,
+				Response: []api.DeletedPackage{},
+			},
+		},
+		{
+			name: "delete package", method: fiber.MethodDelete, path: "/v1/targets/:target/packages/:category/:name/:version",
+			handler: s.deletePackage, scope: auth.ScopeTargetWrite,
+			doc: operation{
+				Summary: "Delete a version of a package from a target",
+				Query: []parameter{
+					{"build_id", "integer", "Only delete the build with this BUILD_ID."},
+				},
+				Status:   fiber.StatusOK,
+				Response: []api.DeletedPackage{},
+			},
+		},
+		{
+			name: "list tokens", method: fiber.MethodGet, path: "/v1/tokens",
+			handler: s.listTokens, scope: auth.ScopeAdmin,
+			doc: operation{
+				Summary:  "List tokens",
+				Status:   fiber.StatusOK,
+				Response: []api.Token{},
+			},
+		},
+		{
+			name: "create token", method: fiber.MethodPost, path: "/v1/tokens",
+			handler: s.createToken, scope: auth.ScopeAdmin,
+			doc: operation{
+				Summary:  "Create a token",
+				Request:  api.CreateTokenRequest{},
+				Status:   fiber.StatusCreated,
+				Response: api.Token{},
+			},
+		},
+		{
+			name: "revoke token", method: fiber.MethodDelete, path: "/v1/tokens/:id",
+			handler: s.revokeToken, scope: auth.ScopeAdmin,
+			doc: operation{
+				Summary: "Revoke a token",
+				Status:  fiber.StatusNoContent,
+			},
+		},
+		{
+			name: "openapi", method: fiber.MethodGet, path: "/v1/openapi.json",
+			handler: s.getOpenAPI,
+			doc: operation{
+				Summary: "Get the OpenAPI document describing the API",
+				Status:  fiber.StatusOK,
+			},
+		},
+	}
+}
+
+// searchParameters are the query parameters of the package search
+// endpoints.
+var searchParameters = []parameter{
+	{"category", "string", "Exact category, e.g., app-editors."},
+	{"name", "string", "Glob pattern (using * and ?) matched against the package name."},
+	{"repository", "string", "Repository the package was built from, e.g., gentoo."},
+	{"slot", "string", "Exact SLOT."},
+	{"keyword", "string", "Keyword the package has, e.g., ~amd64."},
+	{"use", "string", "USE flag the package was built with."},
+	{"built_after", "string", "RFC 3339 time the package was built at or after."},
+	{"built_before", "string", "RFC 3339 time the package was built at or before."},
+	{"cursor", "string", "next_cursor of the previous page."},
+	{"limit", "integer", "Maximum number of packages to return. Defaults to 100, at most 1000."},
+}
+
+// newApp creates the fiber app serving the binhost.
+func (s *Server) newApp() *fiber.App {
+	app := fiber.New(fiber.Config{
+		StreamRequestBody: true,
+		ErrorHandler:      errorHandler(s.deps.Log),
+	})
+
+	app.Use(logger.New(logger.Config{
+		LoggerFunc: func(c fiber.Ctx, data *logger.Data, cfg logger.Config) error {
+			s.deps.Log.Info("http request", "method", c.Method(), "path", c.OriginalURL(), "status", c.Response().StatusCode(), "duration", data.Stop.Sub(data.Start).String())
+			return nil
+		},
+	})).Name("logger")
+
+	for _, r := range s.apiRoutes() {
+		var middleware []fiber.Handler
+		if r.scope != "" {
+			middleware = append(middleware, s.requireScope(r.scope))
+		}
+		app.Add([]string{r.method}, r.path, r.handler, middleware...).Name(r.name)
+	}
+
+	// Gentoo Paths
+	app.Get("/t/:target/Packages", s.getPackages, s.requireTargetAccess)
+	app.Get("/t/:target/*", s.getTargetPackageIndex, s.requireTargetAccess)
+
+	return app
+}
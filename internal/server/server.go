@@ -19,16 +19,22 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
-	"github.com/gofiber/fiber/v3/middleware/logger"
 	"github.com/google/uuid"
 	_ "github.com/jackc/pgx/v5/stdlib" // Used by ent.
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/atom"
+	"github.com/jaredallard/binhost/internal/auth"
+	"github.com/jaredallard/binhost/internal/catalog"
 	"github.com/jaredallard/binhost/internal/config"
 	"github.com/jaredallard/binhost/internal/dpi"
 	"github.com/jaredallard/binhost/internal/ent"
-	"github.com/jaredallard/binhost/internal/ent/target"
+	"github.com/jaredallard/binhost/internal/ent/pkg"
+	"github.com/jaredallard/binhost/internal/ent/predicate"
 	"github.com/jaredallard/binhost/internal/packages"
 )
 
@@ -49,107 +55,410 @@ type Server struct {
 }
 
 func (s *Server) listTargets(c fiber.Ctx) error {
-	targets, err := s.deps.DB.Target.Query().All(c.Context())
+	targets, stats, err := catalog.ListTargets(c.Context(), s.deps.DB)
 	if err != nil {
-		return fmt.Errorf("failed querying targets: %w", err)
+		return err
 	}
 
-	type respType struct {
-		ID   uuid.UUID `json:"id"`
-		Name string    `json:"name"`
-	}
-
-	resp := make([]respType, 0, len(targets))
+	p := principal(c)
+	resp := make([]api.Target, 0, len(targets))
 	for _, t := range targets {
-		resp = append(resp, respType{ID: t.ID, Name: t.Name})
+		if !p.Can(auth.ScopeTargetRead, t.ID) {
+			continue
+		}
+		resp = append(resp, api.NewTarget(t, stats[t.ID]))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
 
 func (s *Server) createTarget(c fiber.Ctx) error {
-	targetName := c.Params("target")
-	_, err := s.deps.DB.Target.Create().SetName(targetName).Save(c.Context())
+	if _, err := catalog.CreateTarget(c.Context(), s.deps.DB, c.Params("target")); err != nil {
+		if errors.Is(err, catalog.ErrTargetExists) {
+			return errTargetExists
+		}
+
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// updateTarget renames a target and/or updates its metadata. Only the
+// fields present in the request body are changed.
+func (s *Server) updateTarget(c fiber.Ctx) error {
+	t, err := s.lookupTarget(c, c.Params("target"))
+	if err != nil {
+		return err
+	}
+
+	var req api.UpdateTargetRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return errInvalidRequest.withMessage(err.Error())
+	}
+
+	update := s.deps.DB.Target.UpdateOne(t)
+	if req.Name != nil {
+		if *req.Name == "" {
+			return errInvalidRequest.withMessage("name must not be empty")
+		}
+		update.SetName(*req.Name)
+	}
+	if req.Description != nil {
+		update.SetDescription(*req.Description)
+	}
+	if req.Owner != nil {
+		update.SetOwner(*req.Owner)
+	}
+	if req.Private != nil {
+		update.SetPrivate(*req.Private)
+	}
+	if req.Profile != nil {
+		// The profile is part of the index, so it changes too.
+		update.SetProfile(req.Profile).SetIndexUpdatedAt(time.Now())
+	}
+
+	t, err = update.Save(c.Context())
 	if err != nil {
 		if ent.IsConstraintError(err) {
-			return c.SendStatus(fiber.StatusConflict)
+			return errTargetExists
 		}
 
-		return fmt.Errorf("failed creating target: %w", err)
+		return fmt.Errorf("failed updating target: %w", err)
 	}
 
-	return c.SendStatus(fiber.StatusCreated)
+	stats, err := catalog.Stats(c.Context(), s.deps.DB)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(api.NewTarget(t, stats[t.ID]))
 }
 
-func (s *Server) uploadPackage(c fiber.Ctx) error {
-	targetName := c.Params("target")
-	if targetName == "" {
-		return c.Status(fiber.StatusBadRequest).SendString("missing target")
+// deleteTarget deletes a target. Targets containing packages are only
+// deleted, along with their packages, when ?force=true is provided.
+func (s *Server) deleteTarget(c fiber.Ctx) error {
+	t, err := s.lookupTarget(c, c.Params("target"))
+	if err != nil {
+		return err
+	}
+
+	if err := catalog.DeleteTarget(c.Context(), s.deps.DB, s.deps.Storage, s.deps.Log, t, fiber.Query[bool](c, "force")); err != nil {
+		if errors.Is(err, catalog.ErrTargetNotEmpty) {
+			return newError(fiber.StatusConflict, "target_not_empty", "target is not empty, use ?force=true to delete it and its packages")
+		}
+
+		return err
 	}
 
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// uploadPackage uploads a package to the target in the route.
+func (s *Server) uploadPackage(c fiber.Ctx) error {
 	// Ensure the target exists
-	t, err := s.deps.DB.Target.Query().Where(target.NameEQ(targetName)).First(c.Context())
-	if t == nil || err != nil {
-		return c.Status(fiber.StatusNotFound).SendString("target not found")
+	t, err := s.lookupTarget(c, c.Params("target"))
+	if err != nil {
+		return err
 	}
 
-	pkg, err := packages.New(c.Request().BodyStream())
+	binpkg, err := packages.New(c.Request().BodyStream())
 	if err != nil {
-		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+		return invalidPackageError(err)
 	}
 	defer c.Request().CloseBodyStream() //nolint:errcheck // Why: Best effort close body.
-	defer pkg.Delete()                  //nolint:errcheck // Why: Best effort delete.
+	defer binpkg.Delete()               //nolint:errcheck // Why: Best effort delete.
 
+	return s.addPackage(c, t, binpkg)
+}
+
+// uploadPackageByCHost uploads a package to the target named after the
+// CHOST of the package. Unknown targets are created if the server is
+// configured to do so.
+func (s *Server) uploadPackageByCHost(c fiber.Ctx) error {
+	binpkg, err := packages.New(c.Request().BodyStream())
+	if err != nil {
+		return invalidPackageError(err)
+	}
+	defer c.Request().CloseBodyStream() //nolint:errcheck // Why: Best effort close body.
+	defer binpkg.Delete()               //nolint:errcheck // Why: Best effort delete.
+
+	if binpkg.CHost == "" {
+		return errInvalidPackage.withMessage("package has no CHOST, upload it to a target instead")
+	}
+
+	t, err := s.lookupTarget(c, binpkg.CHost)
+	if err != nil && !errors.Is(err, errTargetNotFound) {
+		return err
+	}
+
+	// requireScope can't check the target since it depends on the body.
+	// This is checked before anything else so that tokens limited to
+	// specific targets, which can't write to targets that don't exist,
+	// can't tell whether other targets exist.
+	targetID := uuid.Nil
+	if t != nil {
+		targetID = t.ID
+	}
+	if !principal(c).Can(auth.ScopeTargetWrite, targetID) {
+		return errForbidden.withMessage("token is missing scope " + string(auth.ScopeTargetWrite) + " for target " + binpkg.CHost)
+	}
+
+	if t == nil {
+		if !s.deps.Conf.AutoCreateTargets {
+			return errTargetNotFound.withDetails(map[string]any{"target": binpkg.CHost})
+		}
+
+		t, err = s.createTargetIfNotExists(c, binpkg.CHost)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.addPackage(c, t, binpkg)
+}
+
+// createTargetIfNotExists creates a target with the provided name,
+// returning the existing target if it was created concurrently.
+func (s *Server) createTargetIfNotExists(c fiber.Ctx, name string) (*ent.Target, error) {
+	t, err := catalog.CreateTarget(c.Context(), s.deps.DB, name)
+	if err != nil {
+		if errors.Is(err, catalog.ErrTargetExists) {
+			return s.lookupTarget(c, name)
+		}
+
+		return nil, err
+	}
+
+	s.deps.Log.Info("created target", "target", name)
+	return t, nil
+}
+
+// addPackage adds the provided package to the target and responds with
+// an uploadResponse.
+func (s *Server) addPackage(c fiber.Ctx, t *ent.Target, binpkg *packages.Package) error {
 	// name suitable for logging
-	logName := pkg.Category + "/" + pkg.Name + "-" + pkg.Version + "::" + pkg.Repo
+	logName := binpkg.Category + "/" + binpkg.PF + "::" + binpkg.Repo
 
 	s.deps.Log.Info("uploading package", "package", logName, "target", t.Name)
 
-	if err := s.deps.DB.Pkg.Create().
-		SetName(pkg.Name).
-		SetCategory(pkg.Category).
-		SetRepository(pkg.Repo).
-		SetTarget(t).
-		SetVersion(pkg.Version).
-		SetPackageFields(&pkg.PackageCommon).
-		Exec(c.Context()); err != nil {
-		if ent.IsConstraintError(err) {
-			return c.Status(fiber.StatusConflict).SendString("Package already exists")
+	p, err := catalog.AddPackage(c.Context(), s.deps.DB, s.deps.Storage, t, binpkg)
+	if err != nil {
+		switch {
+		case errors.Is(err, catalog.ErrPackageExists):
+			return newError(fiber.StatusConflict, "package_exists", "package already exists")
+		case errors.Is(err, catalog.ErrInvalidBuildID):
+			return errInvalidPackage.withMessage(err.Error())
 		}
 
-		return c.Status(fiber.StatusInternalServerError).SendString(err.Error())
+		return err
 	}
 
-	return c.SendStatus(fiber.StatusCreated)
+	return c.Status(fiber.StatusCreated).JSON(api.UploadResponse{
+		Target:  t.Name,
+		CPV:     catalog.CPV(p),
+		BuildID: p.BuildID,
+		Path:    p.Path,
+	})
+}
+
+// deletePackage deletes a single version of a package, or a single
+// build of it if the build_id query parameter is provided.
+func (s *Server) deletePackage(c fiber.Ctx) error {
+	t, err := s.lookupTarget(c, c.Params("target"))
+	if err != nil {
+		return err
+	}
+
+	v, err := atom.ParseVersion(c.Params("version"))
+	if err != nil {
+		return errInvalidRequest.withMessage(err.Error())
+	}
+
+	preds := []predicate.Pkg{
+		pkg.CategoryEQ(c.Params("category")),
+		pkg.NameEQ(c.Params("name")),
+		pkg.VersionEQ(v.PV()),
+		pkg.RevisionEQ(v.PR()),
+	}
+	if c.Query("build_id") != "" {
+		buildID := fiber.Query[int](c, "build_id")
+		if buildID < 1 {
+			return errInvalidRequest.withMessage("invalid build_id: " + c.Query("build_id"))
+		}
+		preds = append(preds, pkg.BuildIDEQ(buildID))
+	}
+
+	deleted, err := catalog.DeletePackages(c.Context(), s.deps.DB, s.deps.Storage, s.deps.Log, t, preds...)
+	if err != nil {
+		return err
+	}
+	if len(deleted) == 0 {
+		return errPackageNotFound
+	}
+
+	return c.Status(fiber.StatusOK).JSON(api.NewDeletedPackages(deleted))
+}
+
+// deletePackages deletes all packages in a target matching the atom
+// query parameter, e.g., dev-lang/rust-*.
+func (s *Server) deletePackages(c fiber.Ctx) error {
+	t, err := s.lookupTarget(c, c.Params("target"))
+	if err != nil {
+		return err
+	}
+
+	pattern := c.Query("atom")
+	if pattern == "" {
+		return errInvalidRequest.withMessage("missing atom")
+	}
+
+	deleted, err := catalog.DeleteMatching(c.Context(), s.deps.DB, s.deps.Storage, s.deps.Log, t, pattern)
+	if err != nil {
+		if errors.Is(err, catalog.ErrInvalidPattern) {
+			return errInvalidRequest.withMessage(err.Error())
+		}
+
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(api.NewDeletedPackages(deleted))
+}
+
+// listTargetPackages lists the packages in a target.
+func (s *Server) listTargetPackages(c fiber.Ctx) error {
+	t, err := s.lookupTarget(c, c.Params("target"))
+	if err != nil {
+		return err
+	}
+
+	return s.searchPackages(c, []uuid.UUID{t.ID})
+}
+
+// listPackages lists the packages across all targets the token can
+// read, e.g., to find which targets contain a package.
+func (s *Server) listPackages(c fiber.Ctx) error {
+	var targetIDs []uuid.UUID
+	if p := principal(c); !p.Can(auth.ScopeTargetRead, uuid.Nil) {
+		targetIDs = p.TargetIDs
+	}
+
+	return s.searchPackages(c, targetIDs)
+}
+
+// searchPackages responds with the packages matching the filters in
+// the query parameters. Only packages in the provided targets are
+// returned, unless targetIDs is nil.
+func (s *Server) searchPackages(c fiber.Ctx, targetIDs []uuid.UUID) error {
+	q := &catalog.SearchQuery{
+		TargetIDs:  targetIDs,
+		Category:   c.Query("category"),
+		Name:       c.Query("name"),
+		Repository: c.Query("repository"),
+		Slot:       c.Query("slot"),
+		Keyword:    c.Query("keyword"),
+		Use:        c.Query("use"),
+		Cursor:     c.Query("cursor"),
+		Limit:      fiber.Query[int](c, "limit"),
+	}
+
+	for param, t := range map[string]*time.Time{"built_after": &q.BuiltAfter, "built_before": &q.BuiltBefore} {
+		if c.Query(param) == "" {
+			continue
+		}
+
+		var err error
+		*t, err = time.Parse(time.RFC3339, c.Query(param))
+		if err != nil {
+			return errInvalidRequest.withMessage("invalid " + param + ": " + err.Error())
+		}
+	}
+
+	res, err := catalog.Search(c.Context(), s.deps.DB, q)
+	if err != nil {
+		if errors.Is(err, catalog.ErrInvalidCursor) {
+			return errInvalidRequest.withMessage(err.Error())
+		}
+
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(api.NewListPackagesResponse(res))
+}
+
+// lookupTarget returns the target with the provided name, or
+// errTargetNotFound if it doesn't exist.
+func (s *Server) lookupTarget(c fiber.Ctx, name string) (*ent.Target, error) {
+	t, err := catalog.LookupTarget(c.Context(), s.deps.DB, name)
+	if err != nil {
+		if errors.Is(err, catalog.ErrTargetNotFound) {
+			return nil, errTargetNotFound
+		}
+
+		return nil, err
+	}
+
+	return t, nil
 }
 
 func (s *Server) getPackages(c fiber.Ctx) error {
-	return c.SendStatus(fiber.StatusNotImplemented)
+	targetName := c.Params("target")
+
+	t, err := s.lookupTarget(c, targetName)
+	if err != nil {
+		return err
+	}
+
+	// The index only changes when the packages (or profile) of the
+	// target do, so the time it was last changed identifies it.
+	etag := fmt.Sprintf(`"%x"`, t.IndexUpdatedAt.UnixNano())
+	setValidators(c, etag, t.IndexUpdatedAt)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	if notModified(c, etag, t.IndexUpdatedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	index, err := catalog.Index(c.Context(), s.deps.DB, t)
+	if err != nil {
+		return fmt.Errorf("failed to generate index: %w", err)
+	}
+
+	c.Type("txt", "utf-8")
+	return index.EncodeInto(c)
 }
 
 func (s *Server) getTargetPackageIndex(c fiber.Ctx) error {
-	return c.SendStatus(fiber.StatusNotImplemented)
+	targetName := c.Params("target")
+
+	t, err := s.lookupTarget(c, targetName)
+	if err != nil {
+		return err
+	}
+
+	p, err := s.deps.DB.Pkg.Query().
+		Where(pkg.TargetIDEQ(t.ID), pkg.PathEQ(c.Params("*"))).
+		First(c.Context())
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return errPackageNotFound
+		}
+
+		return fmt.Errorf("failed querying package: %w", err)
+	}
+
+	etag := `"` + p.Blake2b + `"`
+	setValidators(c, etag, p.Mtime)
+	if notModified(c, etag, p.Mtime) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return s.sendObject(c, p.ObjectKey, p.Size, catalog.ContentTypes[p.Format], etag, p.Mtime)
 }
 
 // Run starts the HTTP service activity. Blocks until the provided
 // context is cancelled.
 func (a *Activity) Run(ctx context.Context) error {
-	app := fiber.New(fiber.Config{StreamRequestBody: true})
-
-	app.Use(logger.New(logger.Config{
-		LoggerFunc: func(c fiber.Ctx, data *logger.Data, cfg logger.Config) error {
-			a.srv.deps.Log.Info("http request", "method", c.Method(), "path", c.OriginalURL(), "status", c.Response().StatusCode(), "duration", data.Stop.Sub(data.Start).String())
-			return nil
-		},
-	})).Name("logger")
-
-	app.Get("/v1/targets", a.srv.listTargets).Name("list targets")
-	app.Post("/v1/targets/:target", a.srv.createTarget).Name("create target")
-	app.Post("/v1/targets/:target/upload", a.srv.uploadPackage).Name("upload package")
-
-	// Gentoo Paths
-	app.Get("/t/:target/Packages", a.srv.getPackages)
-	app.Get("/t/:target/*", a.srv.getTargetPackageIndex)
+	app := a.srv.newApp()
 
 	// TODO(jaredallard): Make this configurable later.
 	return app.Listen("127.0.0.1:8080", fiber.ListenConfig{
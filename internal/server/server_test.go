@@ -0,0 +1,199 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/catalog"
+	"github.com/jaredallard/binhost/internal/config"
+	"github.com/jaredallard/binhost/internal/dbtest"
+	"github.com/jaredallard/binhost/internal/dpi"
+	"github.com/jaredallard/binhost/internal/ent"
+	"github.com/jaredallard/binhost/internal/packages"
+	"github.com/jaredallard/binhost/internal/packages/packagestest"
+	"github.com/jaredallard/binhost/internal/parser"
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+// testAdminToken is the admin token of servers created by
+// newTestServer.
+const testAdminToken = "admin"
+
+// newTestServer returns a server backed by an empty database and
+// filesystem storage, and its app.
+func newTestServer(t *testing.T) (*Server, *fiber.App) {
+	s := &Server{&dpi.Dependencies{
+		DB:      dbtest.Open(t),
+		Storage: storage.NewFS(t.TempDir()),
+		Conf:    &config.Config{AdminToken: testAdminToken},
+		Log:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}}
+	return s, s.newApp()
+}
+
+// newTestTarget creates a target with the provided name.
+func newTestTarget(t *testing.T, s *Server, name string, private bool) *ent.Target {
+	tgt, err := s.deps.DB.Target.Create().SetName(name).SetPrivate(private).Save(context.Background())
+	assert.NilError(t, err)
+	return tgt
+}
+
+// newTestXpak returns an XPAK package for the provided CPV with the
+// provided additional metadata.
+func newTestXpak(category, pf string, metadata map[string]string) []byte {
+	md := map[string]string{"CATEGORY": category + "\n", "PF": pf + "\n", "repository": "gentoo\n"}
+	for k, v := range metadata {
+		md[k] = v + "\n"
+	}
+	return packagestest.BuildXpak([]byte(category+"/"+pf), md)
+}
+
+// addTestPackage adds the provided package archive to the target.
+func addTestPackage(t *testing.T, s *Server, tgt *ent.Target, b []byte) *ent.Pkg {
+	binpkg, err := packages.New(bytes.NewReader(b))
+	assert.NilError(t, err)
+	defer binpkg.Delete() //nolint:errcheck // Why: Best effort.
+
+	p, err := catalog.AddPackage(context.Background(), s.deps.DB, s.deps.Storage, tgt, binpkg)
+	assert.NilError(t, err)
+	return p
+}
+
+// doRequest sends the provided request, authenticated with the provided
+// bearer token unless it's empty, to the app.
+func doRequest(t *testing.T, app *fiber.App, req *http.Request, token string) *http.Response {
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+
+	resp, err := app.Test(req)
+	assert.NilError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// decodeError decodes the error response in the body of resp.
+func decodeError(t *testing.T, resp *http.Response) api.ErrorBody {
+	var body api.ErrorResponse
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return body.Error
+}
+
+func TestListPackagesReturnsSnakeCaseMetadata(t *testing.T) {
+	s, app := newTestServer(t)
+	tgt := newTestTarget(t, s, "amd64", false)
+	addTestPackage(t, s, tgt, newTestXpak("app-misc", "foo-1.0", map[string]string{
+		"SLOT": "0", "KEYWORDS": "amd64 ~arm64", "BUILD_TIME": "1700000000",
+	}))
+
+	resp := doRequest(t, app, httptest.NewRequest(fiber.MethodGet, "/v1/targets/amd64/packages", nil), testAdminToken)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Packages []struct {
+			CPV      string         `json:"cpv"`
+			Metadata map[string]any `json:"metadata"`
+		} `json:"packages"`
+	}
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 1, len(body.Packages))
+	assert.Equal(t, "app-misc/foo-1.0", body.Packages[0].CPV)
+
+	md := body.Packages[0].Metadata
+	assert.Equal(t, "0", md["slot"])
+	assert.Equal(t, "1700000000", md["build_time"])
+	assert.DeepEqual(t, []any{"amd64", "~arm64"}, md["keywords"])
+	assert.Assert(t, md["Slot"] == nil)
+}
+
+func TestListPackagesRejectsInvalidCursor(t *testing.T) {
+	s, app := newTestServer(t)
+	newTestTarget(t, s, "amd64", false)
+
+	for _, path := range []string{"/v1/packages?cursor=invalid!", "/v1/targets/amd64/packages?cursor=bm90IGpzb24"} {
+		resp := doRequest(t, app, httptest.NewRequest(fiber.MethodGet, path, nil), testAdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, path)
+		assert.Equal(t, "invalid_request", decodeError(t, resp).Code, path)
+	}
+}
+
+// patchTarget sends a PATCH request with the provided JSON body for
+// the target with the provided name.
+func patchTarget(t *testing.T, app *fiber.App, name, body string) *http.Response {
+	req := httptest.NewRequest(fiber.MethodPatch, "/v1/targets/"+name, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return doRequest(t, app, req, testAdminToken)
+}
+
+func TestUpdateTargetOnlyChangesProvidedFields(t *testing.T) {
+	s, app := newTestServer(t)
+	tgt, err := s.deps.DB.Target.Create().
+		SetName("amd64").SetDescription("desktops").SetOwner("infra").SetPrivate(true).
+		Save(context.Background())
+	assert.NilError(t, err)
+
+	resp := patchTarget(t, app, "amd64", `{"owner":"builds"}`)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body api.Target
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "amd64", body.Name)
+	assert.Equal(t, "desktops", body.Description)
+	assert.Equal(t, "builds", body.Owner)
+	assert.Equal(t, true, body.Private)
+
+	tgt = s.deps.DB.Target.GetX(context.Background(), tgt.ID)
+	assert.Equal(t, "builds", tgt.Owner)
+	assert.Equal(t, "desktops", tgt.Description)
+}
+
+func TestUpdateTargetRejectsExistingName(t *testing.T) {
+	s, app := newTestServer(t)
+	amd64 := newTestTarget(t, s, "amd64", false)
+	newTestTarget(t, s, "arm64", false)
+
+	resp := patchTarget(t, app, "amd64", `{"name":"arm64"}`)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "target_exists", decodeError(t, resp).Code)
+
+	assert.Equal(t, "amd64", s.deps.DB.Target.GetX(context.Background(), amd64.ID).Name)
+
+	resp = patchTarget(t, app, "amd64", `{"name":""}`)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp = patchTarget(t, app, "amd64", `{"name":"x86_64"}`)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "x86_64", s.deps.DB.Target.GetX(context.Background(), amd64.ID).Name)
+}
+
+func TestUpdateTargetReplacesProfile(t *testing.T) {
+	s, app := newTestServer(t)
+	tgt, err := s.deps.DB.Target.Create().
+		SetName("amd64").
+		SetProfile(&parser.Profile{Arch: "amd64", AcceptKeywords: []string{"amd64"}}).
+		Save(context.Background())
+	assert.NilError(t, err)
+
+	resp := patchTarget(t, app, "amd64", `{"profile":{"CHOST":"x86_64-pc-linux-gnu"}}`)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// The profile is replaced as a whole rather than merged.
+	updated := s.deps.DB.Target.GetX(context.Background(), tgt.ID)
+	assert.DeepEqual(t, &parser.Profile{CHost: "x86_64-pc-linux-gnu"}, updated.Profile)
+	assert.Assert(t, updated.IndexUpdatedAt.After(tgt.IndexUpdatedAt))
+
+	// Other fields don't change the index.
+	resp = patchTarget(t, app, "amd64", `{"description":"desktops"}`)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, updated.IndexUpdatedAt, s.deps.DB.Target.GetX(context.Background(), tgt.ID).IndexUpdatedAt)
+}
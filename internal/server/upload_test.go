@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jaredallard/binhost/internal/api"
+	"github.com/jaredallard/binhost/internal/auth"
+	"github.com/jaredallard/binhost/internal/ent/target"
+	"gotest.tools/v3/assert"
+)
+
+// uploadByCHost uploads the provided package to /v1/upload.
+func uploadByCHost(t *testing.T, app *fiber.App, token string, b []byte) *http.Response {
+	return doRequest(t, app, httptest.NewRequest(fiber.MethodPost, "/v1/upload", bytes.NewReader(b)), token)
+}
+
+// targetExists returns true if a target with the provided name exists.
+func targetExists(t *testing.T, s *Server, name string) bool {
+	ok, err := s.deps.DB.Target.Query().Where(target.NameEQ(name)).Exist(context.Background())
+	assert.NilError(t, err)
+	return ok
+}
+
+func TestUploadByCHostRequiresCHost(t *testing.T) {
+	_, app := newTestServer(t)
+
+	resp := uploadByCHost(t, app, testAdminToken, newTestXpak("app-misc", "foo-1.0", nil))
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, "invalid_package", decodeError(t, resp).Code)
+}
+
+func TestUploadByCHostUsesExistingTarget(t *testing.T) {
+	s, app := newTestServer(t)
+	amd64 := newTestTarget(t, s, "x86_64-pc-linux-gnu", false)
+	tok := newTestToken(t, s, []auth.Scope{auth.ScopeTargetWrite}, amd64)
+
+	resp := uploadByCHost(t, app, tok, newTestXpak("app-misc", "foo-1.0", map[string]string{"CHOST": amd64.Name}))
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	var body api.UploadResponse
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, amd64.Name, body.Target)
+	assert.Equal(t, "app-misc/foo-1.0", body.CPV)
+}
+
+func TestUploadByCHostRejectsUnknownTarget(t *testing.T) {
+	s, app := newTestServer(t)
+	tok := newTestToken(t, s, []auth.Scope{auth.ScopeTargetWrite})
+
+	resp := uploadByCHost(t, app, tok, newTestXpak("app-misc", "foo-1.0", map[string]string{"CHOST": "aarch64-unknown-linux-gnu"}))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	body := decodeError(t, resp)
+	assert.Equal(t, "target_not_found", body.Code)
+	assert.DeepEqual(t, map[string]any{"target": "aarch64-unknown-linux-gnu"}, body.Details)
+	assert.Assert(t, !targetExists(t, s, "aarch64-unknown-linux-gnu"))
+}
+
+func TestUploadByCHostCreatesTarget(t *testing.T) {
+	s, app := newTestServer(t)
+	s.deps.Conf.AutoCreateTargets = true
+	tok := newTestToken(t, s, []auth.Scope{auth.ScopeTargetWrite})
+
+	resp := uploadByCHost(t, app, tok, newTestXpak("app-misc", "foo-1.0", map[string]string{"CHOST": "aarch64-unknown-linux-gnu"}))
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Assert(t, targetExists(t, s, "aarch64-unknown-linux-gnu"))
+}
+
+func TestUploadByCHostRefusesRestrictedTokens(t *testing.T) {
+	s, app := newTestServer(t)
+	s.deps.Conf.AutoCreateTargets = true
+	amd64 := newTestTarget(t, s, "x86_64-pc-linux-gnu", false)
+	newTestTarget(t, s, "riscv64-unknown-linux-gnu", false)
+	tok := newTestToken(t, s, []auth.Scope{auth.ScopeTargetWrite}, amd64)
+
+	// Whether the target exists or not, the response is the same so that
+	// the token can't be used to find out which targets exist.
+	for _, chost := range []string{"aarch64-unknown-linux-gnu", "riscv64-unknown-linux-gnu"} {
+		resp := uploadByCHost(t, app, tok, newTestXpak("app-misc", "foo-1.0", map[string]string{"CHOST": chost}))
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode, chost)
+		assert.Equal(t, "forbidden", decodeError(t, resp).Code, chost)
+	}
+	assert.Assert(t, !targetExists(t, s, "aarch64-unknown-linux-gnu"))
+}
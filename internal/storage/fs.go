@@ -0,0 +1,161 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// _ ensures that fsStorage implements the Storage interface.
+var _ Storage = (&fsStorage{})
+
+// fsStorage implements the Storage interface on top of a directory on
+// the local filesystem. Content types are not persisted.
+type fsStorage struct {
+	root string
+}
+
+// NewFS creates a new Storage that stores objects as files in the
+// provided directory. Keys are used as paths relative to it.
+func NewFS(root string) Storage {
+	return &fsStorage{root}
+}
+
+// path returns the path of the file that the object with the provided
+// key is stored in.
+func (s *fsStorage) path(key string) (string, error) {
+	// Keys are only ever built out of validated components, but make
+	// sure one can never refer to another object's directory.
+	if !filepath.IsLocal(key) || slices.Contains(strings.Split(key, "/"), "..") {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func (s *fsStorage) Put(_ context.Context, key string, r io.Reader, size int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for object %s: %w", key, err)
+	}
+
+	// Write to a temporary file first so that readers never see a
+	// partially written object.
+	f, err := os.CreateTemp(filepath.Dir(path), ".binhost-*")
+	if err != nil {
+		return fmt.Errorf("failed to create object %s: %w", key, err)
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck // Why: No-op after rename.
+	defer f.Close()
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return fmt.Errorf("failed to write object %s: %w", key, err)
+	}
+	if n != size {
+		return fmt.Errorf("failed to write object %s: expected %d bytes, got %d", key, size, n)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write object %s: %w", key, err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to store object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *fsStorage) Get(_ context.Context, key string) (*Object, error) {
+	f, size, err := s.open(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Object{ReadCloser: f, Size: size}, nil
+}
+
+func (s *fsStorage) GetRange(_ context.Context, key string, offset, length int64) (*Object, error) {
+	f, size, err := s.open(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset < 0 || length < 0 || offset+length > size {
+		_ = f.Close() //nolint:errcheck // Why: Best effort close.
+		return nil, fmt.Errorf("range %d+%d is outside of object %s (%d bytes)", offset, length, key, size)
+	}
+
+	return &Object{
+		ReadCloser: struct {
+			io.Reader
+			io.Closer
+		}{io.NewSectionReader(f, offset, length), f},
+		Size: length,
+	}, nil
+}
+
+// open opens the file of the object with the provided key, returning
+// its size.
+func (s *fsStorage) open(key string) (*os.File, int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, 0, ErrNotFound
+		}
+
+		return nil, 0, fmt.Errorf("failed to open object %s: %w", key, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close() //nolint:errcheck // Why: Best effort close.
+		return nil, 0, fmt.Errorf("failed to stat object %s: %w", key, err)
+	}
+
+	return f, info.Size(), nil
+}
+
+func (s *fsStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jaredallard/binhost/internal/storage"
+	"gotest.tools/v3/assert"
+)
+
+func TestFSStorage(t *testing.T) {
+	ctx := context.Background()
+	s := storage.NewFS(t.TempDir())
+
+	const contents = "hello, world!"
+	assert.NilError(t, s.Put(ctx, "target/app-misc/hello/hello-1.0-1.gpkg.tar", strings.NewReader(contents), int64(len(contents)), "application/x-tar"))
+
+	obj, err := s.Get(ctx, "target/app-misc/hello/hello-1.0-1.gpkg.tar")
+	assert.NilError(t, err)
+	b, err := io.ReadAll(obj)
+	assert.NilError(t, err)
+	assert.NilError(t, obj.Close())
+	assert.Equal(t, contents, string(b))
+	assert.Equal(t, int64(len(contents)), obj.Size)
+
+	obj, err = s.GetRange(ctx, "target/app-misc/hello/hello-1.0-1.gpkg.tar", 7, 5)
+	assert.NilError(t, err)
+	b, err = io.ReadAll(obj)
+	assert.NilError(t, err)
+	assert.NilError(t, obj.Close())
+	assert.Equal(t, "world", string(b))
+	assert.Equal(t, int64(5), obj.Size)
+
+	_, err = s.GetRange(ctx, "target/app-misc/hello/hello-1.0-1.gpkg.tar", 10, 10)
+	assert.ErrorContains(t, err, "outside of object")
+
+	assert.NilError(t, s.Delete(ctx, "target/app-misc/hello/hello-1.0-1.gpkg.tar"))
+	_, err = s.Get(ctx, "target/app-misc/hello/hello-1.0-1.gpkg.tar")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Deleting an object that doesn't exist isn't an error.
+	assert.NilError(t, s.Delete(ctx, "target/app-misc/hello/hello-1.0-1.gpkg.tar"))
+}
+
+func TestFSStorageRejectsShortWrites(t *testing.T) {
+	ctx := context.Background()
+	s := storage.NewFS(t.TempDir())
+
+	assert.ErrorContains(t, s.Put(ctx, "short", strings.NewReader("abc"), 10, ""), "expected 10 bytes, got 3")
+	_, err := s.Get(ctx, "short")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestFSStorageRejectsKeysOutsideRoot(t *testing.T) {
+	ctx := context.Background()
+	s := storage.NewFS(t.TempDir())
+
+	assert.ErrorContains(t, s.Put(ctx, "../escape", strings.NewReader("abc"), 3, ""), "invalid object key")
+	_, err := s.Get(ctx, "/etc/passwd")
+	assert.ErrorContains(t, err, "invalid object key")
+
+	// Keys that stay inside of the root but pass through another
+	// directory are rejected too.
+	assert.ErrorContains(t, s.Put(ctx, "a/../b/app-misc/foo", strings.NewReader("abc"), 3, ""), "invalid object key")
+	assert.ErrorContains(t, s.Delete(ctx, "a/../b/app-misc/foo"), "invalid object key")
+}
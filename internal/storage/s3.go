@@ -0,0 +1,99 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// _ ensures that s3Storage implements the Storage interface.
+var _ Storage = (&s3Storage{})
+
+// s3Storage implements the Storage interface on top of a S3 bucket.
+type s3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3 creates a new Storage that stores objects in the provided
+// bucket.
+func NewS3(client *minio.Client, bucket string) Storage {
+	return &s3Storage{client, bucket}
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if _, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	}); err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *s3Storage) Get(ctx context.Context, key string) (*Object, error) {
+	return s.get(ctx, key, minio.GetObjectOptions{})
+}
+
+func (s *s3Storage) GetRange(ctx context.Context, key string, offset, length int64) (*Object, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, fmt.Errorf("invalid range for object %s: %w", key, err)
+	}
+
+	obj, err := s.get(ctx, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stat returns the size of the whole object.
+	obj.Size = length
+	return obj, nil
+}
+
+// get returns the object stored under the provided key using the
+// provided options.
+func (s *s3Storage) get(ctx context.Context, key string, opts minio.GetObjectOptions) (*Object, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+
+	// GetObject is lazy, so stat the object to find out if it exists.
+	info, err := obj.Stat()
+	if err != nil {
+		_ = obj.Close() //nolint:errcheck // Why: Best effort close.
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to stat object %s: %w", key, err)
+	}
+
+	return &Object{ReadCloser: obj, Size: info.Size, ContentType: info.ContentType}, nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2024 Jared Allard
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package storage implements object storage for package archives
+// uploaded to the binhost.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// Storage is an object store that package archives are stored in.
+type Storage interface {
+	// Put stores the contents of r under the provided key. size is the
+	// number of bytes that will be read from r.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get returns the object stored under the provided key. The caller
+	// must close the returned object. Returns [ErrNotFound] if the
+	// object does not exist.
+	Get(ctx context.Context, key string) (*Object, error)
+
+	// GetRange is like Get, but only returns length bytes of the object
+	// starting at offset. The range must be within the object.
+	GetRange(ctx context.Context, key string, offset, length int64) (*Object, error)
+
+	// Delete removes the object stored under the provided key.
+	Delete(ctx context.Context, key string) error
+}
+
+// Object is an object read from a [Storage].
+type Object struct {
+	io.ReadCloser
+
+	// Size is the size of the object in bytes. For objects returned by
+	// GetRange, this is the size of the range.
+	Size int64
+
+	// ContentType is the content type the object was stored with.
+	ContentType string
+}